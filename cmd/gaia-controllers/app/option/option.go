@@ -4,6 +4,7 @@ import (
 	"fmt"
 	clusterapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
 	"github.com/lmxia/gaia/pkg/common"
+	"github.com/lmxia/gaia/pkg/controllers/apps/rebalancer"
 	"github.com/spf13/pflag"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apiserveroptions "k8s.io/apiserver/pkg/server/options"
@@ -25,6 +26,7 @@ type Options struct {
 	NetworkBindUrl      string
 	ClusterRegistration *ClusterRegistrationOptions
 	ManagedCluster      *clusterapi.ManagedClusterOptions
+	Rebalance           *rebalancer.Policy
 
 	SecureServing  *apiserveroptions.SecureServingOptionsWithLoopback
 	Authentication *apiserveroptions.DelegatingAuthenticationOptions
@@ -49,6 +51,18 @@ func (opts *Options) AddFlags(fs *pflag.FlagSet) {
 		"The base url of the synccontroller service.")
 	fs.BoolVar(&opts.ManagedCluster.UseHypernodeController, "useHypernodeController", opts.ManagedCluster.UseHypernodeController,
 		"Whether use hypernode controller, default value is false.")
+	fs.StringVar(&opts.Rebalance.Mode, "rebalance-mode", opts.Rebalance.Mode,
+		"How replicas are rebalanced across child clusters, one of Off, Propose and Apply.")
+	fs.DurationVar(&opts.Rebalance.Period, "rebalance-period", opts.Rebalance.Period,
+		"How often the utilization of the child clusters is evaluated.")
+	fs.Float64Var(&opts.Rebalance.MaxImbalance, "rebalance-max-imbalance", opts.Rebalance.MaxImbalance,
+		"The difference of utilization, between 0 and 1, of the most and the least utilized child clusters that is tolerated.")
+	fs.Int32Var(&opts.Rebalance.MaxMovesPerPeriod, "rebalance-max-moves", opts.Rebalance.MaxMovesPerPeriod,
+		"The most replicas moved in a rebalance period.")
+	fs.Float64Var(&opts.Rebalance.MaxDisruption, "rebalance-max-disruption", opts.Rebalance.MaxDisruption,
+		"The fraction of the replicas of a component moved at once.")
+	fs.DurationVar(&opts.Rebalance.Cooldown, "rebalance-cooldown", opts.Rebalance.Cooldown,
+		"How long a description is left alone after its replicas were moved.")
 }
 
 // NewOptions creates a new *options with sane defaults
@@ -56,6 +70,7 @@ func NewOptions() *Options {
 	o := &Options{
 		ClusterRegistration: NewClusterRegistrationOptions(),
 		ManagedCluster:      clusterapi.NewManagedClusterOptions(),
+		Rebalance:           rebalancer.NewPolicy(),
 
 		SecureServing:  apiserveroptions.NewSecureServingOptions().WithLoopback(),
 		Authentication: apiserveroptions.NewDelegatingAuthenticationOptions(),
@@ -145,6 +160,24 @@ func (opts *Options) Validate() []error {
 	mcErrs := opts.ManagedCluster.Validate()
 	allErrs = append(allErrs, mcErrs...)
 
+	switch opts.Rebalance.Mode {
+	case rebalancer.ModeOff, rebalancer.ModePropose, rebalancer.ModeApply:
+	default:
+		allErrs = append(allErrs, fmt.Errorf("invalid value for --rebalance-mode: %q", opts.Rebalance.Mode))
+	}
+	if opts.Rebalance.Period <= 0 {
+		allErrs = append(allErrs, fmt.Errorf("--rebalance-period must be greater than 0"))
+	}
+	if opts.Rebalance.MaxImbalance < 0 || opts.Rebalance.MaxImbalance > 1 {
+		allErrs = append(allErrs, fmt.Errorf("--rebalance-max-imbalance must be between 0 and 1"))
+	}
+	if opts.Rebalance.MaxMovesPerPeriod < 0 {
+		allErrs = append(allErrs, fmt.Errorf("--rebalance-max-moves must not be negative"))
+	}
+	if opts.Rebalance.MaxDisruption < 0 || opts.Rebalance.MaxDisruption > 1 {
+		allErrs = append(allErrs, fmt.Errorf("--rebalance-max-disruption must be between 0 and 1"))
+	}
+
 	return allErrs
 }
 
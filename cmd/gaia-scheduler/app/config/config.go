@@ -3,10 +3,15 @@ package config
 import (
 	apiserver "k8s.io/apiserver/pkg/server"
 	restclient "k8s.io/client-go/rest"
+
+	schedulerapis "github.com/lmxia/gaia/pkg/scheduler/apis"
 )
 
 // Config has all the context to run a Scheduler
 type Config struct {
+	// ComponentConfig is the scheduler server's configuration object.
+	ComponentConfig schedulerapis.SchedulerConfiguration
+
 	// LoopbackClientConfig is a config for a privileged loopback connection
 	LoopbackClientConfig *restclient.Config
 
@@ -0,0 +1,39 @@
+package option
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	schedulerapis "github.com/lmxia/gaia/pkg/scheduler/apis"
+	schedulerapisv1alpha1 "github.com/lmxia/gaia/pkg/scheduler/apis/v1alpha1"
+)
+
+// loadConfigFromFile reads a versioned scheduler configuration, an empty file name means the default configuration.
+func loadConfigFromFile(file string) (*schedulerapis.SchedulerConfiguration, error) {
+	versioned := &schedulerapisv1alpha1.GaiaSchedulerConfiguration{}
+	if len(file) > 0 {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err = yaml.UnmarshalStrict(data, versioned); err != nil {
+			return nil, fmt.Errorf("failed to decode scheduler configuration %q: %v", file, err)
+		}
+		if versioned.APIVersion != schedulerapisv1alpha1.SchemeGroupVersion.String() ||
+			versioned.Kind != schedulerapisv1alpha1.Kind {
+			return nil, fmt.Errorf("scheduler configuration %q must be %s %s, got %s %s", file,
+				schedulerapisv1alpha1.SchemeGroupVersion.String(), schedulerapisv1alpha1.Kind,
+				versioned.APIVersion, versioned.Kind)
+		}
+	}
+	schedulerapisv1alpha1.SetDefaultsGaiaSchedulerConfiguration(versioned)
+
+	cfg := &schedulerapis.SchedulerConfiguration{}
+	schedulerapisv1alpha1.Convert_v1alpha1_GaiaSchedulerConfiguration_To_apis_SchedulerConfiguration(versioned, cfg)
+	if err := schedulerapis.ValidateSchedulerConfiguration(cfg); err != nil {
+		return nil, fmt.Errorf("invalid scheduler configuration %q: %v", file, err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,80 @@
+package option
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	schedulerapis "github.com/lmxia/gaia/pkg/scheduler/apis"
+	"github.com/lmxia/gaia/pkg/scheduler/framework/plugins/names"
+)
+
+type ConfigFileSuite struct {
+	suite.Suite
+}
+
+func (suite *ConfigFileSuite) write(content string) string {
+	file := filepath.Join(suite.T().TempDir(), "config.yaml")
+	suite.NoError(os.WriteFile(file, []byte(content), 0600))
+	return file
+}
+
+func (suite *ConfigFileSuite) TestDefaultConfig() {
+	cfg, err := loadConfigFromFile("")
+	suite.NoError(err)
+	suite.Len(cfg.Profiles, 1)
+	suite.Equal(schedulerapis.DefaultSchedulerName, cfg.Profiles[0].SchedulerName)
+	suite.Equal(schedulerapis.DefaultWorkers, cfg.Workers)
+}
+
+func (suite *ConfigFileSuite) TestLoadProfiles() {
+	cfg, err := loadConfigFromFile(suite.write(`
+apiVersion: scheduler.gaia.io/v1alpha1
+kind: GaiaSchedulerConfiguration
+workers: 4
+profiles:
+- schedulerName: edge-scheduler
+  plugins:
+    score:
+      enabled:
+      - name: CorePriority
+        weight: 3
+      - name: DeploymentCondition
+      disabled:
+      - name: VirtualNode
+- schedulerName: default-scheduler
+`))
+	suite.NoError(err)
+	suite.Equal(int32(4), cfg.Workers)
+	suite.Len(cfg.Profiles, 2)
+	suite.Equal("edge-scheduler", cfg.Profiles[0].SchedulerName)
+	suite.Equal([]schedulerapis.Plugin{
+		{Name: names.CorePriority, Weight: 3},
+		{Name: names.DeploymentCondition, Weight: 1},
+	}, cfg.Profiles[0].Plugins.Score.Enabled, "a score plugin without a weight counts once")
+	suite.Equal(schedulerapis.DefaultSchedulerName, cfg.Profiles[1].SchedulerName)
+}
+
+func (suite *ConfigFileSuite) TestInvalidConfig() {
+	_, err := loadConfigFromFile(suite.write(`
+apiVersion: v1
+kind: ConfigMap
+`))
+	suite.Error(err, "only scheduler configurations are loaded")
+
+	_, err = loadConfigFromFile(suite.write(`
+apiVersion: scheduler.gaia.io/v1alpha1
+kind: GaiaSchedulerConfiguration
+unknown: true
+`))
+	suite.Error(err, "unknown fields are rejected")
+
+	_, err = loadConfigFromFile(filepath.Join(suite.T().TempDir(), "missing.yaml"))
+	suite.Error(err)
+}
+
+func TestConfigFileSuite(t *testing.T) {
+	suite.Run(t, new(ConfigFileSuite))
+}
@@ -14,6 +14,8 @@ import (
 // ClusterRegistrationOptions holds the command-line options for command
 type Options struct {
 	Kubeconfig string
+	// ConfigFile is the location of the scheduler's configuration file.
+	ConfigFile string
 
 	SecureServing  *apiserveroptions.SecureServingOptionsWithLoopback
 	Authentication *apiserveroptions.DelegatingAuthenticationOptions
@@ -28,6 +30,8 @@ type Options struct {
 func (opts *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&opts.Kubeconfig, "kubeconfig", opts.Kubeconfig,
 		"Path to a kubeconfig file for current child cluster. Only required if out-of-cluster")
+	fs.StringVar(&opts.ConfigFile, "config", opts.ConfigFile,
+		"Path to a GaiaSchedulerConfiguration file with scheduling profiles. Default profile is used if empty")
 }
 
 // NewOptions creates a new *options with sane defaults
@@ -109,5 +113,11 @@ func (o *Options) ApplyTo(c *schedulerappconfig.Config) error {
 		}
 	}
 	o.Metrics.Apply()
+
+	cfg, err := loadConfigFromFile(o.ConfigFile)
+	if err != nil {
+		return err
+	}
+	c.ComponentConfig = *cfg
 	return nil
 }
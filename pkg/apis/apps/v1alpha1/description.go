@@ -1,6 +1,8 @@
 package v1alpha1
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	serveringv1 "knative.dev/serving/pkg/apis/serving/v1"
@@ -28,8 +30,22 @@ type Description struct {
 type DescriptionSpec struct {
 	// +required
 	AppID string `json:"appID,omitempty"` // appID是蓝图的id
+	// Preoccupy reserves the capacity the components are scheduled on for a duration, e.g. 10m, or until
+	// their workloads run if that's earlier. Nothing is reserved if it's empty.
 	// +optional
 	Preoccupy string `json:"preoccupy,omitempty"`
+	// Priority is the scheduling priority of the description. A description that can't be scheduled
+	// may preempt scheduled descriptions with a lower priority. The higher the value, the higher the priority.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+	// SchedulerName is the name of the gaia-scheduler profile this description is scheduled with.
+	// If not specified, the description will be scheduled by the "default-scheduler" profile.
+	// +optional
+	SchedulerName string `json:"schedulerName,omitempty"`
+	// GangScheduling places all components of the description at the same time or none of them.
+	// A child cluster that can't place its share fails the description in every cluster.
+	// +optional
+	GangScheduling *GangScheduling `json:"gangScheduling,omitempty"`
 	// +optional
 	// +kubebuilder:validation:Optional
 	Components []Component `json:"components,omitempty"`
@@ -44,6 +60,16 @@ type DescriptionSpec struct {
 	ExpectedPerformance ExpectedPerformance `json:"expectedPerformance,omitempty"`
 }
 
+// GangScheduling is the all-or-nothing scheduling policy of a description.
+type GangScheduling struct {
+	// MinReplicas is the least replicas of a deployment component the description can run with, keyed by
+	// component name. A component that is not listed needs all its replicas.
+	// +optional
+	MinReplicas map[string]int32 `json:"minReplicas,omitempty"`
+}
+
+// SandboxType is the runtime sandbox a workload component runs in. Every sandbox but runc, the default
+// runtime of the nodes, runs on the RuntimeClass named after it.
 type SandboxType string
 
 const (
@@ -58,10 +84,13 @@ type WorkloadComponent struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Type=string
 	ComponentName string `json:"componentName,omitempty"`
+	// Sandbox is the runtime sandbox of the component, the component is only scheduled to the clusters
+	// that run it.
 	// +required
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Type=string
 	Sandbox SandboxType `json:"sandbox,omitempty"`
+	// Preoccupy overrides the preoccupy of the description for the component, 0 reserves nothing.
 	// +optional
 	Preoccupy string `json:"preoccupy,omitempty"`
 	// +optional
@@ -82,6 +111,40 @@ type Xject struct {
 	Type string `json:"type,omitempty"`
 }
 
+// Types of the subject and the object of a deployment condition.
+const (
+	// XjectTypeComponent refers to the component named Name.
+	XjectTypeComponent = "component"
+	// XjectTypeCluster refers to the names of the clusters.
+	XjectTypeCluster = "cluster"
+	// XjectTypeGeoLocation refers to the geolocations of the clusters.
+	XjectTypeGeoLocation = "geolocation"
+	// XjectTypeNetEnvironment refers to the net environments of the clusters.
+	XjectTypeNetEnvironment = "netenvironment"
+	// XjectTypeProvider refers to the providers of the clusters.
+	XjectTypeProvider = "provider"
+	// XjectTypeResForm refers to the resource forms of the clusters.
+	XjectTypeResForm = "resform"
+	// XjectTypeLabel refers to the value of the cluster label whose key is Name.
+	XjectTypeLabel = "label"
+)
+
+// Relations between the subject and the object of a deployment condition.
+const (
+	// RelationIn places the subject component in clusters whose object is one of the extent values.
+	RelationIn = "in"
+	// RelationNotIn places the subject component in clusters whose object is none of the extent values.
+	RelationNotIn = "notIn"
+	// RelationSameAs places the subject component only in clusters of the object component.
+	RelationSameAs = "sameAs"
+	// RelationNotSameAs keeps the subject component out of the clusters of the object component.
+	RelationNotSameAs = "notSameAs"
+	// RelationNear places the subject component only in geolocations of the object component.
+	RelationNear = "near"
+)
+
+// Condition is a placement rule of the subject component. The object is either another component, with
+// relation sameAs, notSameAs or near, or an attribute of the clusters, with relation in or notIn.
 type Condition struct {
 	// +required
 	// +kubebuilder:validation:Required
@@ -93,18 +156,36 @@ type Condition struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Type=string
 	Relation string `json:"relation,omitempty"`
+	// Extent holds the values of relation in and notIn.
 	// +required
 	// +kubebuilder:validation:Required
-	Extent []byte `json:"extent,omitempty"`
+	Extent []string `json:"extent,omitempty"`
 }
 
 type DeploymentCondition struct {
+	// Mandatory conditions must all hold, clusters or plans breaking any of them are filtered out.
 	// +optional
 	Mandatory []Condition `json:"mandatory,omitempty"`
+	// BestEffort conditions are preferred, the more a plan satisfies the higher it scores.
 	// +optional
 	BestEffort []Condition `json:"BestEffort,omitempty"`
 }
 
+// Types of inter boundaries, they tighten the sla between the subject component and the components it talks to.
+const (
+	// BoundaryTypeDelay is the most delay in milliseconds.
+	BoundaryTypeDelay = "delay"
+	// BoundaryTypeLost is the most packet loss.
+	BoundaryTypeLost = "lost"
+	// BoundaryTypeJitter is the most jitter in milliseconds.
+	BoundaryTypeJitter = "jitter"
+	// BoundaryTypeBandwidth is the least bandwidth.
+	BoundaryTypeBandwidth = "bandwidth"
+)
+
+// Boundary is a performance expectation of the subject component. The type of an inner boundary is a resource
+// name, like cpu or memory, and the value is the least amount one replica gets. The type of an inter boundary
+// is one of delay, lost, jitter and bandwidth. Extra boundaries are kept for other systems.
 type Boundary struct {
 	// +required
 	// +kubebuilder:validation:Required
@@ -115,9 +196,10 @@ type Boundary struct {
 	// +required
 	// +kubebuilder:validation:Required
 	Type string `json:"type,omitempty"`
+	// Value is a number, or a quantity like 500m or 1Gi.
 	// +required
 	// +kubebuilder:validation:Required
-	Value []byte `json:"value,omitempty"`
+	Value string `json:"value,omitempty"`
 }
 
 type Boundaries struct {
@@ -132,10 +214,61 @@ type Boundaries struct {
 	Extra []Boundary `json:"extra,omitempty"`
 }
 
+// BoundaryState tells whether a boundary is met.
+type BoundaryState string
+
+const (
+	// BoundaryMet means the description is placed with the boundary enforced.
+	BoundaryMet BoundaryState = "Met"
+	// BoundaryUnmet means the boundary is enforced but the description is not placed.
+	BoundaryUnmet BoundaryState = "Unmet"
+	// BoundaryUnknown means the scheduler can't enforce the boundary.
+	BoundaryUnknown BoundaryState = "Unknown"
+)
+
+// BoundaryStatus is the state of a boundary in the latest scheduling attempt.
+type BoundaryStatus struct {
+	// Name is the name of the boundary.
+	Name string `json:"name"`
+	// Subject is the subject component of the boundary.
+	// +optional
+	Subject string `json:"subject,omitempty"`
+	// Type is the type of the boundary.
+	// +optional
+	Type string `json:"type,omitempty"`
+	// State tells whether the boundary is met.
+	State BoundaryState `json:"state"`
+	// Message explains the state.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// Strategy types of an HPA.
+const (
+	// XPAStrategyTargetValue scales the subject component in proportion, so that its trigger, an average over
+	// the replicas, stays at the value of the strategy.
+	XPAStrategyTargetValue = "targetValue"
+	// XPAStrategyStep adds step replicas while the trigger is above the value of the strategy, and removes
+	// step replicas while the rest of the replicas would still keep the trigger below it.
+	XPAStrategyStep = "step"
+)
+
+// Strategy types of a VPA.
+const (
+	// XPAStrategyRecommend records the recommended requests of the subject component in the status of the
+	// description.
+	XPAStrategyRecommend = "recommend"
+	// XPAStrategyAuto records the recommended requests and rolls them out to the template of the subject component.
+	XPAStrategyAuto = "auto"
+)
+
 type XPAStrategy struct {
 	// +required
 	// +kubebuilder:validation:Required
 	Type string `json:"type,omitempty"`
+	// Value is the target of the trigger of an HPA, a number. The threshold of the serverless trait of the
+	// subject component is used if it's empty. Of a VPA, it's the headroom in percent added to the usage,
+	// 15 if empty.
 	// +required
 	// +kubebuilder:validation:Required
 	Value []byte `json:"value,omitempty"`
@@ -149,6 +282,9 @@ type XPA struct {
 	// +required
 	// +kubebuilder:validation:Required
 	Subject string `json:"subject,omitempty"`
+	// Trigger is the prometheus query of the metric the subject component is scaled on. The query of a VPA
+	// returns the usage of a replica in every cluster, with the resource in the resource label, cpu in cores
+	// and memory in bytes.
 	// +required
 	// +kubebuilder:validation:Required
 	Trigger string `json:"trigger,omitempty"`
@@ -256,10 +392,14 @@ type TraitUserAPP struct {
 	SN string `json:"sn,omitempty"`
 }
 
+// TraitServerless bounds the horizontal autoscaling of a component.
 type TraitServerless struct {
-	MiniInstancenumber int32  `json:"miniInstancenumber,omitempty"`
-	Step               int32  `json:"step,omitempty"`
-	Threshold          string `json:"threshold,omitempty"`
+	// MiniInstancenumber is the least replicas the component is scaled to.
+	MiniInstancenumber int32 `json:"miniInstancenumber,omitempty"`
+	// Step is the replicas an HPA with the step strategy adds or removes at a time, 1 if not set.
+	Step int32 `json:"step,omitempty"`
+	// Threshold is the target of the HPA triggers of the component that don't have one.
+	Threshold string `json:"threshold,omitempty"`
 }
 
 type TraitAffinityDaemon struct {
@@ -275,18 +415,170 @@ type SchedulePolicy struct {
 	GeoLocation *metav1.LabelSelector `json:"geolocation,omitempty"`
 	// +optional
 	Provider *metav1.LabelSelector `json:"provider,omitempty"`
+	// Affinity places the component relative to the other components of the description.
+	// +optional
+	Affinity *ComponentAffinity `json:"affinity,omitempty"`
+}
+
+// ComponentTopologyKey is the topology domain that component affinity is evaluated on.
+type ComponentTopologyKey string
+
+const (
+	// ComponentTopologyCluster means each cluster is a domain of its own.
+	ComponentTopologyCluster ComponentTopologyKey = "cluster"
+	// ComponentTopologyGeoLocation means clusters with the same geolocation are in one domain.
+	ComponentTopologyGeoLocation ComponentTopologyKey = "geolocation"
+)
+
+// ComponentAffinity is a group of inter-component affinity rules.
+type ComponentAffinity struct {
+	// Affinity requires every cluster with replicas of this component to share a domain
+	// with a cluster that has replicas of the other components.
+	// +optional
+	Affinity []ComponentAffinityTerm `json:"affinity,omitempty"`
+	// AntiAffinity requires no cluster with replicas of this component to share a domain
+	// with a cluster that has replicas of the other components.
+	// +optional
+	AntiAffinity []ComponentAffinityTerm `json:"antiAffinity,omitempty"`
+}
+
+// ComponentAffinityTerm refers to other components of the description in a topology domain.
+type ComponentAffinityTerm struct {
+	// Components are the names of the other components of the description.
+	// +required
+	Components []string `json:"components"`
+	// TopologyKey is the topology domain the term is evaluated on, "cluster" by default.
+	// +optional
+	// +kubebuilder:validation:Enum=cluster;geolocation
+	TopologyKey ComponentTopologyKey `json:"topologyKey,omitempty"`
 }
 
 // DescriptionStatus defines the observed state of Description
 type DescriptionStatus struct {
 	// Phase denotes the phase of Description
 	// +optional
-	// +kubebuilder:validation:Enum=Pending;Scheduled;Failure
+	// +kubebuilder:validation:Enum=Pending;Scheduled;Failure;ReSchedule
 	Phase DescriptionPhase `json:"phase,omitempty"`
 
 	// Reason indicates the reason of DescriptionPhase
 	// +optional
 	Reason string `json:"reason,omitempty"`
+
+	// NominatedClusters are the clusters on which lower priority descriptions were preempted
+	// to make room for this description.
+	// +optional
+	NominatedClusters []string `json:"nominatedClusters,omitempty"`
+
+	// GangFailedClusters are the child clusters that failed to place their share of this generation of a gang
+	// description, they are left out of the next attempts to schedule it.
+	// +optional
+	GangFailedClusters []string `json:"gangFailedClusters,omitempty"`
+
+	// ObservedGeneration is the most recent generation of the Description observed by the scheduler.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest observations of the Description's scheduling state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ComponentDiagnoses records, per component, the clusters rejected by filter plugins
+	// in the latest scheduling attempt.
+	// +optional
+	ComponentDiagnoses []ComponentDiagnosis `json:"componentDiagnoses,omitempty"`
+
+	// NetworkFilteredResourceBindings are the candidate ResourceBindings dropped by the
+	// network filter in the latest scheduling attempt.
+	// +optional
+	NetworkFilteredResourceBindings []FilteredResourceBinding `json:"networkFilteredResourceBindings,omitempty"`
+
+	// Boundaries reports whether each boundary of the expected performance is met
+	// by the latest scheduling attempt.
+	// +optional
+	Boundaries []BoundaryStatus `json:"boundaries,omitempty"`
+
+	// Recommendations are the requests the VPAs recommend for their subject components.
+	// +optional
+	Recommendations []ResourceRecommendation `json:"recommendations,omitempty"`
+
+	// Moves are the moves of replicas between child clusters the rebalancer made or proposed
+	// in its latest evaluation of the utilization of the clusters.
+	// +optional
+	Moves []ReplicaMove `json:"moves,omitempty"`
+}
+
+// ResourceRecommendation is the requests a VPA recommends for each replica of its subject component.
+type ResourceRecommendation struct {
+	// Name is the name of the VPA.
+	Name string `json:"name"`
+	// Component is the subject component of the VPA.
+	Component string `json:"component"`
+	// Requests are the recommended requests of a replica.
+	// +optional
+	Requests corev1.ResourceList `json:"requests,omitempty"`
+	// Applied is true if the requests are rolled out to the template of the component.
+	// +optional
+	Applied bool `json:"applied,omitempty"`
+}
+
+// ReplicaMove is a move of replicas of a component from one child cluster to another, which evens out
+// the utilization of the clusters.
+type ReplicaMove struct {
+	// Component is the name of the component.
+	Component string `json:"component"`
+	// From is the name of the cluster the replicas move out of.
+	From string `json:"from"`
+	// To is the name of the cluster the replicas move to.
+	To string `json:"to"`
+	// Replicas is the number of replicas moved.
+	Replicas int32 `json:"replicas"`
+	// Applied is true if the move is applied to the selected resource binding, otherwise it is
+	// only proposed.
+	// +optional
+	Applied bool `json:"applied,omitempty"`
+}
+
+const (
+	// DescriptionConditionScheduled represents the result of the latest scheduling attempt of a Description.
+	DescriptionConditionScheduled = "Scheduled"
+)
+
+// ComponentDiagnosis records why clusters were rejected for a component.
+type ComponentDiagnosis struct {
+	// Name of the component.
+	Name string `json:"name"`
+
+	// RejectedClusters are the clusters that failed the filter plugins.
+	// +optional
+	RejectedClusters []ClusterRejection `json:"rejectedClusters,omitempty"`
+}
+
+// ClusterRejection records the filter plugin that rejected a cluster and why.
+type ClusterRejection struct {
+	// Cluster is the namespaced name of the rejected ManagedCluster.
+	Cluster string `json:"cluster"`
+
+	// Plugin is the name of the filter plugin that rejected the cluster.
+	// +optional
+	Plugin string `json:"plugin,omitempty"`
+
+	// Reasons are the messages returned by the plugin.
+	// +optional
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// FilteredResourceBinding is a candidate ResourceBinding dropped by the network filter.
+type FilteredResourceBinding struct {
+	// Index is the position of the candidate among the ResourceBindings spawned in the scheduling attempt.
+	Index int32 `json:"index"`
+
+	// RbApps is the placement of the candidate.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	RbApps []*ResourceBindingApps `json:"rbApps,omitempty"`
 }
 
 type DescriptionPhase string
@@ -307,3 +599,34 @@ type DescriptionList struct {
 	metav1.ListMeta `json:"metadata,omitempty"`
 	Items           []Description `json:"items"`
 }
+
+// SandboxOf returns the runtime sandbox of component name, empty if it has no workload component.
+func (desc *Description) SandboxOf(name string) SandboxType {
+	for _, comp := range desc.Spec.WorkloadComponents {
+		if comp.ComponentName == name {
+			return comp.Sandbox
+		}
+	}
+	return ""
+}
+
+// PreoccupyDuration returns how long the capacity of component name is reserved, the preoccupy of its workload
+// component if set, otherwise the preoccupy of the description. Zero means nothing is reserved, as does a
+// preoccupy that isn't a duration.
+func (desc *Description) PreoccupyDuration(name string) time.Duration {
+	preoccupy := desc.Spec.Preoccupy
+	for _, comp := range desc.Spec.WorkloadComponents {
+		if comp.ComponentName == name && len(comp.Preoccupy) > 0 {
+			preoccupy = comp.Preoccupy
+			break
+		}
+	}
+	if len(preoccupy) == 0 {
+		return 0
+	}
+	duration, err := time.ParseDuration(preoccupy)
+	if err != nil || duration < 0 {
+		return 0
+	}
+	return duration
+}
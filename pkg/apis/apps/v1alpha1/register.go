@@ -52,6 +52,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&NetworkRequirementList{},
 		&ResourceBinding{},
 		&ResourceBindingList{},
+		&SchedulingSimulation{},
+		&SchedulingSimulationList{},
 		&UserAPP{},
 		&UserAPPList{},
 	)
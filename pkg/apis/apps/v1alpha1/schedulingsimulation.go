@@ -0,0 +1,105 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Important: Run "make generated" to regenerate code after modifying this file
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope="Namespaced",shortName=simu,categories=gaia
+// +kubebuilder:printcolumn:name="STATUS",type=string,JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// SchedulingSimulation asks gaia-scheduler where a Description would be placed, without creating any
+// ResourceBinding or Description in the clusters.
+type SchedulingSimulation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SchedulingSimulationSpec   `json:"spec"`
+	Status SchedulingSimulationStatus `json:"status,omitempty"`
+}
+
+// SchedulingSimulationSpec defines the spec of SchedulingSimulation
+type SchedulingSimulationSpec struct {
+	// Description is the spec of the Description to simulate.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	Description DescriptionSpec `json:"description"`
+
+	// NetworkRequirement is the spec of the NetworkRequirement of the Description, the network filter
+	// is skipped if it is not set.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	NetworkRequirement *NetworkRequirementSpec `json:"networkRequirement,omitempty"`
+}
+
+type SimulationPhase string
+
+const (
+	SimulationPhaseSucceeded SimulationPhase = "Succeeded"
+	SimulationPhaseFailed    SimulationPhase = "Failed"
+)
+
+// SchedulingSimulationStatus defines the observed state of SchedulingSimulation
+type SchedulingSimulationStatus struct {
+	// Phase denotes the phase of SchedulingSimulation
+	// +optional
+	// +kubebuilder:validation:Enum=Succeeded;Failed
+	Phase SimulationPhase `json:"phase,omitempty"`
+
+	// Reason indicates why the simulation failed
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// ObservedGeneration is the generation of the SchedulingSimulation the status is computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ComponentDiagnoses records, per component, the clusters rejected by filter plugins.
+	// +optional
+	ComponentDiagnoses []ComponentDiagnosis `json:"componentDiagnoses,omitempty"`
+
+	// Candidates are the ResourceBindings left by the network filter, with their scores.
+	// +optional
+	Candidates []SimulatedResourceBinding `json:"candidates,omitempty"`
+}
+
+// SimulatedResourceBinding is a candidate ResourceBinding of a simulation.
+type SimulatedResourceBinding struct {
+	// RbApps is the placement of the candidate.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	RbApps []*ResourceBindingApps `json:"rbApps,omitempty"`
+
+	// NetworkPath is the network path selected for the candidate by the network filter.
+	// +optional
+	NetworkPath [][]byte `json:"networkPath,omitempty"`
+
+	// Score is the sum of PluginScores.
+	// +optional
+	Score int64 `json:"score,omitempty"`
+
+	// PluginScores is the score given by each score plugin.
+	// +optional
+	PluginScores map[string]int64 `json:"pluginScores,omitempty"`
+
+	// Selected is true if the scheduler would publish the candidate.
+	// +optional
+	Selected bool `json:"selected,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SchedulingSimulationList contains a list of SchedulingSimulation
+type SchedulingSimulationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SchedulingSimulation `json:"items"`
+}
@@ -60,6 +60,43 @@ func (in *Attributes) DeepCopy() *Attributes {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BoundaryStatus) DeepCopyInto(out *BoundaryStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BoundaryStatus.
+func (in *BoundaryStatus) DeepCopy() *BoundaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BoundaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterRejection) DeepCopyInto(out *ClusterRejection) {
+	*out = *in
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRejection.
+func (in *ClusterRejection) DeepCopy() *ClusterRejection {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterRejection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Component) DeepCopyInto(out *Component) {
 	*out = *in
@@ -87,13 +124,87 @@ func (in *Component) DeepCopy() *Component {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentAffinity) DeepCopyInto(out *ComponentAffinity) {
+	*out = *in
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = make([]ComponentAffinityTerm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AntiAffinity != nil {
+		in, out := &in.AntiAffinity, &out.AntiAffinity
+		*out = make([]ComponentAffinityTerm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentAffinity.
+func (in *ComponentAffinity) DeepCopy() *ComponentAffinity {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentAffinity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentAffinityTerm) DeepCopyInto(out *ComponentAffinityTerm) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentAffinityTerm.
+func (in *ComponentAffinityTerm) DeepCopy() *ComponentAffinityTerm {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentAffinityTerm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentDiagnosis) DeepCopyInto(out *ComponentDiagnosis) {
+	*out = *in
+	if in.RejectedClusters != nil {
+		in, out := &in.RejectedClusters, &out.RejectedClusters
+		*out = make([]ClusterRejection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentDiagnosis.
+func (in *ComponentDiagnosis) DeepCopy() *ComponentDiagnosis {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentDiagnosis)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Description) DeepCopyInto(out *Description) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -151,6 +262,11 @@ func (in *DescriptionList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DescriptionSpec) DeepCopyInto(out *DescriptionSpec) {
 	*out = *in
+	if in.GangScheduling != nil {
+		in, out := &in.GangScheduling, &out.GangScheduling
+		*out = new(GangScheduling)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Components != nil {
 		in, out := &in.Components, &out.Components
 		*out = make([]Component, len(*in))
@@ -174,6 +290,54 @@ func (in *DescriptionSpec) DeepCopy() *DescriptionSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DescriptionStatus) DeepCopyInto(out *DescriptionStatus) {
 	*out = *in
+	if in.NominatedClusters != nil {
+		in, out := &in.NominatedClusters, &out.NominatedClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GangFailedClusters != nil {
+		in, out := &in.GangFailedClusters, &out.GangFailedClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ComponentDiagnoses != nil {
+		in, out := &in.ComponentDiagnoses, &out.ComponentDiagnoses
+		*out = make([]ComponentDiagnosis, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NetworkFilteredResourceBindings != nil {
+		in, out := &in.NetworkFilteredResourceBindings, &out.NetworkFilteredResourceBindings
+		*out = make([]FilteredResourceBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Boundaries != nil {
+		in, out := &in.Boundaries, &out.Boundaries
+		*out = make([]BoundaryStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Recommendations != nil {
+		in, out := &in.Recommendations, &out.Recommendations
+		*out = make([]ResourceRecommendation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Moves != nil {
+		in, out := &in.Moves, &out.Moves
+		*out = make([]ReplicaMove, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -208,6 +372,56 @@ func (in *Direction) DeepCopy() *Direction {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilteredResourceBinding) DeepCopyInto(out *FilteredResourceBinding) {
+	*out = *in
+	if in.RbApps != nil {
+		in, out := &in.RbApps, &out.RbApps
+		*out = make([]*ResourceBindingApps, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ResourceBindingApps)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilteredResourceBinding.
+func (in *FilteredResourceBinding) DeepCopy() *FilteredResourceBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(FilteredResourceBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GangScheduling) DeepCopyInto(out *GangScheduling) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GangScheduling.
+func (in *GangScheduling) DeepCopy() *GangScheduling {
+	if in == nil {
+		return nil
+	}
+	out := new(GangScheduling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InterSCNID) DeepCopyInto(out *InterSCNID) {
 	*out = *in
@@ -360,6 +574,22 @@ func (in *NetworkRequirementStatus) DeepCopy() *NetworkRequirementStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaMove) DeepCopyInto(out *ReplicaMove) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaMove.
+func (in *ReplicaMove) DeepCopy() *ReplicaMove {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaMove)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceBinding) DeepCopyInto(out *ResourceBinding) {
 	*out = *in
@@ -509,6 +739,29 @@ func (in *ResourceBindingStatus) DeepCopy() *ResourceBindingStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRecommendation) DeepCopyInto(out *ResourceRecommendation) {
+	*out = *in
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRecommendation.
+func (in *ResourceRecommendation) DeepCopy() *ResourceRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RevisionSpec) DeepCopyInto(out *RevisionSpec) {
 	*out = *in
@@ -558,6 +811,11 @@ func (in *SchedulePolicy) DeepCopyInto(out *SchedulePolicy) {
 		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(ComponentAffinity)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -571,6 +829,119 @@ func (in *SchedulePolicy) DeepCopy() *SchedulePolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingSimulation) DeepCopyInto(out *SchedulingSimulation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingSimulation.
+func (in *SchedulingSimulation) DeepCopy() *SchedulingSimulation {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingSimulation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SchedulingSimulation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingSimulationList) DeepCopyInto(out *SchedulingSimulationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SchedulingSimulation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingSimulationList.
+func (in *SchedulingSimulationList) DeepCopy() *SchedulingSimulationList {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingSimulationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SchedulingSimulationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingSimulationSpec) DeepCopyInto(out *SchedulingSimulationSpec) {
+	*out = *in
+	in.Description.DeepCopyInto(&out.Description)
+	if in.NetworkRequirement != nil {
+		in, out := &in.NetworkRequirement, &out.NetworkRequirement
+		*out = new(NetworkRequirementSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingSimulationSpec.
+func (in *SchedulingSimulationSpec) DeepCopy() *SchedulingSimulationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingSimulationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingSimulationStatus) DeepCopyInto(out *SchedulingSimulationStatus) {
+	*out = *in
+	if in.ComponentDiagnoses != nil {
+		in, out := &in.ComponentDiagnoses, &out.ComponentDiagnoses
+		*out = make([]ComponentDiagnosis, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Candidates != nil {
+		in, out := &in.Candidates, &out.Candidates
+		*out = make([]SimulatedResourceBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingSimulationStatus.
+func (in *SchedulingSimulationStatus) DeepCopy() *SchedulingSimulationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingSimulationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServerlessSpec) DeepCopyInto(out *ServerlessSpec) {
 	*out = *in
@@ -595,6 +966,51 @@ func (in *ServerlessSpec) DeepCopy() *ServerlessSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SimulatedResourceBinding) DeepCopyInto(out *SimulatedResourceBinding) {
+	*out = *in
+	if in.RbApps != nil {
+		in, out := &in.RbApps, &out.RbApps
+		*out = make([]*ResourceBindingApps, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ResourceBindingApps)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.NetworkPath != nil {
+		in, out := &in.NetworkPath, &out.NetworkPath
+		*out = make([][]byte, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make([]byte, len(*in))
+				copy(*out, *in)
+			}
+		}
+	}
+	if in.PluginScores != nil {
+		in, out := &in.PluginScores, &out.PluginScores
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SimulatedResourceBinding.
+func (in *SimulatedResourceBinding) DeepCopy() *SimulatedResourceBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(SimulatedResourceBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TraitAffinityDaemon) DeepCopyInto(out *TraitAffinityDaemon) {
 	*out = *in
@@ -122,6 +122,21 @@ type ManagedClusterStatus struct {
 	// +optional
 	Available corev1.ResourceList `json:"available,omitempty"`
 
+	// MaxNodeAvailable is the largest Available resources of a single node in the cluster, taken for every
+	// resource on its own. A replica requesting more than this fits on no node of the cluster.
+	// +optional
+	MaxNodeAvailable corev1.ResourceList `json:"maxNodeAvailable,omitempty"`
+
+	// ClusterResources is the capacity distribution of a non-leaf cluster, one entry for every leaf cluster
+	// under it. The aggregated resources above can't tell twenty tiny edge clusters from one big cluster.
+	// +optional
+	ClusterResources []ClusterResource `json:"clusterResources,omitempty"`
+
+	// Sandboxes is the runtime sandboxes the cluster runs workloads in, runc and the sandboxes it has a
+	// RuntimeClass named after. A non-leaf cluster runs the sandboxes of all its child clusters.
+	// +optional
+	Sandboxes []string `json:"sandboxes,omitempty"`
+
 	// ClusterCIDR is the CIDR range of the cluster
 	// +optional
 	ClusterCIDR string `json:"clusterCIDR,omitempty"`
@@ -177,6 +192,20 @@ type ManagedClusterList struct {
 	Items           []ManagedCluster `json:"items"`
 }
 
+// ClusterResource is the resource summary of one leaf cluster.
+type ClusterResource struct {
+	// Name is the name of the leaf cluster
+	Name string `json:"name"`
+
+	// Available is the sum of Available resources for nodes in the leaf cluster
+	// +optional
+	Available corev1.ResourceList `json:"available,omitempty"`
+
+	// MaxNodeAvailable is the largest Available resources of a single node in the leaf cluster
+	// +optional
+	MaxNodeAvailable corev1.ResourceList `json:"maxNodeAvailable,omitempty"`
+}
+
 type NodeStatistics struct {
 	// ReadyNodes is the number of ready nodes in the cluster
 	// +optional
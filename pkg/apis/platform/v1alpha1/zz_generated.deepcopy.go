@@ -142,6 +142,36 @@ func (in *ClusterRegistrationRequestStatus) DeepCopy() *ClusterRegistrationReque
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResource) DeepCopyInto(out *ClusterResource) {
+	*out = *in
+	if in.Available != nil {
+		in, out := &in.Available, &out.Available
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.MaxNodeAvailable != nil {
+		in, out := &in.MaxNodeAvailable, &out.MaxNodeAvailable
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResource.
+func (in *ClusterResource) DeepCopy() *ClusterResource {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Fields) DeepCopyInto(out *Fields) {
 	*out = *in
@@ -288,6 +318,25 @@ func (in *ManagedClusterStatus) DeepCopyInto(out *ManagedClusterStatus) {
 			(*out)[key] = val.DeepCopy()
 		}
 	}
+	if in.MaxNodeAvailable != nil {
+		in, out := &in.MaxNodeAvailable, &out.MaxNodeAvailable
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.ClusterResources != nil {
+		in, out := &in.ClusterResources, &out.ClusterResources
+		*out = make([]ClusterResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Sandboxes != nil {
+		in, out := &in.Sandboxes, &out.Sandboxes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	out.NodeStatistics = in.NodeStatistics
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
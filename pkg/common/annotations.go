@@ -4,4 +4,18 @@ package common
 const (
 	// AutoUpdateAnnotation is the name of an annotation which prevents reconciliation if set to "false"
 	AutoUpdateAnnotation = "gaia.io/autoupdate"
+
+	// PreoccupyUntilAnnotation is the name of an annotation on the workloads of preoccupying components, which
+	// holds the time in RFC3339 until the capacity of the replicas that don't run yet is reserved.
+	PreoccupyUntilAnnotation = "apps.gaia.io/preoccupy-until"
+
+	// DescriptionGenerationAnnotation is the name of an annotation on the ResourceBindings and description copies
+	// published into the namespaces of child clusters, which holds the generation of the description they were
+	// scheduled from.
+	DescriptionGenerationAnnotation = "apps.gaia.io/description-generation"
+
+	// DescriptionPlanAnnotation is the name of an annotation on the description copies published into the
+	// namespaces of child clusters, which holds a hash of the ResourceBindings they were published with, so a
+	// new plan of the same generation is published again.
+	DescriptionPlanAnnotation = "apps.gaia.io/description-plan"
 )
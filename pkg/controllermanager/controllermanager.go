@@ -23,6 +23,8 @@ import (
 	known "github.com/lmxia/gaia/pkg/common"
 	"github.com/lmxia/gaia/pkg/controllermanager/approver"
 	"github.com/lmxia/gaia/pkg/controllermanager/metrics"
+	"github.com/lmxia/gaia/pkg/controllers/apps/autoscaler"
+	"github.com/lmxia/gaia/pkg/controllers/apps/rebalancer"
 	"github.com/lmxia/gaia/pkg/controllers/apps/resourcebinding"
 	gaiaclientset "github.com/lmxia/gaia/pkg/generated/clientset/versioned"
 	gaiainformers "github.com/lmxia/gaia/pkg/generated/informers/externalversions"
@@ -74,6 +76,8 @@ type ControllerManager struct {
 	crrApprover         *approver.CRRApprover
 	rbController        *resourcebinding.RBController
 	rbMerger            *resourcebinding.RBMerger
+	autoscaler          *autoscaler.Controller
+	rebalancer          *rebalancer.Controller
 	gaiaInformerFactory gaiainformers.SharedInformerFactory
 	kubeInformerFactory kubeinformers.SharedInformerFactory
 	triggerFunc         func(metav1.Object)
@@ -139,6 +143,8 @@ func NewControllerManager(ctx context.Context, childKubeConfigFile, clusterHostN
 	if err != nil {
 		klog.Error(err)
 	}
+	autoscalerController := autoscaler.NewController(localGaiaClientSet, localGaiaInformerFactory, managedCluster.PrometheusMonitorUrlPrefix, autoscaler.DefaultPeriod)
+	rebalancerController := rebalancer.NewController(localGaiaClientSet, localGaiaInformerFactory, *opts.Rebalance)
 
 	agent := &ControllerManager{
 		ctx:                 ctx,
@@ -151,6 +157,8 @@ func NewControllerManager(ctx context.Context, childKubeConfigFile, clusterHostN
 		crrApprover:         approver,
 		rbController:        rbController,
 		rbMerger:            rbMerger,
+		autoscaler:          autoscalerController,
+		rebalancer:          rebalancerController,
 		statusManager:       statusManager,
 	}
 
@@ -217,6 +225,18 @@ func (controller *ControllerManager) Run(cc *gaiaconfig.CompletedConfig) {
 					controller.rbMerger.RunToParentResourceBindingMerger(common.DefaultThreadiness, ctx.Done())
 				}()
 
+				// 8. start autoscaler
+				go func() {
+					klog.Info("start 8. start autoscaler...")
+					controller.autoscaler.Run(ctx)
+				}()
+
+				// 9. start rebalancer
+				go func() {
+					klog.Info("start 9. start rebalancer...")
+					controller.rebalancer.Run(ctx)
+				}()
+
 				// metrics
 				if cc.SecureServing != nil {
 					handler := buildHandlerChain(newMetricsHandler(), cc.Authentication.Authenticator, cc.Authorization.Authorizer)
@@ -0,0 +1,360 @@
+package autoscaler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/prometheus/client_golang/api"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+
+	appsapi "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	platformapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+	"github.com/lmxia/gaia/pkg/common"
+	gaiaclientset "github.com/lmxia/gaia/pkg/generated/clientset/versioned"
+	gaiainformers "github.com/lmxia/gaia/pkg/generated/informers/externalversions"
+	applisters "github.com/lmxia/gaia/pkg/generated/listers/apps/v1alpha1"
+	platformlisters "github.com/lmxia/gaia/pkg/generated/listers/platform/v1alpha1"
+)
+
+const (
+	// DefaultPeriod is how often the hpa and vpa triggers are evaluated.
+	DefaultPeriod = 30 * time.Second
+	// scaleDownStabilization is how long a component is kept from scaling down after it has been scaled,
+	// and from rolling out requests after they have been rolled out.
+	scaleDownStabilization = 5 * time.Minute
+)
+
+// Controller scales the components of the descriptions scheduled by this cluster across clusters. It evaluates
+// the hpa triggers against prometheus and spreads the desired replicas over the clusters of the selected
+// resource binding, which the child clusters apply on update. It evaluates the vpa triggers to recommend the
+// requests of the components, and rolls them out to the component templates if the vpa is auto.
+type Controller struct {
+	gaiaClient gaiaclientset.Interface
+	descLister applisters.DescriptionLister
+	descSynced cache.InformerSynced
+	rbLister   applisters.ResourceBindingLister
+	rbSynced   cache.InformerSynced
+	mclLister  platformlisters.ManagedClusterLister
+	mclSynced  cache.InformerSynced
+	period     time.Duration
+	// query evaluates a trigger.
+	query func(ctx context.Context, trigger string) (model.Value, error)
+
+	lock sync.Mutex
+	// lastScaled is when each component was scaled last, keyed by description and component.
+	lastScaled map[string]time.Time
+	// lastResized is when the requests of each component were rolled out last, keyed by description and component.
+	lastResized map[string]time.Time
+}
+
+// NewController returns an autoscaler querying the prometheus at promUrlPrefix every period.
+func NewController(gaiaClient gaiaclientset.Interface, gaiaInformerFactory gaiainformers.SharedInformerFactory, promUrlPrefix string, period time.Duration) *Controller {
+	if len(promUrlPrefix) == 0 {
+		promUrlPrefix = common.PrometheusUrlPrefix
+	}
+	descInformer := gaiaInformerFactory.Apps().V1alpha1().Descriptions()
+	rbInformer := gaiaInformerFactory.Apps().V1alpha1().ResourceBindings()
+	mclInformer := gaiaInformerFactory.Platform().V1alpha1().ManagedClusters()
+	return &Controller{
+		gaiaClient: gaiaClient,
+		descLister: descInformer.Lister(),
+		descSynced: descInformer.Informer().HasSynced,
+		rbLister:   rbInformer.Lister(),
+		rbSynced:   rbInformer.Informer().HasSynced,
+		mclLister:  mclInformer.Lister(),
+		mclSynced:  mclInformer.Informer().HasSynced,
+		period:     period,
+		query: func(ctx context.Context, trigger string) (model.Value, error) {
+			return queryPrometheus(ctx, promUrlPrefix, trigger)
+		},
+		lastScaled:  make(map[string]time.Time),
+		lastResized: make(map[string]time.Time),
+	}
+}
+
+// Run evaluates the hpa and vpa triggers periodically until ctx is done.
+func (c *Controller) Run(ctx context.Context) {
+	klog.Info("starting autoscaler ...")
+	defer klog.Info("shutting down autoscaler")
+
+	if !cache.WaitForNamedCacheSync("autoscaler", ctx.Done(), c.descSynced, c.rbSynced, c.mclSynced) {
+		return
+	}
+	wait.UntilWithContext(ctx, c.syncAll, c.period)
+}
+
+// syncAll scales and resizes the descriptions in the reserved namespace that have hpa or vpa.
+func (c *Controller) syncAll(ctx context.Context) {
+	descs, err := c.descLister.Descriptions(common.GaiaReservedNamespace).List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Failed to list descriptions to autoscale")
+		return
+	}
+	for _, desc := range descs {
+		maitenance := desc.Spec.ExpectedPerformance.Maitenance
+		if desc.DeletionTimestamp != nil || (len(maitenance.HPA) == 0 && len(maitenance.VPA) == 0) {
+			continue
+		}
+		rb, err := c.selectedResourceBinding(desc.Name)
+		if err != nil {
+			klog.ErrorS(err, "Failed to get selected resource binding", "description", klog.KObj(desc))
+			continue
+		}
+		if rb == nil {
+			klog.V(5).InfoS("Description has no selected resource binding to autoscale", "description", klog.KObj(desc))
+			continue
+		}
+		if len(maitenance.HPA) > 0 {
+			if err = c.scale(ctx, desc, rb); err != nil {
+				klog.ErrorS(err, "Failed to autoscale description", "description", klog.KObj(desc))
+			}
+		}
+		if len(maitenance.VPA) > 0 {
+			if err = c.resize(ctx, desc, rb); err != nil {
+				klog.ErrorS(err, "Failed to right-size description", "description", klog.KObj(desc))
+			}
+		}
+	}
+}
+
+// selectedResourceBinding returns the selected resource binding of description descName, or nil if there is none.
+func (c *Controller) selectedResourceBinding(descName string) (*appsapi.ResourceBinding, error) {
+	rbs, err := c.rbLister.ResourceBindings(common.GaiaRBMergedReservedNamespace).List(labels.SelectorFromSet(labels.Set{
+		common.GaiaDescriptionLabel: descName,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	for _, rb := range rbs {
+		if rb.DeletionTimestamp == nil && rb.Spec.StatusScheduler == appsapi.ResourceBindingSelected {
+			return rb, nil
+		}
+	}
+	return nil, nil
+}
+
+// scale evaluates the hpa of desc and updates rb with the desired replicas of every subject component.
+func (c *Controller) scale(ctx context.Context, desc *appsapi.Description, rb *appsapi.ResourceBinding) error {
+	rb = rb.DeepCopy()
+	var scaled []string
+	for _, hpa := range desc.Spec.ExpectedPerformance.Maitenance.HPA {
+		comp := findComponent(desc, hpa.Subject)
+		if comp == nil || (comp.Workload.Workloadtype != appsapi.WorkloadTypeDeployment &&
+			comp.Workload.Workloadtype != appsapi.WorkloadTypeServerless) {
+			klog.V(4).InfoS("Ignoring hpa without a scalable subject", "description", klog.KObj(desc), "hpa", hpa.Name, "subject", hpa.Subject)
+			continue
+		}
+		current := totalReplicas(rb.Spec.RbApps, comp.Name)
+		if current == 0 {
+			continue
+		}
+		metric, err := c.queryValue(ctx, hpa.Trigger)
+		if err != nil {
+			klog.ErrorS(err, "Failed to evaluate hpa trigger", "description", klog.KObj(desc), "hpa", hpa.Name)
+			continue
+		}
+		desired, err := desiredReplicas(current, metric, hpa.Strategy, comp.Workload.TraitServerless)
+		if err != nil {
+			klog.ErrorS(err, "Invalid hpa", "description", klog.KObj(desc), "hpa", hpa.Name)
+			continue
+		}
+		if desired == current || (desired < current && c.stabilizing(c.lastScaled, desc.Name, comp.Name)) {
+			continue
+		}
+		klog.InfoS("Scaling component across clusters", "description", klog.KObj(desc), "component", comp.Name,
+			"hpa", hpa.Name, "metric", metric, "replicas", current, "desired", desired)
+		rescale(rb.Spec.RbApps, comp.Name, desired)
+		scaled = append(scaled, comp.Name)
+	}
+	if len(scaled) == 0 {
+		return nil
+	}
+
+	if _, err := c.gaiaClient.AppsV1alpha1().ResourceBindings(rb.Namespace).Update(ctx, rb, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	c.record(c.lastScaled, desc.Name, scaled)
+	return nil
+}
+
+// resize evaluates the vpa of desc and records the recommended requests of every subject component in the status
+// of desc. The requests of an auto vpa are rolled out to the template of the component, and the copies of desc
+// in the clusters that can't hold them any more are scheduled again.
+func (c *Controller) resize(ctx context.Context, desc *appsapi.Description, rb *appsapi.ResourceBinding) error {
+	desc = desc.DeepCopy()
+	clusters, err := c.clustersByName()
+	if err != nil {
+		return err
+	}
+	var recommendations []appsapi.ResourceRecommendation
+	var resized []string
+	reschedule := sets.NewString()
+	for _, vpa := range desc.Spec.ExpectedPerformance.Maitenance.VPA {
+		comp := findComponent(desc, vpa.Subject)
+		if comp == nil {
+			klog.V(4).InfoS("Ignoring vpa without subject", "description", klog.KObj(desc), "vpa", vpa.Name, "subject", vpa.Subject)
+			continue
+		}
+		if vpa.Strategy.Type != "" && vpa.Strategy.Type != appsapi.XPAStrategyRecommend && vpa.Strategy.Type != appsapi.XPAStrategyAuto {
+			klog.V(4).InfoS("Ignoring vpa with unknown strategy", "description", klog.KObj(desc), "vpa", vpa.Name, "strategy", vpa.Strategy.Type)
+			continue
+		}
+		usage, err := c.query(ctx, vpa.Trigger)
+		if err != nil {
+			klog.ErrorS(err, "Failed to evaluate vpa trigger", "description", klog.KObj(desc), "vpa", vpa.Name)
+			continue
+		}
+		vector, ok := usage.(model.Vector)
+		if !ok {
+			klog.ErrorS(nil, "Vpa trigger doesn't return a vector", "description", klog.KObj(desc), "vpa", vpa.Name, "type", usage.Type())
+			continue
+		}
+		requests, err := recommendRequests(vector, vpa.Strategy)
+		if err != nil {
+			klog.ErrorS(err, "Invalid vpa", "description", klog.KObj(desc), "vpa", vpa.Name)
+			continue
+		}
+
+		recommendation := appsapi.ResourceRecommendation{Name: vpa.Name, Component: comp.Name, Requests: requests}
+		current := podRequests(comp.Module)
+		recommendation.Applied = !differs(current, requests)
+		if vpa.Strategy.Type == appsapi.XPAStrategyAuto && !recommendation.Applied && !c.stabilizing(c.lastResized, desc.Name, comp.Name) {
+			klog.InfoS("Rolling out recommended requests", "description", klog.KObj(desc), "component", comp.Name,
+				"vpa", vpa.Name, "requests", requests)
+			for _, cluster := range unfitClusters(rb.Spec.RbApps, clusters, comp.Name, current, requests) {
+				reschedule.Insert(cluster.Namespace)
+			}
+			applyRequests(&comp.Module, requests)
+			recommendation.Applied = true
+			resized = append(resized, comp.Name)
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+
+	if len(resized) > 0 {
+		updated, err := c.gaiaClient.AppsV1alpha1().Descriptions(desc.Namespace).Update(ctx, desc, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		c.record(c.lastResized, desc.Name, resized)
+		desc.ObjectMeta = updated.ObjectMeta
+	}
+	if !apiequality.Semantic.DeepEqual(desc.Status.Recommendations, recommendations) {
+		desc.Status.Recommendations = recommendations
+		if _, err = c.gaiaClient.AppsV1alpha1().Descriptions(desc.Namespace).UpdateStatus(ctx, desc, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	for _, namespace := range reschedule.List() {
+		if err = c.reschedule(ctx, namespace, desc.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reschedule schedules the copy of description name in the namespace of a child cluster again, within the clusters
+// of the child cluster.
+func (c *Controller) reschedule(ctx context.Context, namespace, name string) error {
+	desc, err := c.descLister.Descriptions(namespace).Get(name)
+	if err != nil {
+		return err
+	}
+	if desc.Status.Phase == appsapi.DescriptionPhaseReSchedule {
+		return nil
+	}
+	klog.InfoS("Scheduling description again for the requests it doesn't fit", "description", klog.KObj(desc))
+	desc = desc.DeepCopy()
+	desc.Status.Phase = appsapi.DescriptionPhaseReSchedule
+	_, err = c.gaiaClient.AppsV1alpha1().Descriptions(namespace).UpdateStatus(ctx, desc, metav1.UpdateOptions{})
+	return err
+}
+
+func (c *Controller) clustersByName() (map[string]*platformapi.ManagedCluster, error) {
+	mcls, err := c.mclLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*platformapi.ManagedCluster, len(mcls))
+	for _, mcl := range mcls {
+		result[mcl.Name] = mcl
+	}
+	return result, nil
+}
+
+// record records now in last for components comps of description descName.
+func (c *Controller) record(last map[string]time.Time, descName string, comps []string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, comp := range comps {
+		last[descName+"/"+comp] = time.Now()
+	}
+}
+
+// stabilizing returns true if component comp of description descName has been recorded in last recently.
+func (c *Controller) stabilizing(last map[string]time.Time, descName, comp string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	at, ok := last[descName+"/"+comp]
+	return ok && time.Since(at) < scaleDownStabilization
+}
+
+func findComponent(desc *appsapi.Description, name string) *appsapi.Component {
+	for i := range desc.Spec.Components {
+		if desc.Spec.Components[i].Name == name {
+			return &desc.Spec.Components[i]
+		}
+	}
+	return nil
+}
+
+// queryValue evaluates trigger to a single value, the first sample of a vector or a scalar.
+func (c *Controller) queryValue(ctx context.Context, trigger string) (float64, error) {
+	result, err := c.query(ctx, trigger)
+	if err != nil {
+		return 0, err
+	}
+	switch value := result.(type) {
+	case model.Vector:
+		if len(value) == 0 {
+			return 0, fmt.Errorf("query %q returns no sample", trigger)
+		}
+		return float64(value[0].Value), nil
+	case *model.Scalar:
+		return float64(value.Value), nil
+	default:
+		return 0, fmt.Errorf("query %q returns %s instead of a vector or a scalar", trigger, result.Type())
+	}
+}
+
+// queryPrometheus evaluates query against the prometheus at promUrlPrefix.
+func queryPrometheus(ctx context.Context, promUrlPrefix, query string) (model.Value, error) {
+	client, err := api.NewClient(api.Config{
+		Address: promUrlPrefix,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	result, warnings, err := prometheusv1.NewAPI(client).Query(ctx, query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if len(warnings) > 0 {
+		klog.Warningf("Warnings: %v", warnings)
+	}
+	return result, nil
+}
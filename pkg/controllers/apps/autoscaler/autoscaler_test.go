@@ -0,0 +1,222 @@
+package autoscaler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/suite"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	appsapi "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	platformapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+	"github.com/lmxia/gaia/pkg/common"
+	"github.com/lmxia/gaia/pkg/generated/clientset/versioned/fake"
+	gaiainformers "github.com/lmxia/gaia/pkg/generated/informers/externalversions"
+)
+
+type AutoscalerSuite struct {
+	desc *appsapi.Description
+	rb   *appsapi.ResourceBinding
+	suite.Suite
+}
+
+func (suite *AutoscalerSuite) SetupTest() {
+	suite.desc = &appsapi.Description{
+		ObjectMeta: metav1.ObjectMeta{Name: "desc", Namespace: common.GaiaReservedNamespace},
+		Spec: appsapi.DescriptionSpec{
+			Components: []appsapi.Component{{
+				Name: "web",
+				Workload: appsapi.Workload{
+					Workloadtype:    appsapi.WorkloadTypeDeployment,
+					TraitServerless: &appsapi.TraitServerless{MiniInstancenumber: 2, Threshold: "50"},
+				},
+				Module: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+					{Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("300m")},
+						Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+					}},
+					{Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+					}},
+				}}},
+			}},
+			ExpectedPerformance: appsapi.ExpectedPerformance{Maitenance: appsapi.Maitenance{
+				HPA: []appsapi.XPA{{Name: "cpu", Subject: "web", Trigger: "cpu_usage"}},
+			}},
+		},
+	}
+	suite.rb = &appsapi.ResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "desc-rb", Namespace: common.GaiaRBMergedReservedNamespace},
+		Spec: appsapi.ResourceBindingSpec{
+			StatusScheduler: appsapi.ResourceBindingSelected,
+			RbApps: []*appsapi.ResourceBindingApps{
+				{ClusterName: "field1", Replicas: map[string]int32{"web": 3}, Children: []*appsapi.ResourceBindingApps{
+					{ClusterName: "c1", Replicas: map[string]int32{"web": 1}},
+					{ClusterName: "c2", Replicas: map[string]int32{"web": 2}},
+				}},
+				{ClusterName: "field2", Replicas: map[string]int32{"web": 1}, Children: []*appsapi.ResourceBindingApps{
+					{ClusterName: "c3", Replicas: map[string]int32{"web": 1}},
+					{ClusterName: "c4", Replicas: map[string]int32{"web": 0}},
+				}},
+			},
+		},
+	}
+}
+
+func (suite *AutoscalerSuite) newController(result model.Value, objects ...runtime.Object) *Controller {
+	client := fake.NewSimpleClientset(append(objects, suite.rb)...)
+	factory := gaiainformers.NewSharedInformerFactory(client, 0)
+	for _, object := range objects {
+		switch object := object.(type) {
+		case *appsapi.Description:
+			suite.NoError(factory.Apps().V1alpha1().Descriptions().Informer().GetStore().Add(object))
+		case *platformapi.ManagedCluster:
+			suite.NoError(factory.Platform().V1alpha1().ManagedClusters().Informer().GetStore().Add(object))
+		}
+	}
+	controller := NewController(client, factory, "", DefaultPeriod)
+	controller.query = func(ctx context.Context, trigger string) (model.Value, error) {
+		return result, nil
+	}
+	return controller
+}
+
+func usage(cpu ...float64) model.Vector {
+	var result model.Vector
+	for _, value := range cpu {
+		result = append(result, &model.Sample{Metric: model.Metric{resourceLabel: "cpu"}, Value: model.SampleValue(value)})
+	}
+	return result
+}
+
+func (suite *AutoscalerSuite) TestDesiredReplicas() {
+	targetValue := appsapi.XPAStrategy{Type: appsapi.XPAStrategyTargetValue, Value: []byte("50")}
+	desired, err := desiredReplicas(4, 75, targetValue, nil)
+	suite.NoError(err)
+	suite.Equal(int32(6), desired)
+	desired, _ = desiredReplicas(4, 53, targetValue, nil)
+	suite.Equal(int32(4), desired, "within the tolerance")
+	desired, _ = desiredReplicas(4, 500, targetValue, nil)
+	suite.Equal(int32(8), desired, "doubles at most")
+	desired, _ = desiredReplicas(4, 1, targetValue, &appsapi.TraitServerless{MiniInstancenumber: 2})
+	suite.Equal(int32(2), desired, "the least replicas")
+
+	step := appsapi.XPAStrategy{Type: appsapi.XPAStrategyStep}
+	traits := &appsapi.TraitServerless{Step: 2, Threshold: "50"}
+	desired, _ = desiredReplicas(4, 60, step, traits)
+	suite.Equal(int32(6), desired)
+	desired, _ = desiredReplicas(4, 30, step, traits)
+	suite.Equal(int32(4), desired, "2 replicas can't keep the trigger below the target")
+	desired, _ = desiredReplicas(4, 20, step, traits)
+	suite.Equal(int32(2), desired)
+
+	_, err = desiredReplicas(4, 60, appsapi.XPAStrategy{Type: appsapi.XPAStrategyStep}, nil)
+	suite.Error(err, "no target value")
+	_, err = desiredReplicas(4, 60, appsapi.XPAStrategy{Type: "unknown", Value: []byte("1")}, nil)
+	suite.Error(err)
+}
+
+func (suite *AutoscalerSuite) TestDistribute() {
+	suite.Equal([]int32{3, 3, 2}, distribute(8, []int32{1, 1, 1}))
+	suite.Equal([]int32{2, 0, 4}, distribute(6, []int32{1, 0, 2}))
+	suite.Equal([]int32{0, 0}, distribute(5, []int32{0, 0}))
+}
+
+func (suite *AutoscalerSuite) TestScale() {
+	controller := suite.newController(&model.Scalar{Value: 100})
+	suite.NoError(controller.scale(context.TODO(), suite.desc, suite.rb))
+
+	rb, err := controller.gaiaClient.AppsV1alpha1().ResourceBindings(suite.rb.Namespace).Get(context.TODO(), suite.rb.Name, metav1.GetOptions{})
+	suite.NoError(err)
+	suite.Equal(int32(8), totalReplicas(rb.Spec.RbApps, "web"))
+	suite.Equal(int32(6), rb.Spec.RbApps[0].Replicas["web"])
+	suite.Equal(int32(2), rb.Spec.RbApps[0].Children[0].Replicas["web"])
+	suite.Equal(int32(4), rb.Spec.RbApps[0].Children[1].Replicas["web"])
+	suite.Equal(int32(2), rb.Spec.RbApps[1].Children[0].Replicas["web"])
+	suite.Equal(int32(0), rb.Spec.RbApps[1].Children[1].Replicas["web"], "c4 has no replicas of web to scale")
+	suite.Equal(int32(4), totalReplicas(suite.rb.Spec.RbApps, "web"), "the lister copy is left alone")
+}
+
+func (suite *AutoscalerSuite) TestScaleDownStabilization() {
+	controller := suite.newController(&model.Scalar{Value: 10})
+	controller.lastScaled["desc/web"] = time.Now()
+	suite.NoError(controller.scale(context.TODO(), suite.desc, suite.rb))
+	rb, _ := controller.gaiaClient.AppsV1alpha1().ResourceBindings(suite.rb.Namespace).Get(context.TODO(), suite.rb.Name, metav1.GetOptions{})
+	suite.Equal(int32(4), totalReplicas(rb.Spec.RbApps, "web"))
+
+	controller.lastScaled["desc/web"] = time.Now().Add(-scaleDownStabilization)
+	suite.NoError(controller.scale(context.TODO(), suite.desc, suite.rb))
+	rb, _ = controller.gaiaClient.AppsV1alpha1().ResourceBindings(suite.rb.Namespace).Get(context.TODO(), suite.rb.Name, metav1.GetOptions{})
+	suite.Equal(int32(2), totalReplicas(rb.Spec.RbApps, "web"), "the least replicas")
+}
+
+func (suite *AutoscalerSuite) TestRecommendRequests() {
+	requests, err := recommendRequests(append(usage(0.5, 0.8),
+		&model.Sample{Metric: model.Metric{resourceLabel: "memory"}, Value: 100 << 20},
+		&model.Sample{Metric: model.Metric{resourceLabel: "gpu"}, Value: 1},
+	), appsapi.XPAStrategy{})
+	suite.NoError(err)
+	suite.Equal("920m", requests.Cpu().String(), "the peak with 15 percent headroom")
+	suite.Equal("115Mi", requests.Memory().String())
+	suite.Len(requests, 2)
+
+	requests, _ = recommendRequests(usage(0.5), appsapi.XPAStrategy{Value: []byte("0")})
+	suite.Equal("500m", requests.Cpu().String())
+
+	_, err = recommendRequests(nil, appsapi.XPAStrategy{})
+	suite.Error(err)
+}
+
+func (suite *AutoscalerSuite) TestApplyRequests() {
+	template := suite.desc.Spec.Components[0].Module.DeepCopy()
+	applyRequests(template, corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("800m"),
+		corev1.ResourceMemory: resource.MustParse("1Gi"),
+	})
+	first, second := template.Spec.Containers[0].Resources, template.Spec.Containers[1].Resources
+	suite.Equal("600m", first.Requests.Cpu().String(), "in proportion to the requests")
+	suite.Equal("600m", first.Limits.Cpu().String(), "the limit is raised")
+	suite.Equal("200m", second.Requests.Cpu().String())
+	suite.Equal("1Gi", first.Requests.Memory().String(), "the first container takes what no container requests")
+	suite.True(second.Requests.Memory().IsZero())
+}
+
+func (suite *AutoscalerSuite) TestResize() {
+	suite.desc.Spec.ExpectedPerformance.Maitenance = appsapi.Maitenance{VPA: []appsapi.XPA{{
+		Name: "cpu", Subject: "web", Trigger: "cpu_usage", Strategy: appsapi.XPAStrategy{Type: appsapi.XPAStrategyRecommend},
+	}}}
+	copy1 := &appsapi.Description{ObjectMeta: metav1.ObjectMeta{Name: "desc", Namespace: "gaia-field1"}}
+	field1 := &platformapi.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "field1", Namespace: "gaia-field1"},
+		Status: platformapi.ManagedClusterStatus{Available: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("1"),
+		}},
+	}
+	controller := suite.newController(usage(0.8), suite.desc, copy1, field1)
+	ctx := context.TODO()
+
+	suite.NoError(controller.resize(ctx, suite.desc, suite.rb))
+	desc, _ := controller.gaiaClient.AppsV1alpha1().Descriptions(suite.desc.Namespace).Get(ctx, "desc", metav1.GetOptions{})
+	suite.Len(desc.Status.Recommendations, 1)
+	suite.Equal("920m", desc.Status.Recommendations[0].Requests.Cpu().String())
+	suite.False(desc.Status.Recommendations[0].Applied)
+	suite.Equal(suite.desc.Spec.Components, desc.Spec.Components, "a recommendation is only recorded")
+
+	suite.desc.Spec.ExpectedPerformance.Maitenance.VPA[0].Strategy.Type = appsapi.XPAStrategyAuto
+	suite.NoError(controller.resize(ctx, suite.desc, suite.rb))
+	desc, _ = controller.gaiaClient.AppsV1alpha1().Descriptions(suite.desc.Namespace).Get(ctx, "desc", metav1.GetOptions{})
+	suite.True(desc.Status.Recommendations[0].Applied)
+	requests := podRequests(desc.Spec.Components[0].Module)
+	suite.Equal("920m", requests.Cpu().String())
+	copy1, _ = controller.gaiaClient.AppsV1alpha1().Descriptions("gaia-field1").Get(ctx, "desc", metav1.GetOptions{})
+	suite.Equal(appsapi.DescriptionPhaseReSchedule, copy1.Status.Phase, "field1 can't hold 3 replicas with 520m more each")
+}
+
+func TestAutoscaler(t *testing.T) {
+	suite.Run(t, new(AutoscalerSuite))
+}
@@ -0,0 +1,147 @@
+package autoscaler
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	appsapi "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+)
+
+// tolerance is the relative distance of the trigger from its target that the targetValue strategy doesn't scale on.
+const tolerance = 0.1
+
+// desiredReplicas returns the replicas of a component with current replicas whose trigger evaluates to metric.
+// It is bounded by the least replicas of traits and doubles the current replicas at most, at least 1 replica
+// is kept.
+func desiredReplicas(current int32, metric float64, strategy appsapi.XPAStrategy, traits *appsapi.TraitServerless) (int32, error) {
+	target, err := targetValue(strategy, traits)
+	if err != nil {
+		return current, err
+	}
+
+	desired := current
+	switch strategy.Type {
+	case appsapi.XPAStrategyTargetValue, "":
+		ratio := metric / target
+		if math.Abs(ratio-1) > tolerance {
+			desired = int32(math.Ceil(float64(current) * ratio))
+		}
+	case appsapi.XPAStrategyStep:
+		step := int32(1)
+		if traits != nil && traits.Step > 0 {
+			step = traits.Step
+		}
+		switch {
+		case metric > target:
+			desired = current + step
+		case current > step && metric*float64(current) < target*float64(current-step):
+			desired = current - step
+		}
+	default:
+		return current, fmt.Errorf("unknown hpa strategy %q", strategy.Type)
+	}
+
+	minReplicas := int32(1)
+	if traits != nil && traits.MiniInstancenumber > minReplicas {
+		minReplicas = traits.MiniInstancenumber
+	}
+	maxReplicas := 2 * current
+	if maxReplicas < 4 {
+		maxReplicas = 4
+	}
+	if desired > maxReplicas {
+		desired = maxReplicas
+	}
+	if desired < minReplicas {
+		desired = minReplicas
+	}
+	return desired, nil
+}
+
+// targetValue returns the value of strategy, or the threshold of traits if the strategy has none.
+func targetValue(strategy appsapi.XPAStrategy, traits *appsapi.TraitServerless) (float64, error) {
+	value := strings.TrimSpace(string(strategy.Value))
+	if len(value) == 0 && traits != nil {
+		value = strings.TrimSpace(traits.Threshold)
+	}
+	if len(value) == 0 {
+		return 0, fmt.Errorf("hpa strategy has no target value")
+	}
+	target, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hpa target value %q: %v", value, err)
+	}
+	if target <= 0 {
+		return 0, fmt.Errorf("hpa target value %q is not positive", value)
+	}
+	return target, nil
+}
+
+// totalReplicas returns the replicas of component comp in rbApps.
+func totalReplicas(rbApps []*appsapi.ResourceBindingApps, comp string) int32 {
+	var total int32
+	for _, rbApp := range rbApps {
+		if rbApp != nil {
+			total += rbApp.Replicas[comp]
+		}
+	}
+	return total
+}
+
+// rescale spreads the replicas of component comp over the clusters of rbApps in proportion to the replicas
+// each cluster has, down to the leaf clusters. A cluster without replicas of comp is kept without, so comp
+// stays in the clusters it has been scheduled to.
+func rescale(rbApps []*appsapi.ResourceBindingApps, comp string, replicas int32) {
+	weights := make([]int32, len(rbApps))
+	for i, rbApp := range rbApps {
+		if rbApp != nil {
+			weights[i] = rbApp.Replicas[comp]
+		}
+	}
+	for i, share := range distribute(replicas, weights) {
+		rbApp := rbApps[i]
+		if rbApp == nil || weights[i] == 0 {
+			continue
+		}
+		rbApp.Replicas[comp] = share
+		if len(rbApp.Children) > 0 {
+			rescale(rbApp.Children, comp, share)
+		}
+	}
+}
+
+// distribute splits total in proportion to weights by the largest remainder method, the earlier weights win
+// ties. Nothing is distributed if all weights are zero.
+func distribute(total int32, weights []int32) []int32 {
+	result := make([]int32, len(weights))
+	var sum int64
+	for _, weight := range weights {
+		sum += int64(weight)
+	}
+	if sum == 0 {
+		return result
+	}
+
+	remainders := make([]int64, len(weights))
+	left := int64(total)
+	for i, weight := range weights {
+		share := int64(total) * int64(weight)
+		result[i] = int32(share / sum)
+		remainders[i] = share % sum
+		left -= int64(result[i])
+	}
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return remainders[order[i]] > remainders[order[j]]
+	})
+	for _, i := range order[:left] {
+		result[i]++
+	}
+	return result
+}
@@ -0,0 +1,165 @@
+package autoscaler
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	appsapi "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	platformapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+)
+
+const (
+	// defaultHeadroom is the headroom in percent a vpa adds to the usage if its strategy has none.
+	defaultHeadroom = 15
+	// resourceLabel is the label of the samples of a vpa trigger that names the resource.
+	resourceLabel = "resource"
+)
+
+// recommendRequests returns the requests of a replica recommended for usage, the samples of a vpa trigger.
+// The peak of each resource over the clusters is raised by the headroom of strategy, cpu is rounded up to
+// millicores and memory to mebibytes.
+func recommendRequests(usage model.Vector, strategy appsapi.XPAStrategy) (corev1.ResourceList, error) {
+	headroom := float64(defaultHeadroom)
+	if value := strings.TrimSpace(string(strategy.Value)); len(value) > 0 {
+		var err error
+		if headroom, err = strconv.ParseFloat(value, 64); err != nil || headroom < 0 {
+			return nil, fmt.Errorf("invalid vpa headroom %q", value)
+		}
+	}
+
+	peaks := make(map[corev1.ResourceName]float64)
+	for _, sample := range usage {
+		name := corev1.ResourceName(sample.Metric[resourceLabel])
+		if name != corev1.ResourceCPU && name != corev1.ResourceMemory {
+			continue
+		}
+		if value := float64(sample.Value); value > peaks[name] {
+			peaks[name] = value
+		}
+	}
+	if len(peaks) == 0 {
+		return nil, fmt.Errorf("vpa trigger returns no cpu or memory usage")
+	}
+
+	requests := make(corev1.ResourceList, len(peaks))
+	factor := 1 + headroom/100
+	if peak, ok := peaks[corev1.ResourceCPU]; ok {
+		requests[corev1.ResourceCPU] = *resource.NewMilliQuantity(int64(math.Ceil(peak*factor*1000)), resource.DecimalSI)
+	}
+	if peak, ok := peaks[corev1.ResourceMemory]; ok {
+		requests[corev1.ResourceMemory] = *resource.NewQuantity(int64(math.Ceil(peak*factor/(1<<20)))<<20, resource.BinarySI)
+	}
+	return requests, nil
+}
+
+// podRequests returns the requests of a replica of template, summed over its containers.
+func podRequests(template corev1.PodTemplateSpec) corev1.ResourceList {
+	result := make(corev1.ResourceList)
+	for _, container := range template.Spec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			sum := result[name]
+			sum.Add(quantity)
+			result[name] = sum
+		}
+	}
+	return result
+}
+
+// differs returns true if any recommended request is away from the current one by more than the tolerance.
+func differs(current, recommended corev1.ResourceList) bool {
+	for name, quantity := range recommended {
+		now, ok := current[name]
+		if !ok || now.IsZero() {
+			return true
+		}
+		if math.Abs(float64(quantity.MilliValue())/float64(now.MilliValue())-1) > tolerance {
+			return true
+		}
+	}
+	return false
+}
+
+// applyRequests sets the requests of a replica of template. The containers share each request in proportion to
+// what they request now, the first container takes a request no container has. Limits below the new requests
+// are raised to them.
+func applyRequests(template *corev1.PodTemplateSpec, requests corev1.ResourceList) {
+	containers := template.Spec.Containers
+	if len(containers) == 0 {
+		return
+	}
+	current := podRequests(*template)
+	for name, quantity := range requests {
+		total, want := scaledValue(name, current[name]), scaledValue(name, quantity)
+		left := want
+		for i := range containers {
+			share := left
+			if total > 0 && i < len(containers)-1 {
+				share = int64(float64(want) * float64(scaledValue(name, containers[i].Resources.Requests[name])) / float64(total))
+			} else if total == 0 && i > 0 {
+				break
+			}
+			left -= share
+			setRequest(&containers[i].Resources, name, newScaledQuantity(name, share, quantity.Format))
+		}
+	}
+}
+
+func setRequest(resources *corev1.ResourceRequirements, name corev1.ResourceName, quantity resource.Quantity) {
+	if resources.Requests == nil {
+		resources.Requests = make(corev1.ResourceList)
+	}
+	resources.Requests[name] = quantity
+	if limit, ok := resources.Limits[name]; ok && limit.Cmp(quantity) < 0 {
+		resources.Limits[name] = quantity.DeepCopy()
+	}
+}
+
+// scaledValue returns cpu in millicores and the other resources in units.
+func scaledValue(name corev1.ResourceName, quantity resource.Quantity) int64 {
+	if name == corev1.ResourceCPU {
+		return quantity.MilliValue()
+	}
+	return quantity.Value()
+}
+
+func newScaledQuantity(name corev1.ResourceName, value int64, format resource.Format) resource.Quantity {
+	if name == corev1.ResourceCPU {
+		return *resource.NewMilliQuantity(value, format)
+	}
+	return *resource.NewQuantity(value, format)
+}
+
+// unfitClusters returns the clusters of rbApps that can't hold their replicas of component comp once the requests
+// of a replica are raised from current to recommended.
+func unfitClusters(rbApps []*appsapi.ResourceBindingApps, clusters map[string]*platformapi.ManagedCluster, comp string,
+	current, recommended corev1.ResourceList) []*platformapi.ManagedCluster {
+	var result []*platformapi.ManagedCluster
+	for _, rbApp := range rbApps {
+		if rbApp == nil || rbApp.Replicas[comp] == 0 {
+			continue
+		}
+		cluster, ok := clusters[rbApp.ClusterName]
+		if !ok {
+			continue
+		}
+		for name, quantity := range recommended {
+			extra := quantity.DeepCopy()
+			extra.Sub(current[name])
+			if extra.Sign() <= 0 {
+				continue
+			}
+			available := cluster.Status.Available[name]
+			if scaledValue(name, extra)*int64(rbApp.Replicas[comp]) > scaledValue(name, available) {
+				result = append(result, cluster)
+				break
+			}
+		}
+	}
+	return result
+}
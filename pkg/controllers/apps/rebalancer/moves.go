@@ -0,0 +1,264 @@
+package rebalancer
+
+import (
+	"math"
+
+	corev1 "k8s.io/api/core/v1"
+
+	appsapi "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	platformapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+)
+
+// balancedResources are the resources whose utilization is evened out.
+var balancedResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+// clusterLoad is the allocatable and used resources of a child cluster, updated as moves are planned.
+type clusterLoad struct {
+	allocatable map[corev1.ResourceName]float64
+	used        map[corev1.ResourceName]float64
+}
+
+// newClusterLoads returns the loads of the ready clusters that take new workloads, keyed by cluster name.
+func newClusterLoads(mcls []*platformapi.ManagedCluster) map[string]*clusterLoad {
+	loads := make(map[string]*clusterLoad, len(mcls))
+	for _, mcl := range mcls {
+		if mcl.DeletionTimestamp != nil || !mcl.Status.Readyz || noSchedule(mcl.Spec.Taints) {
+			continue
+		}
+		load := &clusterLoad{
+			allocatable: make(map[corev1.ResourceName]float64),
+			used:        make(map[corev1.ResourceName]float64),
+		}
+		for _, name := range balancedResources {
+			allocatable, ok := mcl.Status.Allocatable[name]
+			if !ok || allocatable.Sign() <= 0 {
+				continue
+			}
+			load.allocatable[name] = allocatable.AsApproximateFloat64()
+			if available, ok := mcl.Status.Available[name]; ok {
+				load.used[name] = math.Max(load.allocatable[name]-available.AsApproximateFloat64(), 0)
+			}
+		}
+		if len(load.allocatable) > 0 {
+			loads[mcl.Name] = load
+		}
+	}
+	return loads
+}
+
+func noSchedule(taints []corev1.Taint) bool {
+	for _, taint := range taints {
+		if taint.Effect == corev1.TaintEffectNoSchedule || taint.Effect == corev1.TaintEffectNoExecute {
+			return true
+		}
+	}
+	return false
+}
+
+// utilization returns the utilization of the most utilized resource.
+func (l *clusterLoad) utilization() float64 {
+	var result float64
+	for name, allocatable := range l.allocatable {
+		result = math.Max(result, l.used[name]/allocatable)
+	}
+	return result
+}
+
+// add adds the requests of replicas to the used resources.
+func (l *clusterLoad) add(requests corev1.ResourceList, replicas int32) {
+	for _, name := range balancedResources {
+		if quantity, ok := requests[name]; ok {
+			l.used[name] += quantity.AsApproximateFloat64() * float64(replicas)
+		}
+	}
+}
+
+// planMoves plans the moves of the replicas of the deployment components of desc, placed in the child clusters
+// by rbApps, from the most utilized cluster to the least utilized one, one replica at a time while their
+// utilization differs by more than the max imbalance of policy. Each move is taken out of budget and updates
+// loads. Preoccupying components and gang descriptions are never moved. In the apply mode, a component is only
+// moved into the clusters it runs in, the rest of its moves are proposed and leave loads alone.
+func planMoves(desc *appsapi.Description, rbApps []*appsapi.ResourceBindingApps, loads map[string]*clusterLoad,
+	budget *int32, policy Policy) []appsapi.ReplicaMove {
+	if desc.Spec.GangScheduling != nil {
+		return nil
+	}
+	var moves []appsapi.ReplicaMove
+	for i := range desc.Spec.Components {
+		comp := &desc.Spec.Components[i]
+		if comp.Workload.Workloadtype != appsapi.WorkloadTypeDeployment || desc.PreoccupyDuration(comp.Name) > 0 {
+			continue
+		}
+		replicas := make(map[string]int32)
+		var total int32
+		for _, rbApp := range rbApps {
+			if rbApp != nil && rbApp.Replicas[comp.Name] > 0 {
+				replicas[rbApp.ClusterName] = rbApp.Replicas[comp.Name]
+				total += rbApp.Replicas[comp.Name]
+			}
+		}
+		if total < 2 {
+			continue
+		}
+		disruption := int32(math.Floor(float64(total) * policy.MaxDisruption))
+		if disruption < 1 {
+			disruption = 1
+		}
+		requests := replicaRequests(comp.Module)
+		if !balancing(requests) {
+			// moving its replicas doesn't change the utilization of the clusters.
+			continue
+		}
+
+		var proposed *appsapi.ReplicaMove
+		for ; *budget > 0 && disruption > 0; disruption-- {
+			from := hottest(loads, replicas)
+			to := coolest(loads, from, nil)
+			if len(from) == 0 || len(to) == 0 || loads[from].utilization()-loads[to].utilization() <= policy.MaxImbalance {
+				break
+			}
+			applied := false
+			if policy.Mode == ModeApply {
+				if proposed == nil && replicas[to] == 0 {
+					// the least utilized cluster doesn't run comp, so the scheduler has to place it there.
+					proposed = &appsapi.ReplicaMove{Component: comp.Name, From: from, To: to, Replicas: 1}
+				}
+				to = coolest(loads, from, replicas)
+				if len(to) == 0 || loads[from].utilization()-loads[to].utilization() <= policy.MaxImbalance {
+					break
+				}
+				applied = true
+			}
+
+			loads[from].add(requests, -1)
+			loads[to].add(requests, 1)
+			if loads[to].utilization() > loads[from].utilization() {
+				// the replica would make the clusters swap places.
+				loads[from].add(requests, 1)
+				loads[to].add(requests, -1)
+				break
+			}
+			replicas[from]--
+			replicas[to]++
+			*budget--
+			moves = addMove(moves, appsapi.ReplicaMove{Component: comp.Name, From: from, To: to, Replicas: 1, Applied: applied})
+		}
+		if proposed != nil {
+			moves = append(moves, *proposed)
+		}
+	}
+	return moves
+}
+
+// hottest returns the most utilized cluster of loads that holds replicas, or empty if there is none.
+func hottest(loads map[string]*clusterLoad, replicas map[string]int32) string {
+	result := ""
+	for name, num := range replicas {
+		load, ok := loads[name]
+		if !ok || num == 0 {
+			continue
+		}
+		if len(result) == 0 || load.utilization() > loads[result].utilization() ||
+			(load.utilization() == loads[result].utilization() && name < result) {
+			result = name
+		}
+	}
+	return result
+}
+
+// coolest returns the least utilized cluster of loads other than except, among the ones that hold replicas
+// unless replicas is nil, or empty if there is none.
+func coolest(loads map[string]*clusterLoad, except string, replicas map[string]int32) string {
+	result := ""
+	for name, load := range loads {
+		if name == except || (replicas != nil && replicas[name] == 0) {
+			continue
+		}
+		if len(result) == 0 || load.utilization() < loads[result].utilization() ||
+			(load.utilization() == loads[result].utilization() && name < result) {
+			result = name
+		}
+	}
+	return result
+}
+
+// addMove adds move to moves, merging it into the move of the same replicas if there is one.
+func addMove(moves []appsapi.ReplicaMove, move appsapi.ReplicaMove) []appsapi.ReplicaMove {
+	for i := range moves {
+		if moves[i].Component == move.Component && moves[i].From == move.From && moves[i].To == move.To &&
+			moves[i].Applied == move.Applied {
+			moves[i].Replicas += move.Replicas
+			return moves
+		}
+	}
+	return append(moves, move)
+}
+
+// applyMove moves the replicas of move between the clusters of rbApps, down to their leaf clusters.
+func applyMove(rbApps []*appsapi.ResourceBindingApps, move appsapi.ReplicaMove) {
+	for _, rbApp := range rbApps {
+		if rbApp == nil {
+			continue
+		}
+		switch rbApp.ClusterName {
+		case move.From:
+			shiftReplicas(rbApp, move.Component, -move.Replicas)
+		case move.To:
+			shiftReplicas(rbApp, move.Component, move.Replicas)
+		}
+	}
+}
+
+// shiftReplicas adds delta replicas of component comp to rbApp and its children, one replica at a time. Replicas
+// are removed from the child with the most replicas of comp, and added to the child with the least, so comp stays
+// in the clusters it runs in.
+func shiftReplicas(rbApp *appsapi.ResourceBindingApps, comp string, delta int32) {
+	if rbApp.Replicas == nil {
+		rbApp.Replicas = make(map[string]int32)
+	}
+	rbApp.Replicas[comp] += delta
+	for delta != 0 {
+		var child *appsapi.ResourceBindingApps
+		for _, item := range rbApp.Children {
+			if item == nil || item.Replicas[comp] == 0 {
+				continue
+			}
+			if child == nil || (delta < 0 && item.Replicas[comp] > child.Replicas[comp]) ||
+				(delta > 0 && item.Replicas[comp] < child.Replicas[comp]) {
+				child = item
+			}
+		}
+		if child == nil {
+			return
+		}
+		step := int32(1)
+		if delta < 0 {
+			step = -1
+		}
+		shiftReplicas(child, comp, step)
+		delta -= step
+	}
+}
+
+// balancing returns true if requests request any of balancedResources.
+func balancing(requests corev1.ResourceList) bool {
+	for _, name := range balancedResources {
+		if quantity, ok := requests[name]; ok && quantity.Sign() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// replicaRequests returns the requests of a replica of template, summed over its containers.
+func replicaRequests(template corev1.PodTemplateSpec) corev1.ResourceList {
+	result := make(corev1.ResourceList)
+	for _, container := range template.Spec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			sum := result[name]
+			sum.Add(quantity)
+			result[name] = sum
+		}
+	}
+	return result
+}
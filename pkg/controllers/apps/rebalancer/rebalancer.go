@@ -0,0 +1,221 @@
+package rebalancer
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	appsapi "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	"github.com/lmxia/gaia/pkg/common"
+	gaiaclientset "github.com/lmxia/gaia/pkg/generated/clientset/versioned"
+	gaiainformers "github.com/lmxia/gaia/pkg/generated/informers/externalversions"
+	applisters "github.com/lmxia/gaia/pkg/generated/listers/apps/v1alpha1"
+	platformlisters "github.com/lmxia/gaia/pkg/generated/listers/platform/v1alpha1"
+)
+
+// The modes of the rebalancer.
+const (
+	// ModeOff turns the rebalancer off.
+	ModeOff = "Off"
+	// ModePropose records the moves in the status of the descriptions without applying them.
+	ModePropose = "Propose"
+	// ModeApply applies the moves between the clusters a component runs in, and proposes the others.
+	ModeApply = "Apply"
+)
+
+// Policy configures when and how far the rebalancer moves replicas.
+type Policy struct {
+	// Mode is one of Off, Propose and Apply.
+	Mode string
+	// Period is how often the utilization of the child clusters is evaluated.
+	Period time.Duration
+	// MaxImbalance is the difference of utilization, between 0 and 1, of the most and the least utilized
+	// clusters that is tolerated.
+	MaxImbalance float64
+	// MaxMovesPerPeriod is the most replicas moved in a period, over all descriptions.
+	MaxMovesPerPeriod int32
+	// MaxDisruption is the fraction of the replicas of a component moved at once, at least 1 replica of
+	// a component with more than one is moved.
+	MaxDisruption float64
+	// Cooldown is how long a description is left alone after moves were applied to it.
+	Cooldown time.Duration
+}
+
+// NewPolicy returns the default policy, which proposes moves only.
+func NewPolicy() *Policy {
+	return &Policy{
+		Mode:              ModePropose,
+		Period:            5 * time.Minute,
+		MaxImbalance:      0.2,
+		MaxMovesPerPeriod: 5,
+		MaxDisruption:     0.25,
+		Cooldown:          15 * time.Minute,
+	}
+}
+
+// Controller rebalances the descriptions scheduled by this cluster across its child clusters. It periodically
+// compares the utilization of the child clusters and moves replicas of deployment components out of the most
+// utilized clusters into the least utilized ones, updating the selected resource binding that the child
+// clusters apply on update. Only the clusters a component runs in are known to pass the filters of the
+// scheduler, moves into other clusters, e.g. the ones that joined later, are proposed in the status of the
+// description.
+type Controller struct {
+	gaiaClient gaiaclientset.Interface
+	descLister applisters.DescriptionLister
+	descSynced cache.InformerSynced
+	rbLister   applisters.ResourceBindingLister
+	rbSynced   cache.InformerSynced
+	mclLister  platformlisters.ManagedClusterLister
+	mclSynced  cache.InformerSynced
+	policy     Policy
+
+	lock sync.Mutex
+	// lastMoved is when moves were applied to each description last.
+	lastMoved map[string]time.Time
+}
+
+// NewController returns a rebalancer following policy.
+func NewController(gaiaClient gaiaclientset.Interface, gaiaInformerFactory gaiainformers.SharedInformerFactory, policy Policy) *Controller {
+	descInformer := gaiaInformerFactory.Apps().V1alpha1().Descriptions()
+	rbInformer := gaiaInformerFactory.Apps().V1alpha1().ResourceBindings()
+	mclInformer := gaiaInformerFactory.Platform().V1alpha1().ManagedClusters()
+	return &Controller{
+		gaiaClient: gaiaClient,
+		descLister: descInformer.Lister(),
+		descSynced: descInformer.Informer().HasSynced,
+		rbLister:   rbInformer.Lister(),
+		rbSynced:   rbInformer.Informer().HasSynced,
+		mclLister:  mclInformer.Lister(),
+		mclSynced:  mclInformer.Informer().HasSynced,
+		policy:     policy,
+		lastMoved:  make(map[string]time.Time),
+	}
+}
+
+// Run evaluates the utilization of the child clusters periodically until ctx is done.
+func (c *Controller) Run(ctx context.Context) {
+	if c.policy.Mode == ModeOff {
+		klog.Info("rebalancer is off")
+		return
+	}
+	klog.InfoS("starting rebalancer ...", "mode", c.policy.Mode)
+	defer klog.Info("shutting down rebalancer")
+
+	if !cache.WaitForNamedCacheSync("rebalancer", ctx.Done(), c.descSynced, c.rbSynced, c.mclSynced) {
+		return
+	}
+	wait.UntilWithContext(ctx, c.syncAll, c.policy.Period)
+}
+
+// syncAll plans the moves of the scheduled descriptions in the reserved namespace, lower priority ones first,
+// until the clusters are balanced or the moves of the period are used up.
+func (c *Controller) syncAll(ctx context.Context) {
+	mcls, err := c.mclLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Failed to list clusters to rebalance")
+		return
+	}
+	loads := newClusterLoads(mcls)
+	if len(loads) < 2 {
+		return
+	}
+	descs, err := c.descLister.Descriptions(common.GaiaReservedNamespace).List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Failed to list descriptions to rebalance")
+		return
+	}
+	sort.SliceStable(descs, func(i, j int) bool {
+		if descs[i].Spec.Priority != descs[j].Spec.Priority {
+			return descs[i].Spec.Priority < descs[j].Spec.Priority
+		}
+		return descs[i].Name < descs[j].Name
+	})
+
+	budget := c.policy.MaxMovesPerPeriod
+	for _, desc := range descs {
+		if budget <= 0 {
+			break
+		}
+		if desc.DeletionTimestamp != nil || desc.Status.Phase != appsapi.DescriptionPhaseScheduled || c.coolingDown(desc.Name) {
+			continue
+		}
+		rb, err := c.selectedResourceBinding(desc.Name)
+		if err != nil {
+			klog.ErrorS(err, "Failed to get selected resource binding", "description", klog.KObj(desc))
+			continue
+		}
+		if rb == nil {
+			continue
+		}
+		moves := planMoves(desc, rb.Spec.RbApps, loads, &budget, c.policy)
+		if err = c.rebalance(ctx, desc, rb, moves); err != nil {
+			klog.ErrorS(err, "Failed to rebalance description", "description", klog.KObj(desc))
+		}
+	}
+}
+
+// rebalance applies the applicable moves to rb and records moves in the status of desc.
+func (c *Controller) rebalance(ctx context.Context, desc *appsapi.Description, rb *appsapi.ResourceBinding,
+	moves []appsapi.ReplicaMove) error {
+	rb = rb.DeepCopy()
+	applied := false
+	for _, move := range moves {
+		if !move.Applied {
+			klog.V(3).InfoS("Proposing move", "description", klog.KObj(desc), "component", move.Component,
+				"from", move.From, "to", move.To, "replicas", move.Replicas)
+			continue
+		}
+		klog.InfoS("Moving replicas", "description", klog.KObj(desc), "component", move.Component,
+			"from", move.From, "to", move.To, "replicas", move.Replicas)
+		applyMove(rb.Spec.RbApps, move)
+		applied = true
+	}
+	if applied {
+		if _, err := c.gaiaClient.AppsV1alpha1().ResourceBindings(rb.Namespace).Update(ctx, rb, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		c.lock.Lock()
+		c.lastMoved[desc.Name] = time.Now()
+		c.lock.Unlock()
+	}
+
+	if apiequality.Semantic.DeepEqual(desc.Status.Moves, moves) {
+		return nil
+	}
+	desc = desc.DeepCopy()
+	desc.Status.Moves = moves
+	_, err := c.gaiaClient.AppsV1alpha1().Descriptions(desc.Namespace).UpdateStatus(ctx, desc, metav1.UpdateOptions{})
+	return err
+}
+
+// selectedResourceBinding returns the selected resource binding of description descName, or nil if there is none.
+func (c *Controller) selectedResourceBinding(descName string) (*appsapi.ResourceBinding, error) {
+	rbs, err := c.rbLister.ResourceBindings(common.GaiaRBMergedReservedNamespace).List(labels.SelectorFromSet(labels.Set{
+		common.GaiaDescriptionLabel: descName,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	for _, rb := range rbs {
+		if rb.DeletionTimestamp == nil && rb.Spec.StatusScheduler == appsapi.ResourceBindingSelected {
+			return rb, nil
+		}
+	}
+	return nil, nil
+}
+
+// coolingDown returns true if moves were applied to description descName recently.
+func (c *Controller) coolingDown(descName string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	at, ok := c.lastMoved[descName]
+	return ok && time.Since(at) < c.policy.Cooldown
+}
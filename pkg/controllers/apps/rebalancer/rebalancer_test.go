@@ -0,0 +1,174 @@
+package rebalancer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsapi "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	platformapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+	"github.com/lmxia/gaia/pkg/common"
+	"github.com/lmxia/gaia/pkg/generated/clientset/versioned/fake"
+	gaiainformers "github.com/lmxia/gaia/pkg/generated/informers/externalversions"
+)
+
+type RebalancerSuite struct {
+	policy Policy
+	mcls   []*platformapi.ManagedCluster
+	desc   *appsapi.Description
+	rb     *appsapi.ResourceBinding
+	suite.Suite
+}
+
+func newCluster(name, available string) *platformapi.ManagedCluster {
+	return &platformapi.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "gaia-" + name},
+		Status: platformapi.ManagedClusterStatus{
+			Readyz:      true,
+			Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10")},
+			Available:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(available)},
+		},
+	}
+}
+
+func (suite *RebalancerSuite) SetupTest() {
+	suite.policy = *NewPolicy()
+	// cluster1 is 80% utilized, cluster2 20% and cluster3, which doesn't run the component, 10%.
+	suite.mcls = []*platformapi.ManagedCluster{
+		newCluster("cluster1", "2"), newCluster("cluster2", "8"), newCluster("cluster3", "9"),
+	}
+	suite.desc = &appsapi.Description{
+		ObjectMeta: metav1.ObjectMeta{Name: "desc", Namespace: common.GaiaReservedNamespace},
+		Spec: appsapi.DescriptionSpec{Components: []appsapi.Component{{
+			Name:     "c",
+			Workload: appsapi.Workload{Workloadtype: appsapi.WorkloadTypeDeployment},
+			Module: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				},
+			}}}},
+		}}},
+		Status: appsapi.DescriptionStatus{Phase: appsapi.DescriptionPhaseScheduled},
+	}
+	suite.rb = &appsapi.ResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "desc-rs-0",
+			Namespace: common.GaiaRBMergedReservedNamespace,
+			Labels:    map[string]string{common.GaiaDescriptionLabel: "desc"},
+		},
+		Spec: appsapi.ResourceBindingSpec{
+			StatusScheduler: appsapi.ResourceBindingSelected,
+			RbApps: []*appsapi.ResourceBindingApps{
+				{ClusterName: "cluster1", Replicas: map[string]int32{"c": 6}},
+				{ClusterName: "cluster2", Replicas: map[string]int32{"c": 2}},
+				{ClusterName: "cluster3", Replicas: map[string]int32{"c": 0}},
+			},
+		},
+	}
+}
+
+func (suite *RebalancerSuite) plan(budget int32) []appsapi.ReplicaMove {
+	return planMoves(suite.desc, suite.rb.Spec.RbApps, newClusterLoads(suite.mcls), &budget, suite.policy)
+}
+
+func (suite *RebalancerSuite) TestPropose() {
+	suite.Equal([]appsapi.ReplicaMove{
+		{Component: "c", From: "cluster1", To: "cluster3", Replicas: 1},
+		{Component: "c", From: "cluster1", To: "cluster2", Replicas: 1},
+	}, suite.plan(5), "at most a quarter of the replicas are moved")
+}
+
+func (suite *RebalancerSuite) TestApply() {
+	suite.policy.Mode = ModeApply
+	budget := int32(5)
+	moves := planMoves(suite.desc, suite.rb.Spec.RbApps, newClusterLoads(suite.mcls), &budget, suite.policy)
+	suite.Equal([]appsapi.ReplicaMove{
+		{Component: "c", From: "cluster1", To: "cluster2", Replicas: 2, Applied: true},
+		{Component: "c", From: "cluster1", To: "cluster3", Replicas: 1},
+	}, moves, "the scheduler has to place the component in cluster3")
+	suite.Equal(int32(3), budget)
+
+	suite.Len(suite.plan(1), 2)
+	suite.Equal(int32(1), suite.plan(1)[0].Replicas, "the moves of a period are limited")
+}
+
+func (suite *RebalancerSuite) TestBalanced() {
+	suite.mcls[0].Status.Available[corev1.ResourceCPU] = resource.MustParse("7")
+	suite.Empty(suite.plan(5))
+
+	suite.mcls[0].Status.Available[corev1.ResourceCPU] = resource.MustParse("2")
+	suite.mcls[0].Spec.Taints = []corev1.Taint{{Key: "maintenance", Effect: corev1.TaintEffectNoSchedule}}
+	suite.mcls[1].Status.Available[corev1.ResourceCPU] = resource.MustParse("5")
+	suite.Equal([]appsapi.ReplicaMove{
+		{Component: "c", From: "cluster2", To: "cluster3", Replicas: 1},
+	}, suite.plan(5), "only cluster2 and cluster3 are considered")
+
+	suite.mcls[0].Spec.Taints = nil
+	suite.desc.Spec.Components[0].Workload.Workloadtype = appsapi.WorkloadTypeServerless
+	suite.Empty(suite.plan(5), "only deployments are moved")
+}
+
+func (suite *RebalancerSuite) TestWithoutRequests() {
+	suite.desc.Spec.Components[0].Module.Spec.Containers[0].Resources = corev1.ResourceRequirements{}
+	budget := int32(5)
+	suite.Empty(planMoves(suite.desc, suite.rb.Spec.RbApps, newClusterLoads(suite.mcls), &budget, suite.policy),
+		"moving replicas without requests doesn't balance the clusters")
+	suite.Equal(int32(5), budget)
+}
+
+func (suite *RebalancerSuite) TestApplyMove() {
+	rbApps := []*appsapi.ResourceBindingApps{
+		{ClusterName: "cluster1", Replicas: map[string]int32{"c": 3}, Children: []*appsapi.ResourceBindingApps{
+			{ClusterName: "leaf1", Replicas: map[string]int32{"c": 1}},
+			{ClusterName: "leaf2", Replicas: map[string]int32{"c": 2}},
+		}},
+		{ClusterName: "cluster2", Replicas: map[string]int32{"c": 3}, Children: []*appsapi.ResourceBindingApps{
+			{ClusterName: "leaf3", Replicas: map[string]int32{"c": 2}},
+			{ClusterName: "leaf4", Replicas: map[string]int32{"c": 1}},
+			{ClusterName: "leaf5"},
+		}},
+	}
+	applyMove(rbApps, appsapi.ReplicaMove{Component: "c", From: "cluster1", To: "cluster2", Replicas: 3, Applied: true})
+
+	suite.Zero(rbApps[0].Replicas["c"])
+	suite.Zero(rbApps[0].Children[0].Replicas["c"])
+	suite.Zero(rbApps[0].Children[1].Replicas["c"])
+	suite.Equal(int32(6), rbApps[1].Replicas["c"])
+	suite.Equal(int32(3), rbApps[1].Children[0].Replicas["c"])
+	suite.Equal(int32(3), rbApps[1].Children[1].Replicas["c"])
+	suite.Zero(rbApps[1].Children[2].Replicas["c"], "only the children running the component get replicas")
+}
+
+func (suite *RebalancerSuite) TestController() {
+	suite.policy.Mode = ModeApply
+	client := fake.NewSimpleClientset(suite.desc, suite.rb)
+	factory := gaiainformers.NewSharedInformerFactory(client, 0)
+	c := NewController(client, factory, suite.policy)
+	suite.NoError(factory.Apps().V1alpha1().Descriptions().Informer().GetIndexer().Add(suite.desc))
+	suite.NoError(factory.Apps().V1alpha1().ResourceBindings().Informer().GetIndexer().Add(suite.rb))
+	for _, mcl := range suite.mcls {
+		suite.NoError(factory.Platform().V1alpha1().ManagedClusters().Informer().GetIndexer().Add(mcl))
+	}
+
+	c.syncAll(context.TODO())
+	rb, err := client.AppsV1alpha1().ResourceBindings(suite.rb.Namespace).Get(context.TODO(), suite.rb.Name, metav1.GetOptions{})
+	suite.NoError(err)
+	suite.Equal(int32(4), rb.Spec.RbApps[0].Replicas["c"])
+	suite.Equal(int32(4), rb.Spec.RbApps[1].Replicas["c"])
+	desc, err := client.AppsV1alpha1().Descriptions(suite.desc.Namespace).Get(context.TODO(), suite.desc.Name, metav1.GetOptions{})
+	suite.NoError(err)
+	suite.Len(desc.Status.Moves, 2)
+	suite.True(c.coolingDown(suite.desc.Name))
+
+	c.lastMoved[suite.desc.Name] = time.Now().Add(-suite.policy.Cooldown)
+	suite.False(c.coolingDown(suite.desc.Name))
+}
+
+func TestRebalancerSuite(t *testing.T) {
+	suite.Run(t, new(RebalancerSuite))
+}
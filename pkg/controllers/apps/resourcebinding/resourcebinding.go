@@ -30,6 +30,7 @@ import (
 	"github.com/lmxia/gaia/pkg/utils"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	utilErrors "k8s.io/apimachinery/pkg/util/errors"
 	cacheddiscovery "k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
@@ -82,6 +83,11 @@ type RBController struct {
 	rbParentController                 *Controller
 	descParentController               *description.Controller
 	restMapper                         *restmapper.DeferredDiscoveryRESTMapper
+
+	mu sync.Mutex
+	// appliedGenerations holds the generation of every parent Description whose workloads were applied last,
+	// keyed by uid.
+	appliedGenerations map[types.UID]int64
 }
 
 // Controller is a controller that handle ResourceBinding requests
@@ -116,6 +122,7 @@ func NewRBController(localkubeclient *kubernetes.Clientset, localgaiaclient *gai
 		localRBsLister:           localGaiaInformerFactory.Apps().V1alpha1().ResourceBindings().Lister(),
 		localRBsSynced:           localGaiaInformerFactory.Apps().V1alpha1().ResourceBindings().Informer().HasSynced,
 		restMapper:               restmapper.NewDeferredDiscoveryRESTMapper(cacheddiscovery.NewMemCacheClient(localkubeclient.Discovery())),
+		appliedGenerations:       make(map[types.UID]int64),
 	}
 	if err != nil {
 		return nil, err
@@ -425,6 +432,9 @@ func (c *RBController) handleLocalDescription(desc *appsv1alpha1.Description) er
 		return err
 	}
 
+	if desc.DeletionTimestamp == nil && desc.Namespace == known.GaiaReservedNamespace {
+		return c.syncDerivedComponents(desc)
+	}
 	return nil
 }
 
@@ -470,6 +480,10 @@ func (c *RBController) handleParentDescription(desc *appsv1alpha1.Description) e
 			return fmt.Errorf("handleParentDescription: waiting for parent DedicatedNS ResourceBings belongs to Description %s getting deleted", klog.KObj(desc).String())
 		}
 
+		c.mu.Lock()
+		delete(c.appliedGenerations, desc.UID)
+		c.mu.Unlock()
+
 		descCopy := desc.DeepCopy()
 		descCopy.Finalizers = utils.RemoveString(descCopy.Finalizers, known.AppFinalizer)
 		if _, err = c.parentGaiaClient.AppsV1alpha1().Descriptions(desc.Namespace).Update(context.TODO(), descCopy, metav1.UpdateOptions{}); err != nil {
@@ -478,6 +492,86 @@ func (c *RBController) handleParentDescription(desc *appsv1alpha1.Description) e
 		return err
 	}
 
+	if err := c.syncDerivedComponents(desc); err != nil {
+		return err
+	}
+	return c.reapplyParentWorkloads(desc)
+}
+
+// syncDerivedComponents rolls the components of desc out to the local Descriptions derived from it, so that a
+// change of the component templates reaches the clusters deploying them.
+func (c *RBController) syncDerivedComponents(desc *appsv1alpha1.Description) error {
+	derivedDescs, err := c.derivedDescriptions(desc)
+	if err != nil {
+		return err
+	}
+	var allErrs []error
+	for _, derived := range derivedDescs {
+		if derived.DeletionTimestamp != nil || derived.UID == desc.UID || reflect.DeepEqual(derived.Spec.Components, desc.Spec.Components) {
+			continue
+		}
+		klog.V(4).Infof("rolling out components of Description %s to %s", klog.KObj(desc), klog.KObj(derived))
+		derivedCopy := derived.DeepCopy()
+		derivedCopy.Spec.Components = desc.Spec.Components
+		if _, err = c.localgaiaclient.AppsV1alpha1().Descriptions(derived.Namespace).Update(context.TODO(), derivedCopy, metav1.UpdateOptions{}); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	return utilErrors.NewAggregate(allErrs)
+}
+
+// reapplyParentWorkloads applies the components of desc again if the selected parent ResourceBinding of desc
+// deploys them in this cluster, once the spec of desc changes. The first generation seen is applied by the
+// ResourceBinding handler, along with the ResourceBinding.
+func (c *RBController) reapplyParentWorkloads(desc *appsv1alpha1.Description) error {
+	if c.parentMergedGaiaInformerFactory == nil {
+		return nil
+	}
+	c.mu.Lock()
+	applied, ok := c.appliedGenerations[desc.UID]
+	if !ok {
+		c.appliedGenerations[desc.UID] = desc.Generation
+	}
+	c.mu.Unlock()
+	if !ok || applied == desc.Generation {
+		return nil
+	}
+
+	rbs, err := c.parentMergedGaiaInformerFactory.Apps().V1alpha1().ResourceBindings().Lister().List(labels.SelectorFromSet(labels.Set{
+		known.GaiaDescriptionLabel: desc.Name,
+	}))
+	if err != nil {
+		return err
+	}
+	clusterName := ""
+	for _, rb := range rbs {
+		if rb.DeletionTimestamp != nil || rb.Spec.StatusScheduler != appsv1alpha1.ResourceBindingSelected {
+			continue
+		}
+		deployed := true
+		for _, rba := range rb.Spec.RbApps {
+			if len(rba.Children) > 0 {
+				deployed = false
+				break
+			}
+		}
+		if !deployed {
+			continue
+		}
+		if len(clusterName) == 0 {
+			if clusterName, _, err = utils.GetLocalClusterName(c.localkubeclient); err != nil {
+				return err
+			}
+		}
+		if err = utils.ApplyRBWorkloads(context.TODO(), desc, c.parentGaiaClient, c.localdynamicClient,
+			c.restMapper, rb.DeepCopy(), clusterName); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	c.appliedGenerations[desc.UID] = desc.Generation
+	c.mu.Unlock()
 	return nil
 }
 
@@ -517,7 +611,15 @@ func (c *RBController) handleParentResourceBinding(rb *appsv1alpha1.ResourceBind
 					klog.Infof("nwr error===%v \n", newErr)
 					nwr = nil
 				}
-				return utils.ApplyResourceBinding(context.TODO(), c.localdynamicClient, c.restMapper, rb, clusterName, descriptionName, c.networkBindUrl, nwr)
+				reserved := false
+				for _, comp := range desc.Spec.Components {
+					if desc.PreoccupyDuration(comp.Name) > 0 {
+						reserved = true
+						break
+					}
+				}
+				return utils.ApplyResourceBinding(context.TODO(), c.localdynamicClient, c.restMapper, rb, clusterName, descriptionName,
+					c.networkBindUrl, nwr, reserved)
 			} else {
 				// need schedule across clusters
 				if error := utils.ApplyRBWorkloads(context.TODO(), desc, c.parentGaiaClient, c.localdynamicClient,
@@ -594,24 +696,26 @@ func (c *RBController) SetLocalDescriptionController() (*RBController, error) {
 	return c, nil
 }
 
-func (c *RBController) offloadLocalDescriptions(desc *appsv1alpha1.Description) error {
-	var derivedDescs []*appsv1alpha1.Description
-	var allErrs []error
-	var err error
+// derivedDescriptions returns the local Descriptions derived from desc, which share the origin of desc.
+func (c *RBController) derivedDescriptions(desc *appsv1alpha1.Description) ([]*appsv1alpha1.Description, error) {
 	descLabels := desc.GetLabels()
 	if _, ok := descLabels[known.OriginDescriptionUIDLabel]; ok {
-		derivedDescs, err = c.localDescLister.List(labels.SelectorFromSet(labels.Set{
+		return c.localDescLister.List(labels.SelectorFromSet(labels.Set{
 			known.OriginDescriptionNameLabel:      descLabels[known.OriginDescriptionNameLabel],
 			known.OriginDescriptionNamespaceLabel: descLabels[known.OriginDescriptionNamespaceLabel],
 			known.OriginDescriptionUIDLabel:       descLabels[known.OriginDescriptionUIDLabel],
 		}))
-	} else {
-		derivedDescs, err = c.localDescLister.List(labels.SelectorFromSet(labels.Set{
-			known.OriginDescriptionNameLabel:      desc.Name,
-			known.OriginDescriptionNamespaceLabel: desc.Namespace,
-			known.OriginDescriptionUIDLabel:       string(desc.UID),
-		}))
 	}
+	return c.localDescLister.List(labels.SelectorFromSet(labels.Set{
+		known.OriginDescriptionNameLabel:      desc.Name,
+		known.OriginDescriptionNamespaceLabel: desc.Namespace,
+		known.OriginDescriptionUIDLabel:       string(desc.UID),
+	}))
+}
+
+func (c *RBController) offloadLocalDescriptions(desc *appsv1alpha1.Description) error {
+	var allErrs []error
+	derivedDescs, err := c.derivedDescriptions(desc)
 	if err != nil {
 		return err
 	}
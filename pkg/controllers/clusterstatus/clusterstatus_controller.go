@@ -4,11 +4,14 @@ import (
 	"context"
 	"github.com/lmxia/gaia/pkg/utils"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -16,13 +19,16 @@ import (
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	appsv1lister "k8s.io/client-go/listers/apps/v1"
 	corev1lister "k8s.io/client-go/listers/core/v1"
+	nodev1lister "k8s.io/client-go/listers/node/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	utilpointer "k8s.io/utils/pointer"
 
 	hypernodeclientset "github.com/SUMMERLm/hyperNodes/pkg/generated/clientset/versioned"
 	hypernodelister "github.com/SUMMERLm/hyperNodes/pkg/generated/listers/cluster/v1alpha1"
+	appsapi "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
 	clusterapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
 	known "github.com/lmxia/gaia/pkg/common"
 	"github.com/lmxia/gaia/pkg/controllers/clusterstatus/toposync"
@@ -55,6 +61,10 @@ type Controller struct {
 	nodeSynced             cache.InformerSynced
 	podLister              corev1lister.PodLister
 	podSynced              cache.InformerSynced
+	deploymentLister       appsv1lister.DeploymentLister
+	deploymentSynced       cache.InformerSynced
+	runtimeClassLister     nodev1lister.RuntimeClassLister
+	runtimeClassSynced     cache.InformerSynced
 	clusterName            string
 }
 
@@ -63,6 +73,8 @@ func NewController(ctx context.Context, apiserverURL, clusterName string, manage
 	// add informers
 	kubeInformerFactory.Core().V1().Nodes().Informer()
 	kubeInformerFactory.Core().V1().Pods().Informer()
+	kubeInformerFactory.Apps().V1().Deployments().Informer()
+	kubeInformerFactory.Node().V1().RuntimeClasses().Informer()
 	kubeInformerFactory.Start(ctx.Done())
 
 	gaiaInformerFactory := gaiainformers.NewSharedInformerFactory(gaiaClient, known.DefaultResync)
@@ -85,6 +97,10 @@ func NewController(ctx context.Context, apiserverURL, clusterName string, manage
 		nodeSynced:             kubeInformerFactory.Core().V1().Nodes().Informer().HasSynced,
 		podLister:              kubeInformerFactory.Core().V1().Pods().Lister(),
 		podSynced:              kubeInformerFactory.Core().V1().Pods().Informer().HasSynced,
+		deploymentLister:       kubeInformerFactory.Apps().V1().Deployments().Lister(),
+		deploymentSynced:       kubeInformerFactory.Apps().V1().Deployments().Informer().HasSynced,
+		runtimeClassLister:     kubeInformerFactory.Node().V1().RuntimeClasses().Lister(),
+		runtimeClassSynced:     kubeInformerFactory.Node().V1().RuntimeClasses().Informer().HasSynced,
 		clusterName:            clusterName,
 	}
 }
@@ -93,6 +109,8 @@ func (c *Controller) Run(ctx context.Context) {
 	if !cache.WaitForNamedCacheSync("cluster-status-controller", ctx.Done(),
 		c.podSynced,
 		c.nodeSynced,
+		c.deploymentSynced,
+		c.runtimeClassSynced,
 	) {
 		return
 	}
@@ -113,7 +131,9 @@ func (c *Controller) collectingClusterStatus(ctx context.Context) {
 	}
 
 	var nodeStatistics clusterapi.NodeStatistics
-	var capacity, allocatable, available corev1.ResourceList
+	var capacity, allocatable, available, maxNodeAvailable corev1.ResourceList
+	var clusterResources []clusterapi.ClusterResource
+	var sandboxes []string
 	var topoInfo clusterapi.Topo
 	if len(clusters) == 0 {
 		klog.V(7).Info("no joined clusters, collecting cluster resources...")
@@ -123,8 +143,23 @@ func (c *Controller) collectingClusterStatus(ctx context.Context) {
 		}
 
 		nodeStatistics = getNodeStatistics(nodes)
+		runtimeClasses, err := c.runtimeClassLister.List(labels.Everything())
+		if err != nil {
+			klog.Warningf("failed to list runtimeClasses: %v", err)
+		}
+		sandboxes = getSandboxes(runtimeClasses)
 		if c.managedClusterSource == known.ManagedClusterSourceFromInformer {
-			capacity, allocatable, available = getNodeResource(nodes)
+			pods, err := c.podLister.List(labels.Everything())
+			if err != nil {
+				klog.Warningf("failed to list pods: %v", err)
+			}
+			capacity, allocatable, available, maxNodeAvailable = getNodeResource(nodes, pods)
+
+			deployments, err := c.deploymentLister.List(labels.Everything())
+			if err != nil {
+				klog.Warningf("failed to list deployments: %v", err)
+			}
+			available = subtractResourceList(available, getPreoccupiedRequests(deployments, pods, time.Now()))
 		} else if c.managedClusterSource == known.ManagedClusterSourceFromPrometheus {
 			capacity, allocatable, available = getNodeResourceFromPrometheus(c.promUrlPrefix)
 		}
@@ -132,7 +167,9 @@ func (c *Controller) collectingClusterStatus(ctx context.Context) {
 		klog.V(7).Info("collecting ManagedCluster status...")
 
 		nodeStatistics = getManagedClusterNodeStatistics(clusters)
-		capacity, allocatable, available = getManagedClusterResource(clusters)
+		capacity, allocatable, available, maxNodeAvailable = getManagedClusterResource(clusters)
+		clusterResources = getClusterResources(clusters)
+		sandboxes = getManagedClusterSandboxes(clusters)
 
 		selfClusterName, _, errClusterName := utils.GetLocalClusterName(c.kubeClient.(*kubernetes.Clientset))
 		if errClusterName != nil {
@@ -165,6 +202,9 @@ func (c *Controller) collectingClusterStatus(ctx context.Context) {
 	status.Allocatable = allocatable
 	status.Capacity = capacity
 	status.Available = available
+	status.MaxNodeAvailable = maxNodeAvailable
+	status.ClusterResources = clusterResources
+	status.Sandboxes = sandboxes
 	status.HeartbeatFrequencySeconds = utilpointer.Int64Ptr(int64(c.heartbeatFrequency.Seconds()))
 	status.Conditions = []metav1.Condition{c.getCondition(status)}
 	status.TopologyInfo = topoInfo
@@ -394,49 +434,215 @@ func (c *Controller) discoverClusterCIDR() (string, error) {
 	return findClusterIPRange(c.podLister)
 }
 
-// get node capacity and allocatable resource
-func getNodeResource(nodes []*corev1.Node) (Capacity, Allocatable, Available corev1.ResourceList) {
-	var capacityCpu, capacityMem, allocatableCpu, allocatableMem resource.Quantity
-	Capacity, Allocatable, Available = make(map[corev1.ResourceName]resource.Quantity), make(map[corev1.ResourceName]resource.Quantity), make(map[corev1.ResourceName]resource.Quantity)
+// get node capacity and allocatable resource, every resource the nodes report is aggregated, so ephemeral
+// storage, hugepages and extended resources like nvidia.com/gpu are reported along with cpu and memory.
+// The available resource of a node is its allocatable resource minus the requests of the non-terminated pods on it.
+func getNodeResource(nodes []*corev1.Node, pods []*corev1.Pod) (Capacity, Allocatable, Available, MaxNodeAvailable corev1.ResourceList) {
+	Capacity, Allocatable, Available, MaxNodeAvailable = newResourceList(), newResourceList(), newResourceList(), newResourceList()
 
+	nodeRequests := getNodeRequests(pods)
 	for _, node := range nodes {
-		capacityCpu.Add(*node.Status.Capacity.Cpu())
-		capacityMem.Add(*node.Status.Capacity.Memory())
-		allocatableCpu.Add(*node.Status.Allocatable.Cpu())
-		allocatableMem.Add(*node.Status.Allocatable.Memory())
-	}
+		addResourceList(Capacity, node.Status.Capacity)
+		addResourceList(Allocatable, node.Status.Allocatable)
 
-	Capacity[corev1.ResourceCPU] = capacityCpu
-	Capacity[corev1.ResourceMemory] = capacityMem
-	Allocatable[corev1.ResourceCPU] = allocatableCpu
-	Allocatable[corev1.ResourceMemory] = allocatableMem
-	Available[corev1.ResourceCPU] = allocatableCpu
-	Available[corev1.ResourceMemory] = allocatableMem
+		nodeAvailable := subtractResourceList(node.Status.Allocatable, nodeRequests[node.Name])
+		addResourceList(Available, nodeAvailable)
+		maxResourceList(MaxNodeAvailable, nodeAvailable)
+	}
 
 	return
 }
 
-// getManagedClusterResource gets the node capacity of all managedClusters and their allocatable resources
-func getManagedClusterResource(clusters []*clusterapi.ManagedCluster) (Capacity, Allocatable, Available corev1.ResourceList) {
-	Capacity, Allocatable, Available = make(map[corev1.ResourceName]resource.Quantity), make(map[corev1.ResourceName]resource.Quantity), make(map[corev1.ResourceName]resource.Quantity)
-	var capacityCPU, capacityMem, allocatableCPU, allocatableMem, availableCPU, availableMem resource.Quantity
+// getPreoccupiedRequests sums the requests of the replicas that don't run yet of the deployments preoccupying
+// their resources until a time after now, so the capacity stays reserved for them while they are pulled or
+// waiting in the queue of kube-scheduler.
+func getPreoccupiedRequests(deployments []*appsv1.Deployment, pods []*corev1.Pod, now time.Time) corev1.ResourceList {
+	result := make(corev1.ResourceList)
+	for _, deployment := range deployments {
+		until, err := time.Parse(time.RFC3339, deployment.Annotations[known.PreoccupyUntilAnnotation])
+		if err != nil || !now.Before(until) || deployment.Spec.Replicas == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+		if err != nil {
+			continue
+		}
+
+		missing := *deployment.Spec.Replicas
+		for _, pod := range pods {
+			if pod.Namespace != deployment.Namespace || pod.Spec.NodeName == "" ||
+				pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed ||
+				!selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			missing--
+		}
+		requests := getPodRequests(&corev1.Pod{Spec: deployment.Spec.Template.Spec})
+		for ; missing > 0; missing-- {
+			addResourceList(result, requests)
+		}
+	}
+	return result
+}
+
+// getManagedClusterResource gets the node capacity of all managedClusters and their allocatable resources,
+// every resource the managedClusters report is aggregated. The largest free node is only known for the resources
+// every managedCluster reports it for, e.g. clusters sourced from prometheus or running an older agent report none.
+func getManagedClusterResource(clusters []*clusterapi.ManagedCluster) (Capacity, Allocatable, Available, MaxNodeAvailable corev1.ResourceList) {
+	Capacity, Allocatable, Available, MaxNodeAvailable = newResourceList(), newResourceList(), newResourceList(), corev1.ResourceList{}
 	for _, cluster := range clusters {
-		capacityCPU.Add(cluster.Status.Capacity[corev1.ResourceCPU])
-		capacityMem.Add(cluster.Status.Capacity[corev1.ResourceMemory])
-		allocatableCPU.Add(cluster.Status.Allocatable[corev1.ResourceCPU])
-		allocatableMem.Add(cluster.Status.Allocatable[corev1.ResourceMemory])
-		availableCPU.Add(cluster.Status.Available[corev1.ResourceCPU])
-		availableMem.Add(cluster.Status.Available[corev1.ResourceMemory])
+		addResourceList(Capacity, cluster.Status.Capacity)
+		addResourceList(Allocatable, cluster.Status.Allocatable)
+		addResourceList(Available, cluster.Status.Available)
+		maxResourceList(MaxNodeAvailable, cluster.Status.MaxNodeAvailable)
+	}
+	for _, cluster := range clusters {
+		for name := range MaxNodeAvailable {
+			if _, ok := cluster.Status.MaxNodeAvailable[name]; !ok {
+				delete(MaxNodeAvailable, name)
+			}
+		}
 	}
-	Capacity[corev1.ResourceCPU] = capacityCPU
-	Capacity[corev1.ResourceMemory] = capacityMem
-	Allocatable[corev1.ResourceCPU] = allocatableCPU
-	Allocatable[corev1.ResourceMemory] = allocatableMem
-	Available[corev1.ResourceCPU] = availableCPU
-	Available[corev1.ResourceMemory] = availableMem
 	return
 }
 
+// getClusterResources gets the capacity distribution of all managedClusters, the distribution reported by a
+// non-leaf managedCluster is taken as is, so every entry stays a leaf cluster however deep the hierarchy is.
+func getClusterResources(clusters []*clusterapi.ManagedCluster) []clusterapi.ClusterResource {
+	result := make([]clusterapi.ClusterResource, 0, len(clusters))
+	for _, cluster := range clusters {
+		if len(cluster.Status.ClusterResources) > 0 {
+			for _, item := range cluster.Status.ClusterResources {
+				result = append(result, *item.DeepCopy())
+			}
+			continue
+		}
+		result = append(result, clusterapi.ClusterResource{
+			Name:             cluster.Name,
+			Available:        cluster.Status.Available.DeepCopy(),
+			MaxNodeAvailable: cluster.Status.MaxNodeAvailable.DeepCopy(),
+		})
+	}
+	return result
+}
+
+// getSandboxes returns the runtime sandboxes of a leaf cluster, runc is the default runtime of the nodes and
+// every other sandbox runs on the RuntimeClass named after it.
+func getSandboxes(runtimeClasses []*nodev1.RuntimeClass) []string {
+	sandboxes := map[string]struct{}{string(appsapi.Runc): {}}
+	for _, sandbox := range []appsapi.SandboxType{appsapi.Process, appsapi.Kata, appsapi.Wasm} {
+		for _, runtimeClass := range runtimeClasses {
+			if runtimeClass.Name == string(sandbox) {
+				sandboxes[string(sandbox)] = struct{}{}
+				break
+			}
+		}
+	}
+	return sortedKeys(sandboxes)
+}
+
+// getManagedClusterSandboxes returns the runtime sandboxes of all managedClusters.
+func getManagedClusterSandboxes(clusters []*clusterapi.ManagedCluster) []string {
+	sandboxes := make(map[string]struct{})
+	for _, cluster := range clusters {
+		for _, sandbox := range cluster.Status.Sandboxes {
+			sandboxes[sandbox] = struct{}{}
+		}
+	}
+	return sortedKeys(sandboxes)
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	result := make([]string, 0, len(set))
+	for key := range set {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// getNodeRequests sums the requests of the non-terminated pods on every node, keyed by node name.
+func getNodeRequests(pods []*corev1.Pod) map[string]corev1.ResourceList {
+	result := make(map[string]corev1.ResourceList)
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if _, ok := result[pod.Spec.NodeName]; !ok {
+			result[pod.Spec.NodeName] = make(corev1.ResourceList)
+		}
+		addResourceList(result[pod.Spec.NodeName], getPodRequests(pod))
+	}
+	return result
+}
+
+// getPodRequests returns the resources the pod requests, the same way kube-scheduler counts them:
+// the sum of all containers, at least the largest init container, plus the pod overhead.
+func getPodRequests(pod *corev1.Pod) corev1.ResourceList {
+	result := make(corev1.ResourceList)
+	for _, container := range pod.Spec.Containers {
+		addResourceList(result, container.Resources.Requests)
+	}
+	maxResourceList(result, maxInitContainerRequests(pod))
+	addResourceList(result, pod.Spec.Overhead)
+	return result
+}
+
+// maxInitContainerRequests returns the largest request of the init containers for every resource.
+func maxInitContainerRequests(pod *corev1.Pod) corev1.ResourceList {
+	result := make(corev1.ResourceList)
+	for _, container := range pod.Spec.InitContainers {
+		maxResourceList(result, container.Resources.Requests)
+	}
+	return result
+}
+
+// newResourceList returns a resource list that always reports cpu and memory, even with nothing to aggregate.
+func newResourceList() corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceCPU:    resource.Quantity{},
+		corev1.ResourceMemory: resource.Quantity{},
+	}
+}
+
+// addResourceList adds every resource in delta to list.
+func addResourceList(list, delta corev1.ResourceList) {
+	for name, quantity := range delta {
+		value := list[name]
+		value.Add(quantity)
+		list[name] = value
+	}
+}
+
+// maxResourceList sets every resource of list to the larger one of list and other.
+func maxResourceList(list, other corev1.ResourceList) {
+	for name, quantity := range other {
+		if value, ok := list[name]; !ok || quantity.Cmp(value) > 0 {
+			list[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+// subtractResourceList returns list minus delta, no resource goes below zero.
+func subtractResourceList(list, delta corev1.ResourceList) corev1.ResourceList {
+	result := list.DeepCopy()
+	if result == nil {
+		result = make(corev1.ResourceList)
+	}
+	for name, quantity := range delta {
+		value, ok := result[name]
+		if !ok {
+			continue
+		}
+		value.Sub(quantity)
+		if value.Sign() < 0 {
+			value = *resource.NewQuantity(0, value.Format)
+		}
+		result[name] = value
+	}
+	return result
+}
+
 // getNodeCondition returns the specified condition from node's status
 // Copied from k8s.io/kubernetes/pkg/controller/util/node/controller_utils.go and make some modifications
 func getNodeCondition(status *corev1.NodeStatus, conditionType corev1.NodeConditionType) (int, *corev1.NodeCondition) {
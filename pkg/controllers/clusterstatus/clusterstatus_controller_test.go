@@ -0,0 +1,186 @@
+package clusterstatus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilpointer "k8s.io/utils/pointer"
+
+	clusterapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+	known "github.com/lmxia/gaia/pkg/common"
+	"github.com/lmxia/gaia/pkg/scheduler/framework"
+)
+
+type ResourceSuite struct {
+	nodes []*corev1.Node
+	pods  []*corev1.Pod
+	suite.Suite
+}
+
+func (suite *ResourceSuite) SetupTest() {
+	suite.nodes = []*corev1.Node{
+		newNode("node0", "4", "8Gi", "0"),
+		newNode("node1", "8", "16Gi", "2"),
+	}
+	suite.pods = []*corev1.Pod{
+		newPod("running", "node1", corev1.PodRunning, "6", "4Gi"),
+		newPod("succeeded", "node0", corev1.PodSucceeded, "4", "8Gi"),
+		newPod("pending", "", corev1.PodPending, "4", "8Gi"),
+	}
+	suite.pods[0].Spec.InitContainers = []corev1.Container{{Resources: corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("7")},
+	}}}
+}
+
+func (suite *ResourceSuite) TestGetNodeResource() {
+	capacity, allocatable, available, maxNodeAvailable := getNodeResource(suite.nodes, suite.pods)
+	suite.Equal(int64(12000), capacity.Cpu().MilliValue())
+	suite.Equal(int64(12000), allocatable.Cpu().MilliValue())
+	gpu := allocatable["nvidia.com/gpu"]
+	suite.Equal(int64(2), gpu.Value(), "extended resources are aggregated")
+
+	suite.Equal(int64(5000), available.Cpu().MilliValue(), "the largest init container counts, terminated pods don't")
+	suite.Equal(int64(20*1024*1024*1024), available.Memory().Value())
+	suite.Equal(int64(4000), maxNodeAvailable.Cpu().MilliValue(), "node0 is the freest on cpu")
+	suite.Equal(int64(12*1024*1024*1024), maxNodeAvailable.Memory().Value(), "node1 is the freest on memory")
+}
+
+func (suite *ResourceSuite) TestGetPreoccupiedRequests() {
+	now := time.Now()
+	template := newPod("", "", "", "2", "1Gi")
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: map[string]string{
+			known.PreoccupyUntilAnnotation: now.Add(time.Minute).UTC().Format(time.RFC3339),
+		}},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: utilpointer.Int32(3),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "app"}},
+			Template: corev1.PodTemplateSpec{Spec: template.Spec},
+		},
+	}
+	pods := []*corev1.Pod{
+		newPod("bound", "node0", corev1.PodRunning, "2", "1Gi"),
+		newPod("pending", "", corev1.PodPending, "2", "1Gi"),
+		newPod("other", "node0", corev1.PodRunning, "2", "1Gi"),
+	}
+	pods[0].Namespace, pods[0].Labels = "default", map[string]string{"app": "app"}
+	pods[1].Namespace, pods[1].Labels = "default", map[string]string{"app": "app"}
+	pods[2].Namespace = "default"
+
+	requests := getPreoccupiedRequests([]*appsv1.Deployment{deployment}, pods, now)
+	suite.Equal(int64(4000), requests.Cpu().MilliValue(), "the unbound replicas keep their resources")
+	suite.Equal(int64(2*1024*1024*1024), requests.Memory().Value())
+
+	requests = getPreoccupiedRequests([]*appsv1.Deployment{deployment}, pods, now.Add(time.Hour))
+	suite.Empty(requests, "nothing is preoccupied once the preoccupy expires")
+}
+
+func (suite *ResourceSuite) TestGetManagedClusterResource() {
+	clusters := []*clusterapi.ManagedCluster{
+		{Status: clusterapi.ManagedClusterStatus{
+			Available:        corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+			MaxNodeAvailable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+		}},
+		{Status: clusterapi.ManagedClusterStatus{
+			Available:        corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("6")},
+			MaxNodeAvailable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3")},
+		}},
+	}
+	_, _, available, maxNodeAvailable := getManagedClusterResource(clusters)
+	suite.Equal(int64(10000), available.Cpu().MilliValue())
+	suite.Equal(int64(3000), maxNodeAvailable.Cpu().MilliValue(), "the freest node of all clusters")
+	suite.NotContains(maxNodeAvailable, corev1.ResourceMemory, "no cluster reports memory")
+}
+
+func (suite *ResourceSuite) TestGetManagedClusterResourceWithoutMaxNode() {
+	clusters := []*clusterapi.ManagedCluster{
+		{Status: clusterapi.ManagedClusterStatus{
+			Available: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+		}},
+		{Status: clusterapi.ManagedClusterStatus{
+			Available: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("6")},
+		}},
+	}
+	_, _, available, maxNodeAvailable := getManagedClusterResource(clusters)
+	suite.Equal(int64(10000), available.Cpu().MilliValue())
+	suite.Empty(maxNodeAvailable, "children report no free node")
+
+	clusters[1].Status.MaxNodeAvailable = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3")}
+	_, _, _, maxNodeAvailable = getManagedClusterResource(clusters)
+	suite.Empty(maxNodeAvailable, "the free nodes of the first child are unknown")
+
+	parent := &framework.ClusterInfo{Cluster: &clusterapi.ManagedCluster{Status: clusterapi.ManagedClusterStatus{
+		Available: available, MaxNodeAvailable: maxNodeAvailable,
+	}}}
+	suite.Equal(int64(2), parent.CalculateCapacity(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}))
+}
+
+func (suite *ResourceSuite) TestGetClusterResources() {
+	clusters := []*clusterapi.ManagedCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "leaf"}, Status: clusterapi.ManagedClusterStatus{
+			Available: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+		}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "middle"}, Status: clusterapi.ManagedClusterStatus{
+			Available: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("6")},
+			ClusterResources: []clusterapi.ClusterResource{
+				{Name: "edge0", Available: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3")}},
+				{Name: "edge1", Available: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3")}},
+			},
+		}},
+	}
+	result := getClusterResources(clusters)
+	suite.Len(result, 3, "non-leaf clusters are flattened into their leaf clusters")
+	suite.Equal("leaf", result[0].Name)
+	suite.Equal("edge1", result[2].Name)
+}
+
+func (suite *ResourceSuite) TestGetSandboxes() {
+	runtimeClasses := []*nodev1.RuntimeClass{
+		{ObjectMeta: metav1.ObjectMeta{Name: "kata"}, Handler: "kata-qemu"},
+		{ObjectMeta: metav1.ObjectMeta{Name: "gvisor"}, Handler: "runsc"},
+	}
+	suite.Equal([]string{"kata", "runc"}, getSandboxes(runtimeClasses), "runc runs everywhere")
+	suite.Equal([]string{"runc"}, getSandboxes(nil))
+
+	clusters := []*clusterapi.ManagedCluster{
+		{Status: clusterapi.ManagedClusterStatus{Sandboxes: []string{"kata", "runc"}}},
+		{Status: clusterapi.ManagedClusterStatus{Sandboxes: []string{"runc", "wasm"}}},
+	}
+	suite.Equal([]string{"kata", "runc", "wasm"}, getManagedClusterSandboxes(clusters))
+}
+
+func newNode(name, cpu, mem, gpu string) *corev1.Node {
+	resources := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(mem),
+		"nvidia.com/gpu":      resource.MustParse(gpu),
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     corev1.NodeStatus{Capacity: resources, Allocatable: resources.DeepCopy()},
+	}
+}
+
+func newPod(name, nodeName string, phase corev1.PodPhase, cpu, mem string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(mem),
+			}}}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestResource(t *testing.T) {
+	suite.Run(t, new(ResourceSuite))
+}
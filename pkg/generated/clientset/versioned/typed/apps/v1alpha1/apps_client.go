@@ -29,6 +29,7 @@ type AppsV1alpha1Interface interface {
 	DescriptionsGetter
 	NetworkRequirementsGetter
 	ResourceBindingsGetter
+	SchedulingSimulationsGetter
 	UserAPPsGetter
 }
 
@@ -49,6 +50,10 @@ func (c *AppsV1alpha1Client) ResourceBindings(namespace string) ResourceBindingI
 	return newResourceBindings(c, namespace)
 }
 
+func (c *AppsV1alpha1Client) SchedulingSimulations(namespace string) SchedulingSimulationInterface {
+	return newSchedulingSimulations(c, namespace)
+}
+
 func (c *AppsV1alpha1Client) UserAPPs(namespace string) UserAPPInterface {
 	return newUserAPPs(c, namespace)
 }
@@ -40,6 +40,10 @@ func (c *FakeAppsV1alpha1) ResourceBindings(namespace string) v1alpha1.ResourceB
 	return &FakeResourceBindings{c, namespace}
 }
 
+func (c *FakeAppsV1alpha1) SchedulingSimulations(namespace string) v1alpha1.SchedulingSimulationInterface {
+	return &FakeSchedulingSimulations{c, namespace}
+}
+
 func (c *FakeAppsV1alpha1) UserAPPs(namespace string) v1alpha1.UserAPPInterface {
 	return &FakeUserAPPs{c, namespace}
 }
@@ -24,4 +24,6 @@ type NetworkRequirementExpansion interface{}
 
 type ResourceBindingExpansion interface{}
 
+type SchedulingSimulationExpansion interface{}
+
 type UserAPPExpansion interface{}
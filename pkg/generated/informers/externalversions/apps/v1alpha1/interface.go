@@ -30,6 +30,8 @@ type Interface interface {
 	NetworkRequirements() NetworkRequirementInformer
 	// ResourceBindings returns a ResourceBindingInformer.
 	ResourceBindings() ResourceBindingInformer
+	// SchedulingSimulations returns a SchedulingSimulationInformer.
+	SchedulingSimulations() SchedulingSimulationInformer
 	// UserAPPs returns a UserAPPInformer.
 	UserAPPs() UserAPPInformer
 }
@@ -60,6 +62,11 @@ func (v *version) ResourceBindings() ResourceBindingInformer {
 	return &resourceBindingInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// SchedulingSimulations returns a SchedulingSimulationInformer.
+func (v *version) SchedulingSimulations() SchedulingSimulationInformer {
+	return &schedulingSimulationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // UserAPPs returns a UserAPPInformer.
 func (v *version) UserAPPs() UserAPPInformer {
 	return &userAPPInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -60,6 +60,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Apps().V1alpha1().NetworkRequirements().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("resourcebindings"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Apps().V1alpha1().ResourceBindings().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("schedulingsimulations"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Apps().V1alpha1().SchedulingSimulations().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("userapps"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Apps().V1alpha1().UserAPPs().Informer()}, nil
 
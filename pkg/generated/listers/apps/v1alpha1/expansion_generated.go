@@ -42,6 +42,14 @@ type ResourceBindingListerExpansion interface{}
 // ResourceBindingNamespaceLister.
 type ResourceBindingNamespaceListerExpansion interface{}
 
+// SchedulingSimulationListerExpansion allows custom methods to be added to
+// SchedulingSimulationLister.
+type SchedulingSimulationListerExpansion interface{}
+
+// SchedulingSimulationNamespaceListerExpansion allows custom methods to be added to
+// SchedulingSimulationNamespaceLister.
+type SchedulingSimulationNamespaceListerExpansion interface{}
+
 // UserAPPListerExpansion allows custom methods to be added to
 // UserAPPLister.
 type UserAPPListerExpansion interface{}
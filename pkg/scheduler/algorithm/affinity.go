@@ -0,0 +1,177 @@
+package algorithm
+
+import (
+	"errors"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	appv1alpha1 "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	"github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+)
+
+// ErrComponentAffinity is used to describe the error that no combination of component plans satisfies
+// the inter-component affinity of a description.
+var ErrComponentAffinity = errors.New("no combination of component plans satisfies the component affinity")
+
+// componentAffinity evaluates the inter-component affinity of a description on combinations of component plans,
+// together with the mandatory deployment conditions between its components. The columns of the plans are clusters.
+type componentAffinity struct {
+	names []string
+	// affinities are the affinity rules of every component.
+	affinities []*appv1alpha1.ComponentAffinity
+	// domains are the topology domains of every cluster, keyed by topology key.
+	domains map[appv1alpha1.ComponentTopologyKey][]sets.String
+}
+
+// newComponentAffinity returns the component affinity of desc, or nil if no component of desc has any.
+func newComponentAffinity(desc *appv1alpha1.Description, clusters []*v1alpha1.ManagedCluster) *componentAffinity {
+	names := make([]string, len(desc.Spec.Components))
+	affinities := make([]*appv1alpha1.ComponentAffinity, len(desc.Spec.Components))
+	hasAffinity := false
+	for i, comp := range desc.Spec.Components {
+		names[i] = comp.Name
+		affinities[i] = conditionAffinity(comp.SchedulePolicy.Affinity, comp.Name, desc.Spec.DeploymentCondition.Mandatory)
+		hasAffinity = hasAffinity || affinities[i] != nil
+	}
+	if !hasAffinity {
+		return nil
+	}
+
+	byCluster := make([]sets.String, len(clusters))
+	byGeoLocation := make([]sets.String, len(clusters))
+	for i, cluster := range clusters {
+		byCluster[i] = sets.NewString(cluster.Name)
+		_, _, _, _, _, geoLocations, _ := cluster.GetHypernodeLabelsMapFromManagedCluster()
+		byGeoLocation[i] = sets.StringKeySet(geoLocations)
+	}
+	return &componentAffinity{
+		names:      names,
+		affinities: affinities,
+		domains: map[appv1alpha1.ComponentTopologyKey][]sets.String{
+			appv1alpha1.ComponentTopologyCluster:     byCluster,
+			appv1alpha1.ComponentTopologyGeoLocation: byGeoLocation,
+		},
+	}
+}
+
+// conditionAffinity returns affinity with the terms of the mandatory conditions of component name on other
+// components: sameAs is an affinity on clusters, near an affinity on geolocations and notSameAs an
+// anti-affinity on clusters. The conditions on attributes of clusters are left to the filter plugins.
+func conditionAffinity(affinity *appv1alpha1.ComponentAffinity, name string, conditions []appv1alpha1.Condition) *appv1alpha1.ComponentAffinity {
+	var result *appv1alpha1.ComponentAffinity
+	if affinity != nil {
+		result = affinity.DeepCopy()
+	}
+	for _, cond := range conditions {
+		if cond.Subject.Name != name || cond.Object.Type != appv1alpha1.XjectTypeComponent ||
+			(cond.Subject.Type != "" && cond.Subject.Type != appv1alpha1.XjectTypeComponent) {
+			continue
+		}
+		term := appv1alpha1.ComponentAffinityTerm{Components: []string{cond.Object.Name}}
+		switch cond.Relation {
+		case appv1alpha1.RelationSameAs, appv1alpha1.RelationNotSameAs:
+			term.TopologyKey = appv1alpha1.ComponentTopologyCluster
+		case appv1alpha1.RelationNear:
+			term.TopologyKey = appv1alpha1.ComponentTopologyGeoLocation
+		default:
+			klog.V(4).InfoS("Ignoring mandatory condition with unknown relation between components",
+				"component", name, "relation", cond.Relation)
+			continue
+		}
+		if result == nil {
+			result = &appv1alpha1.ComponentAffinity{}
+		}
+		if cond.Relation == appv1alpha1.RelationNotSameAs {
+			result.AntiAffinity = append(result.AntiAffinity, term)
+		} else {
+			result.Affinity = append(result.Affinity, term)
+		}
+	}
+	return result
+}
+
+// fits checks the last component of c against the components before it, both the rules of the last component
+// and the rules of the others that refer to it. The earlier components are checked when c is grown.
+func (a *componentAffinity) fits(c combination) bool {
+	last := len(c) - 1
+	for i := 0; i < last; i++ {
+		if !a.fitsTerms(c, last, i) || !a.fitsTerms(c, i, last) {
+			return false
+		}
+	}
+	return true
+}
+
+// fitsTerms checks the rules of component i that refer to component j.
+func (a *componentAffinity) fitsTerms(c combination, i, j int) bool {
+	affinity := a.affinities[i]
+	if affinity == nil {
+		return true
+	}
+	name := a.names[j]
+	for _, term := range affinity.Affinity {
+		if refersTo(term, name) && !a.colocated(c[i], c[j], term.TopologyKey) {
+			return false
+		}
+	}
+	for _, term := range affinity.AntiAffinity {
+		if refersTo(term, name) && a.shareDomain(c[i], c[j], term.TopologyKey) {
+			return false
+		}
+	}
+	return true
+}
+
+// colocated returns true if every cluster with replicas in plan shares a domain with a cluster that has
+// replicas in other.
+func (a *componentAffinity) colocated(plan, other []float64, key appv1alpha1.ComponentTopologyKey) bool {
+	domains := a.domainsOf(key)
+	otherDomains := unionDomains(domains, other)
+	for k, replicas := range plan {
+		if replicas > 0 && !domains[k].HasAny(otherDomains.UnsortedList()...) {
+			return false
+		}
+	}
+	return true
+}
+
+// shareDomain returns true if any cluster with replicas in plan shares a domain with a cluster that has
+// replicas in other.
+func (a *componentAffinity) shareDomain(plan, other []float64, key appv1alpha1.ComponentTopologyKey) bool {
+	domains := a.domainsOf(key)
+	otherDomains := unionDomains(domains, other)
+	for k, replicas := range plan {
+		if replicas > 0 && domains[k].HasAny(otherDomains.UnsortedList()...) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *componentAffinity) domainsOf(key appv1alpha1.ComponentTopologyKey) []sets.String {
+	if domains, ok := a.domains[key]; ok {
+		return domains
+	}
+	return a.domains[appv1alpha1.ComponentTopologyCluster]
+}
+
+// unionDomains returns the domains of all clusters with replicas in plan.
+func unionDomains(domains []sets.String, plan []float64) sets.String {
+	result := sets.NewString()
+	for k, replicas := range plan {
+		if replicas > 0 {
+			result = result.Union(domains[k])
+		}
+	}
+	return result
+}
+
+func refersTo(term appv1alpha1.ComponentAffinityTerm, name string) bool {
+	for _, item := range term.Components {
+		if item == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package algorithm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"gonum.org/v1/gonum/mat"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	platformv1alpha1 "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+)
+
+type AffinitySuite struct {
+	clusters []*platformv1alpha1.ManagedCluster
+	plans    []mat.Matrix
+	suite.Suite
+}
+
+func (suite *AffinitySuite) SetupTest() {
+	geo := func(name, location string) *platformv1alpha1.ManagedCluster {
+		return &platformv1alpha1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{platformv1alpha1.ParsedGeoLocationKey: location},
+		}}
+	}
+	suite.clusters = []*platformv1alpha1.ManagedCluster{geo("c0", "beijing"), geo("c1", "beijing"), geo("c2", "shanghai")}
+	// every component has one plan on each cluster.
+	onEach := mat.NewDense(3, 3, []float64{
+		2, 0, 0,
+		0, 2, 0,
+		0, 0, 2,
+	})
+	suite.plans = []mat.Matrix{onEach, onEach}
+}
+
+func (suite *AffinitySuite) newDescription(affinity *v1alpha1.ComponentAffinity) *v1alpha1.Description {
+	return &v1alpha1.Description{
+		ObjectMeta: metav1.ObjectMeta{Name: "desc"},
+		Spec: v1alpha1.DescriptionSpec{Components: []v1alpha1.Component{
+			{Name: "a"},
+			{Name: "b", SchedulePolicy: v1alpha1.SchedulePolicy{Affinity: affinity}},
+		}},
+	}
+}
+
+func (suite *AffinitySuite) TestAffinity() {
+	desc := suite.newDescription(&v1alpha1.ComponentAffinity{
+		Affinity: []v1alpha1.ComponentAffinityTerm{{Components: []string{"a"}}},
+	})
+	result, err := combineComponentPlans(context.TODO(), nil, suite.plans, suite.clusters, desc, 100)
+	suite.NoError(err)
+	suite.Len(result, 3, "b lands in the same cluster as a")
+	for _, item := range result {
+		suite.Equal(item[0], item[1])
+	}
+}
+
+func (suite *AffinitySuite) TestAntiAffinityOnGeoLocation() {
+	desc := suite.newDescription(&v1alpha1.ComponentAffinity{
+		AntiAffinity: []v1alpha1.ComponentAffinityTerm{{
+			Components:  []string{"a"},
+			TopologyKey: v1alpha1.ComponentTopologyGeoLocation,
+		}},
+	})
+	result, err := combineComponentPlans(context.TODO(), nil, suite.plans, suite.clusters, desc, 100)
+	suite.NoError(err)
+	suite.Len(result, 4, "a and b never share beijing or shanghai")
+
+	desc.Spec.Components[1].SchedulePolicy.Affinity.AntiAffinity[0].TopologyKey = v1alpha1.ComponentTopologyCluster
+	result, err = combineComponentPlans(context.TODO(), nil, suite.plans, suite.clusters, desc, 100)
+	suite.NoError(err)
+	suite.Len(result, 6, "a and b only stay out of each other's cluster")
+}
+
+func (suite *AffinitySuite) TestUnsatisfiable() {
+	desc := suite.newDescription(&v1alpha1.ComponentAffinity{
+		Affinity:     []v1alpha1.ComponentAffinityTerm{{Components: []string{"a"}}},
+		AntiAffinity: []v1alpha1.ComponentAffinityTerm{{Components: []string{"a"}}},
+	})
+	_, err := combineComponentPlans(context.TODO(), nil, suite.plans, suite.clusters, desc, 100)
+	suite.ErrorIs(err, ErrComponentAffinity)
+}
+
+func (suite *AffinitySuite) TestMandatoryConditions() {
+	desc := suite.newDescription(nil)
+	desc.Spec.DeploymentCondition.Mandatory = []v1alpha1.Condition{{
+		Subject:  v1alpha1.Xject{Name: "b", Type: v1alpha1.XjectTypeComponent},
+		Object:   v1alpha1.Xject{Name: "a", Type: v1alpha1.XjectTypeComponent},
+		Relation: v1alpha1.RelationNear,
+	}}
+	result, err := combineComponentPlans(context.TODO(), nil, suite.plans, suite.clusters, desc, 100)
+	suite.NoError(err)
+	suite.Len(result, 5, "b lands in the geolocation of a")
+
+	desc.Spec.DeploymentCondition.Mandatory[0].Relation = v1alpha1.RelationNotSameAs
+	result, err = combineComponentPlans(context.TODO(), nil, suite.plans, suite.clusters, desc, 100)
+	suite.NoError(err)
+	suite.Len(result, 6)
+
+	desc.Spec.DeploymentCondition.Mandatory[0].Object = v1alpha1.Xject{Type: v1alpha1.XjectTypeGeoLocation}
+	suite.Nil(newComponentAffinity(desc, suite.clusters), "conditions on clusters are left to the filter plugins")
+}
+
+func TestAffinity(t *testing.T) {
+	suite.Run(t, new(AffinitySuite))
+}
@@ -1,17 +1,12 @@
 package algorithm
 
 import (
-	"errors"
 	"fmt"
-	"math"
-	"math/rand"
-	"reflect"
-	"sort"
 	"time"
 
 	"gonum.org/v1/gonum/mat"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	appv1alpha1 "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
 	"github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
@@ -26,60 +21,62 @@ const (
 	DefaultMemoryRequest int64 = 200 * 1024 * 1024 // 200 MB
 )
 
-func scheduleWorkload(cpu int64, mem int64, clusters []*v1alpha1.ManagedCluster) ([]*framework.ClusterInfo, int64) {
+// spreadLevelFull means spreading replicas over all clusters that have capacity.
+const spreadLevelFull int64 = 0
+
+// defaultSpreadLevels are the spread levels every component is planned on: full level, 2 level, 1 level.
+var defaultSpreadLevels = []int64{spreadLevelFull, 2, 1}
+
+func scheduleWorkload(requests corev1.ResourceList, clusters []*v1alpha1.ManagedCluster) ([]*framework.ClusterInfo, int64) {
 	result := make([]*framework.ClusterInfo, len(clusters))
 	total := int64(0)
 	for i, cluster := range clusters {
 		clusterInfo := &framework.ClusterInfo{
 			Cluster: cluster,
 		}
-		clusterCapacity := clusterInfo.CalculateCapacity(cpu, mem)
+		clusterCapacity := clusterInfo.CalculateCapacity(requests)
 		clusterInfo.Total = clusterCapacity
 		result[i] = clusterInfo
 	}
 	return result, total
 }
 
-// spawn a brank new resourcebindings on multi spread level.
-func spawnResourceBindings(ins [][]mat.Matrix, allClusters []*v1alpha1.ManagedCluster, desc *appv1alpha1.Description) []*appv1alpha1.ResourceBinding {
-	result := make([]*appv1alpha1.ResourceBinding, 0)
-	matResult := make([]mat.Matrix, 0)
-	rbIndex := 0
-	rbLabels := fillRBLabels(desc)
-	rbLabels[common.GaiaDescriptionLabel] = desc.Name
-	for _, items := range ins {
-		indexMatrix, err := makeResourceBindingMatrix(items)
-		if err != nil {
-			// leave this matrix alone.
-			continue
+// countCapableClusters returns how many clusters can hold at least one replica.
+func countCapableClusters(clusters []*framework.ClusterInfo) int {
+	count := 0
+	for _, item := range clusters {
+		if item != nil && item.Total > 0 {
+			count++
 		}
-		for _, item := range indexMatrix {
-			rb := &appv1alpha1.ResourceBinding{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:   fmt.Sprintf("%s-rs-%d", desc.Name, rbIndex),
-					Labels: rbLabels,
-				},
-				Spec: appv1alpha1.ResourceBindingSpec{
-					AppID:  desc.Name,
-					RbApps: spawnResourceBindingApps(item, allClusters, desc),
-				},
-			}
-			rb.Kind = "ResourceBinding"
-			rb.APIVersion = "apps.gaia.io/v1alpha1"
-			if checkContainMatrix(matResult, item) {
-				continue
-			}
-			rbIndex += 1
-			matResult = append(matResult, item)
-			result = append(result, rb)
-			// TODO only return top 10 rbs. not so random
-			// TODO change me!!!
-			if rbIndex == 5 {
-				return result
-			}
+	}
+	return count
+}
+
+// getSpreadLevels resolves defaultSpreadLevels into the number of clusters a component is spread over on each level.
+// dispersion is the minimum number of distinct clusters the component asks for, it raises every lower level,
+// and it is capped by replicas because a cluster without replicas doesn't count, so a component without replicas
+// has no dispersion to meet.
+func getSpreadLevels(capableClusters int, dispersion, replicas int64) ([]int64, error) {
+	minimum := dispersion
+	if minimum > replicas {
+		minimum = replicas
+	}
+	if minimum > int64(capableClusters) {
+		return nil, fmt.Errorf("%w: dispersion %d needs %d clusters but only %d clusters have capacity",
+			ErrInfeasiblePlan, dispersion, minimum, capableClusters)
+	}
+
+	result := make([]int64, len(defaultSpreadLevels))
+	for i, level := range defaultSpreadLevels {
+		if level == spreadLevelFull || level > int64(capableClusters) {
+			level = int64(capableClusters)
+		}
+		if level < minimum {
+			level = minimum
 		}
+		result[i] = level
 	}
-	return result
+	return result, nil
 }
 
 func fillRBLabels(desc *appv1alpha1.Description) map[string]string {
@@ -157,92 +154,21 @@ func makeServelessPlan(capability []*framework.ClusterInfo, replicas int64) mat.
 	return result
 }
 
-// makeDeployPlans make plans for specific component
-func makeDeployPlans(capability []*framework.ClusterInfo, componentTotal, spreadOver int64) mat.Matrix {
+// makeDeployPlans make plans for specific component, every plan spreads componentTotal replicas over spreadOver clusters.
+func makeDeployPlans(capability []*framework.ClusterInfo, componentTotal, spreadOver int64) (mat.Matrix, error) {
 	if componentTotal == 0 {
 		result := mat.NewDense(1, len(capability), nil)
 		result.Zero()
-		return result
-	}
-	// 1. check param,
-	if int(spreadOver) >= len(capability) {
-		result := mat.NewDense(1, len(capability), nil)
-		result.SetRow(0, plan(capability, componentTotal))
-		return result
-	} else {
-		result := mat.NewDense(2, len(capability), nil)
-		result.Zero()
-		// 2. random 2 	may be duplicate but have no way to avoid.
-		i := 0
-		for i < 2 {
-			randomClusterInfo, err := GenerateRandomClusterInfos(capability, int(spreadOver))
-			availableCountForThisPlan := int64(0)
-			if randomClusterInfo != nil {
-				for _, item := range randomClusterInfo {
-					if item != nil {
-						availableCountForThisPlan += item.Total
-					}
-				}
-			}
-			// we get a unavailable plan
-			if err != nil || availableCountForThisPlan < componentTotal {
-				continue
-			} else {
-				r := plan(randomClusterInfo, componentTotal)
-				result.SetRow(i, r)
-				i += 1
-			}
-		}
-		return result
-	}
-}
-
-// make matrix from component matrix on same level, 1, n, full, return one schedule result.
-func makeResourceBindingMatrix(in []mat.Matrix) ([]mat.Matrix, error) {
-	totalCount := 1
-	for i := 0; i < len(in); i++ {
-		if in[i] == nil {
-			return nil, errors.New("can't produce logic matrix")
-		}
-		aR, _ := in[i].Dims()
-		totalCount = totalCount * aR
-	}
-
-	// only one component.
-	result := make([]mat.Matrix, totalCount)
-	aR, aC := in[0].Dims()
-	for i := 0; i < aR; i++ {
-		row := mat.DenseCopyOf(in[0]).RawRowView(i)
-		dens := mat.NewDense(1, aC, nil)
-		dens.SetRow(0, row)
-		result[i] = dens
-	}
-	if len(in) == 1 {
 		return result, nil
 	}
-
-	for i := 1; i < len(in); i++ {
-		bR, _ := in[i].Dims()
-		stride := 0
-		tempResult := make([]mat.Matrix, len(result))
-		reflect.Copy(reflect.ValueOf(tempResult), reflect.ValueOf(result))
-		for j := 0; j < bR; j++ {
-			row := mat.DenseCopyOf(in[i]).RawRowView(j)
-			for index, item := range tempResult {
-				// not initialed so we stop
-				if item == nil {
-					stride = index
-					break
-				}
-				tempMatBeforeGrow := mat.DenseCopyOf(item).Grow(1, 0)
-				tempRowCount, _ := tempMatBeforeGrow.Dims()
-				tempMatGrow := mat.DenseCopyOf(tempMatBeforeGrow)
-				tempMatGrow.SetRow(tempRowCount-1, row)
-				result[j*stride+index] = tempMatGrow
-			}
-		}
+	plans, err := placePlans(capability, componentTotal, int(spreadOver), maxPlansPerSpreadLevel)
+	if err != nil {
+		return nil, err
+	}
+	result := mat.NewDense(len(plans), len(capability), nil)
+	for i, row := range plans {
+		result.SetRow(i, row)
 	}
-
 	return result, nil
 }
 
@@ -263,18 +189,34 @@ func getComponentClusterTotal(rbApps []*appv1alpha1.ResourceBindingApps, cluster
 	return 0
 }
 
-func calculateResource(templateSpec corev1.PodTemplateSpec) (non0CPU int64, non0Mem int64, pod *corev1.Pod) {
+// calculateResource aggregates the requests of all containers of the template. cpu and memory fall back to
+// the non-zero defaults, every other resource (ephemeral storage, hugepages, extended resources like
+// nvidia.com/gpu) is taken as requested. A resource with a limit but no request requests its limit,
+// the same as the apiserver defaults pods.
+func calculateResource(templateSpec corev1.PodTemplateSpec) (requests corev1.ResourceList, pod *corev1.Pod) {
 
 	pod = &corev1.Pod{
 		ObjectMeta: templateSpec.ObjectMeta,
 		Spec:       templateSpec.Spec,
 	}
 
+	var non0CPU, non0Mem int64
+	requests = make(corev1.ResourceList)
 	for _, c := range templateSpec.Spec.Containers {
-		non0CPUReq, non0MemReq := GetNonzeroRequests(&c.Resources.Requests)
+		containerRequests := c.Resources.Requests.DeepCopy()
+		if containerRequests == nil {
+			containerRequests = make(corev1.ResourceList)
+		}
+		for name, quantity := range c.Resources.Limits {
+			if _, found := containerRequests[name]; !found {
+				containerRequests[name] = quantity.DeepCopy()
+			}
+		}
+
+		non0CPUReq, non0MemReq := GetNonzeroRequests(&containerRequests)
 		non0CPU += non0CPUReq
 		non0Mem += non0MemReq
-		// No non-zero resources for GPUs or opaque resources.
+		addResourceList(requests, containerRequests)
 	}
 
 	// If Overhead is being utilized, add to the total requests for the pod
@@ -286,93 +228,112 @@ func calculateResource(templateSpec corev1.PodTemplateSpec) (non0CPU int64, non0
 		if _, found := templateSpec.Spec.Overhead[corev1.ResourceMemory]; found {
 			non0Mem += templateSpec.Spec.Overhead.Memory().Value()
 		}
+		addResourceList(requests, templateSpec.Spec.Overhead)
 	}
 
+	requests[corev1.ResourceCPU] = *resource.NewMilliQuantity(non0CPU, resource.DecimalSI)
+	requests[corev1.ResourceMemory] = *resource.NewQuantity(non0Mem, resource.BinarySI)
 	return
 }
 
-// plan https://www.jianshu.com/p/12b89147993c
-func plan(weight []*framework.ClusterInfo, request int64) []float64 {
-	sum := int64(0)
-	percent := make([]float64, 0)
-	firstAssign := make([]float64, 0)
-	finalAssign := make([]float64, 0)
-	targetF := make([]float64, 0)
-	totalFirstAssign := float64(0)
-
-	for _, weight := range weight {
-		if weight != nil {
-			sum += weight.Total
-		}
-	}
-
-	for _, weight := range weight {
-		currentTotal := int64(0)
-		if weight != nil {
-			currentTotal = weight.Total
-		}
-		currentPercent := float64(currentTotal) / float64(sum)
-		currentAssign := currentPercent * float64(request)
-		currentFinalAssign := math.Floor(currentAssign)
-		totalFirstAssign += currentFinalAssign
-		percent = append(percent, currentPercent)
-		firstAssign = append(firstAssign, float64(request)*currentPercent)
-		if currentTotal != 0 {
-			targetF = append(targetF, float64(sum)/float64(request)*(1+(currentAssign-math.Floor(currentAssign))/math.Floor(currentAssign)))
+// addResourceList adds the resources in delta to list, cpu and memory included.
+func addResourceList(list, delta corev1.ResourceList) {
+	for name, quantity := range delta {
+		if value, ok := list[name]; ok {
+			value.Add(quantity)
+			list[name] = value
 		} else {
-			targetF = append(targetF, float64(0))
+			list[name] = quantity.DeepCopy()
 		}
-
-		finalAssign = append(finalAssign, currentFinalAssign)
-	}
-	// f=np.sum(arr)/n*(1+(arg-np.floor(arg))/np.floor(arg))
-	unassginLeft := request - int64(totalFirstAssign)
-	ord := ArgsortNew(targetF)
-	for i := 0; i < int(unassginLeft); i++ {
-		finalAssign[ord[len(ord)-i-1]] += 1
 	}
-	return finalAssign
 }
 
-// argsort, like in Numpy, it returns an array of indexes into an array. Note
-// that the gonum version of argsort reorders the original array and returns
-// indexes to reconstruct the original order.
-type argsort struct {
-	s    []float64 // Points to orignal array but does NOT alter it.
-	inds []int     // Indexes to be returned.
+// AssumedRequests returns the resources rbs request on each cluster, keyed by cluster name. The rbs of a
+// description are alternatives of each other, so the largest request among them is taken for every resource
+// of every cluster. A description from a parent cluster is placed by the children of the rb apps of selfClusterName.
+func AssumedRequests(desc *appv1alpha1.Description, rbs []*appv1alpha1.ResourceBinding, selfClusterName string) map[string]corev1.ResourceList {
+	return assumedRequests(ComponentRequests(desc), desc, rbs, selfClusterName)
 }
 
-func (a argsort) Len() int {
-	return len(a.s)
-}
-
-func (a argsort) Less(i, j int) bool {
-	return a.s[a.inds[i]] < a.s[a.inds[j]]
+// PreoccupiedRequests returns the resources the preoccupying components of desc request on each cluster the
+// same way as AssumedRequests, and the longest preoccupy among them. Nothing is returned if no component
+// preoccupies.
+func PreoccupiedRequests(desc *appv1alpha1.Description, rbs []*appv1alpha1.ResourceBinding, selfClusterName string) (map[string]corev1.ResourceList, time.Duration) {
+	var longest time.Duration
+	requestsOfComponents := ComponentRequests(desc)
+	for name := range requestsOfComponents {
+		duration := desc.PreoccupyDuration(name)
+		if duration == 0 {
+			delete(requestsOfComponents, name)
+		} else if duration > longest {
+			longest = duration
+		}
+	}
+	if longest == 0 {
+		return nil, 0
+	}
+	return assumedRequests(requestsOfComponents, desc, rbs, selfClusterName), longest
 }
 
-func (a argsort) Swap(i, j int) {
-	a.inds[i], a.inds[j] = a.inds[j], a.inds[i]
+func assumedRequests(requestsOfComponents map[string]corev1.ResourceList, desc *appv1alpha1.Description,
+	rbs []*appv1alpha1.ResourceBinding, selfClusterName string) map[string]corev1.ResourceList {
+	result := make(map[string]corev1.ResourceList)
+	for _, rb := range rbs {
+		rbApps := rb.Spec.RbApps
+		if desc.Namespace != common.GaiaReservedNamespace {
+			rbApps = nil
+			for _, rbApp := range rb.Spec.RbApps {
+				if rbApp.ClusterName == selfClusterName {
+					rbApps = rbApp.Children
+					break
+				}
+			}
+		}
+		for clusterName, requests := range rbAppsRequests(requestsOfComponents, rbApps) {
+			current, ok := result[clusterName]
+			if !ok {
+				result[clusterName] = requests
+				continue
+			}
+			for name, quantity := range requests {
+				if value, found := current[name]; !found || quantity.Cmp(value) > 0 {
+					current[name] = quantity
+				}
+			}
+		}
+	}
+	return result
 }
 
-// ArgsortNew allocates and returns an array of indexes into the source float
-// array.
-func ArgsortNew(src []float64) []int {
-	inds := make([]int, len(src))
-	for i := range src {
-		inds[i] = i
+// ComponentRequests returns the resources one replica of every component of desc requests, keyed by component name.
+func ComponentRequests(desc *appv1alpha1.Description) map[string]corev1.ResourceList {
+	result := make(map[string]corev1.ResourceList, len(desc.Spec.Components))
+	for i := range desc.Spec.Components {
+		result[desc.Spec.Components[i].Name] = replicaRequests(desc, &desc.Spec.Components[i])
 	}
-	Argsort(src, inds)
-	return inds
+	return result
 }
 
-// Argsort alters a caller-allocated array of indexes into the source float
-// array. The indexes must already have values 0...n-1.
-func Argsort(src []float64, inds []int) {
-	if len(src) != len(inds) {
-		panic("floats: length of inds does not match length of slice")
+// rbAppsRequests returns the resources all components of rbApps request on each cluster, keyed by cluster name.
+// Clusters without replicas are omitted.
+func rbAppsRequests(requestsOfComponents map[string]corev1.ResourceList,
+	rbApps []*appv1alpha1.ResourceBindingApps) map[string]corev1.ResourceList {
+	result := make(map[string]corev1.ResourceList)
+	for _, rbApp := range rbApps {
+		requests := make(corev1.ResourceList)
+		for name, replicas := range rbApp.Replicas {
+			for resourceName, quantity := range requestsOfComponents[name] {
+				value := requests[resourceName]
+				value.Add(*resource.NewMilliQuantity(quantity.MilliValue()*int64(replicas), quantity.Format))
+				requests[resourceName] = value
+			}
+		}
+		if requests.Cpu().IsZero() && requests.Memory().IsZero() {
+			continue
+		}
+		result[rbApp.ClusterName] = requests
 	}
-	a := argsort{s: src, inds: inds}
-	sort.Sort(a)
+	return result
 }
 
 // GetNonzeroRequests returns the default cpu and memory resource request if none is found or
@@ -408,54 +369,3 @@ func GetNonzeroRequestForResource(resource corev1.ResourceName, requests *corev1
 		return 0
 	}
 }
-
-func GenerateRandomClusterInfos(capacities []*framework.ClusterInfo, count int) ([]*framework.ClusterInfo, error) {
-	nonZeroCount := 0
-	for _, item := range capacities {
-		if item.Total != 0 {
-			nonZeroCount += 1
-		}
-	}
-	if nonZeroCount < count {
-		return nil, errors.New("math: can't allocate desired count into clusters")
-	} else if nonZeroCount == count {
-		return capacities, nil
-	} else {
-		nums := make(map[int]struct{}, 0)
-		randomCapacity := make([]*framework.ClusterInfo, len(capacities))
-		r := rand.New(rand.NewSource(time.Now().UnixNano()))
-		for len(nums) < count {
-			num := r.Intn(len(capacities))
-			if _, ok := nums[num]; ok {
-				// we have chosen this cluster.
-				continue
-			} else {
-				if capacities[num].Total > 0 {
-					nums[num] = struct{}{}
-				} else {
-					continue
-				}
-			}
-		}
-
-		for i, item := range capacities {
-			if _, ok := nums[i]; ok {
-				randomCapacity[i] = item
-			} else {
-				randomCapacity[i] = nil
-			}
-		}
-
-		return randomCapacity, nil
-	}
-}
-
-// if mat in matrices
-func checkContainMatrix(matrices []mat.Matrix, matix mat.Matrix) bool {
-	for _, item := range matrices {
-		if mat.Equal(item, matix) {
-			return true
-		}
-	}
-	return false
-}
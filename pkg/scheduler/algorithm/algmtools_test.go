@@ -1,15 +1,19 @@
 package algorithm
 
 import (
-	"fmt"
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 	"gonum.org/v1/gonum/mat"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
 	platformv1alpha1 "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+	"github.com/lmxia/gaia/pkg/common"
 	"github.com/lmxia/gaia/pkg/scheduler/framework"
 )
 
@@ -181,9 +185,22 @@ func (suite *DeploymentSuite) SetupTest() {
 	}
 }
 
-func (suite *DeploymentSuite) TestMakeResourceBindingMatrix() {
-	result, _ := makeResourceBindingMatrix(suite.matries)
-	suite.Equal(24, len(result), "")
+func (suite *DeploymentSuite) TestCombineComponentPlans() {
+	desc := &v1alpha1.Description{
+		ObjectMeta: metav1.ObjectMeta{Name: "desc"},
+		Spec: v1alpha1.DescriptionSpec{
+			Components: []v1alpha1.Component{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}},
+		},
+	}
+	result, _ := combineComponentPlans(context.TODO(), nil, suite.matries, nil, desc, 100)
+	suite.Equal(24, len(result), "all combinations fit in a wide beam")
+
+	result, _ = combineComponentPlans(context.TODO(), nil, suite.matries, nil, desc, 4)
+	suite.Equal(4, len(result), "narrow beam is pruned")
+	suite.Equal(4, len(result[0]), "one plan for every component")
+
+	_, err := combineComponentPlans(context.TODO(), nil, []mat.Matrix{suite.matries[0], nil}, nil, desc, 4)
+	suite.Error(err, "component without plan")
 }
 
 func (suite *DeploymentSuite) TestGetComponentClusterTotal() {
@@ -192,24 +209,214 @@ func (suite *DeploymentSuite) TestGetComponentClusterTotal() {
 }
 
 func (suite *DeploymentSuite) TestCalculatePlans() {
-	mat := makeDeployPlans(suite.capability, 10, 1)
-	fmt.Printf("%v", mat)
-	suite.Equal(5, 5, "test")
+	result, err := makeDeployPlans(suite.capability, 10, 1)
+	suite.NoError(err)
+	suite.Equal([]float64{0, 10, 0, 0, 0}, mat.Row(nil, 0, result), "biggest cluster first")
+	suite.Equal([]float64{0, 0, 10, 0, 0}, mat.Row(nil, 1, result))
+
+	again, _ := makeDeployPlans(suite.capability, 10, 1)
+	suite.True(mat.Equal(result, again), "plans are repeatable")
+}
+
+func (suite *DeploymentSuite) TestCalculatePlansInfeasible() {
+	_, err := makeDeployPlans(suite.capability, 50, 1)
+	suite.ErrorIs(err, ErrInfeasiblePlan)
+
+	_, err = makeDeployPlans(suite.capability, 41, 2)
+	suite.ErrorIs(err, ErrInfeasiblePlan)
+}
+
+func (suite *DeploymentSuite) TestAllocateReplicas() {
+	result := allocateReplicas(suite.capability, []int{1, 2}, 40)
+	suite.Equal([]float64{0, 30, 10, 0, 0}, result, "never over capacity")
+
+	result = allocateReplicas(suite.capability, []int{1, 2}, 2)
+	suite.Equal([]float64{0, 1, 1, 0, 0}, result, "every cluster gets one")
+
+	result = allocateReplicas(suite.capability, []int{1, 2}, 9)
+	suite.Equal(float64(9), result[1]+result[2])
+}
+
+func (suite *DeploymentSuite) TestGetSpreadLevels() {
+	levels, err := getSpreadLevels(countCapableClusters(suite.capability), 1, 10)
+	suite.NoError(err)
+	suite.Equal([]int64{2, 2, 1}, levels, "full level means all capable clusters")
+
+	levels, err = getSpreadLevels(4, 3, 10)
+	suite.NoError(err)
+	suite.Equal([]int64{4, 3, 3}, levels, "dispersion raises lower levels")
+
+	levels, err = getSpreadLevels(4, 3, 2)
+	suite.NoError(err)
+	suite.Equal([]int64{4, 2, 2}, levels, "dispersion is capped by replicas")
+
+	levels, err = getSpreadLevels(1, 3, 0)
+	suite.NoError(err, "a component without replicas has no dispersion to meet")
+	suite.Equal([]int64{1, 1, 1}, levels)
+
+	_, err = getSpreadLevels(countCapableClusters(suite.capability), 3, 10)
+	suite.Error(err, "not enough clusters for dispersion")
+}
+
+func (suite *DeploymentSuite) TestCalculatePlansWithDispersion() {
+	result, err := makeDeployPlans(suite.capability, 10, 2)
+	suite.NoError(err)
+	r, _ := result.Dims()
+	suite.Equal(1, r, "only one group of two clusters has capacity")
+	suite.Equal([]float64{0, 8, 2, 0, 0}, mat.Row(nil, 0, result), "in proportion to capacity")
+}
+
+func (suite *DeploymentSuite) TestPlacePlansWithFewReplicas() {
+	plans, err := placePlans(suite.capability, 1, 2, maxPlansPerSpreadLevel)
+	suite.NoError(err)
+	suite.Len(plans, 2)
+	suite.NotEqual(plans[0], plans[1], "plans are distinct")
+	for _, plan := range plans {
+		suite.Equal(float64(1), mat.Sum(mat.NewVecDense(len(plan), plan)))
+	}
 }
 
-func (suite *DeploymentSuite) TestGenerateRandomNumber() {
-	result, err := GenerateRandomClusterInfos(suite.capability, 1)
-	if err != nil {
-		fmt.Printf("%v", result)
+func (suite *DeploymentSuite) TestAssumedRequests() {
+	container := corev1.Container{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("1Gi"),
+	}}}
+	desc := &v1alpha1.Description{
+		ObjectMeta: metav1.ObjectMeta{Name: "desc", Namespace: common.GaiaReservedNamespace},
+		Spec: v1alpha1.DescriptionSpec{Components: []v1alpha1.Component{
+			{Name: "web", Module: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{container}}}},
+		}},
+	}
+	rbs := []*v1alpha1.ResourceBinding{
+		{Spec: v1alpha1.ResourceBindingSpec{RbApps: []*v1alpha1.ResourceBindingApps{
+			{ClusterName: "cluster1", Replicas: map[string]int32{"web": 2}},
+			{ClusterName: "cluster2", Replicas: map[string]int32{"web": 0}},
+		}}},
+		{Spec: v1alpha1.ResourceBindingSpec{RbApps: []*v1alpha1.ResourceBindingApps{
+			{ClusterName: "cluster1", Replicas: map[string]int32{"web": 3}},
+		}}},
+	}
+
+	requests := AssumedRequests(desc, rbs, "")
+	suite.Len(requests, 1, "clusters without replicas take nothing")
+	cluster1 := requests["cluster1"]
+	suite.Equal(int64(1500), cluster1.Cpu().MilliValue(), "the largest request among alternatives")
+	suite.Equal(int64(3*1024*1024*1024), cluster1.Memory().Value())
+
+	desc.Namespace = "gaia-parent"
+	rbs = []*v1alpha1.ResourceBinding{
+		{Spec: v1alpha1.ResourceBindingSpec{RbApps: []*v1alpha1.ResourceBindingApps{
+			{ClusterName: "self", Replicas: map[string]int32{"web": 4}, Children: []*v1alpha1.ResourceBindingApps{
+				{ClusterName: "child", Replicas: map[string]int32{"web": 1}},
+			}},
+			{ClusterName: "other", Replicas: map[string]int32{"web": 4}},
+		}}},
+	}
+	requests = AssumedRequests(desc, rbs, "self")
+	suite.Len(requests, 1, "only the children of the self cluster are placed by this scheduler")
+	child := requests["child"]
+	suite.Equal(int64(500), child.Cpu().MilliValue())
+}
+
+func (suite *DeploymentSuite) TestPreoccupiedRequests() {
+	module := corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+		Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}},
+	}}}}
+	desc := &v1alpha1.Description{
+		ObjectMeta: metav1.ObjectMeta{Name: "desc", Namespace: common.GaiaReservedNamespace},
+		Spec: v1alpha1.DescriptionSpec{
+			Components:         []v1alpha1.Component{{Name: "web", Module: module}, {Name: "db", Module: module}},
+			WorkloadComponents: []v1alpha1.WorkloadComponent{{ComponentName: "db", Preoccupy: "0"}},
+		},
+	}
+	rbs := []*v1alpha1.ResourceBinding{
+		{Spec: v1alpha1.ResourceBindingSpec{RbApps: []*v1alpha1.ResourceBindingApps{
+			{ClusterName: "cluster1", Replicas: map[string]int32{"web": 2, "db": 1}},
+		}}},
 	}
 
-	suite.Equal(len(result), 5, "test")
+	requests, duration := PreoccupiedRequests(desc, rbs, "")
+	suite.Nil(requests, "nothing is preoccupied without a preoccupy")
+	suite.Zero(duration)
+
+	desc.Spec.Preoccupy = "10m"
+	requests, duration = PreoccupiedRequests(desc, rbs, "")
+	suite.Equal(10*time.Minute, duration)
+	cluster1 := requests["cluster1"]
+	suite.Equal(int64(2000), cluster1.Cpu().MilliValue(), "db opts out of the preoccupy of the description")
 }
 
-func (suite *DeploymentSuite) TestPlan() {
-	result := plan(suite.capability, 5)
-	fmt.Printf("plan result is %v", result)
-	suite.Equal(3, 3, "test")
+func (suite *DeploymentSuite) TestScheduleWorkloadOnExtendedResources() {
+	template := corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+		{Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")}}},
+		{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			corev1.ResourceCPU:              resource.MustParse("1"),
+			corev1.ResourceEphemeralStorage: resource.MustParse("1Gi"),
+		}}},
+	}}}
+	requests, _ := calculateResource(template)
+	gpu := requests["nvidia.com/gpu"]
+	suite.Equal(int64(1), gpu.Value(), "limits are requested when there is no request")
+	suite.Equal(int64(1100), requests.Cpu().MilliValue(), "default cpu for the container without request")
+	suite.Equal(DefaultMemoryRequest*2, requests.Memory().Value())
+
+	clusters := []*platformv1alpha1.ManagedCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cpu-only"}, Status: platformv1alpha1.ManagedClusterStatus{
+			Available: corev1.ResourceList{
+				corev1.ResourceCPU:              resource.MustParse("64"),
+				corev1.ResourceMemory:           resource.MustParse("256Gi"),
+				corev1.ResourceEphemeralStorage: resource.MustParse("1Ti"),
+			},
+		}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "gpu"}, Status: platformv1alpha1.ManagedClusterStatus{
+			Available: corev1.ResourceList{
+				corev1.ResourceCPU:              resource.MustParse("64"),
+				corev1.ResourceMemory:           resource.MustParse("256Gi"),
+				corev1.ResourceEphemeralStorage: resource.MustParse("1Ti"),
+				"nvidia.com/gpu":                resource.MustParse("4"),
+			},
+		}},
+	}
+	result, _ := scheduleWorkload(requests, clusters)
+	suite.Equal(int64(0), result[0].Total, "a cluster without gpu holds nothing")
+	suite.Equal(int64(4), result[1].Total, "gpu is the scarcest resource")
+
+	clusters[1].Status.MaxNodeAvailable = corev1.ResourceList{
+		corev1.ResourceCPU: resource.MustParse("1"),
+		"nvidia.com/gpu":   resource.MustParse("4"),
+	}
+	result, _ = scheduleWorkload(requests, clusters)
+	suite.Equal(int64(0), result[1].Total, "a replica larger than any single node fits nowhere")
+}
+
+func (suite *DeploymentSuite) TestScheduleWorkloadOnNonLeafCluster() {
+	requests := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("3"),
+		corev1.ResourceMemory: resource.MustParse("1Gi"),
+	}
+	edge := platformv1alpha1.ClusterResource{Available: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("2"),
+		corev1.ResourceMemory: resource.MustParse("4Gi"),
+	}}
+	cluster := &platformv1alpha1.ManagedCluster{Status: platformv1alpha1.ManagedClusterStatus{
+		Available: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("40"),
+			corev1.ResourceMemory: resource.MustParse("80Gi"),
+		},
+	}}
+	for i := 0; i < 20; i++ {
+		cluster.Status.ClusterResources = append(cluster.Status.ClusterResources, *edge.DeepCopy())
+	}
+	result, _ := scheduleWorkload(requests, []*platformv1alpha1.ManagedCluster{cluster})
+	suite.Equal(int64(0), result[0].Total, "no edge cluster holds a replica")
+
+	cluster.Status.ClusterResources[0].Available[corev1.ResourceCPU] = resource.MustParse("7")
+	result, _ = scheduleWorkload(requests, []*platformv1alpha1.ManagedCluster{cluster})
+	suite.Equal(int64(2), result[0].Total)
+
+	cluster.Status.Available[corev1.ResourceCPU] = resource.MustParse("3")
+	result, _ = scheduleWorkload(requests, []*platformv1alpha1.ManagedCluster{cluster})
+	suite.Equal(int64(1), result[0].Total, "the aggregated resources still cap the leaf clusters")
 }
 
 func TestDeployment(t *testing.T) {
@@ -0,0 +1,169 @@
+package algorithm
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+)
+
+// slaBoundaryTypes are the types of inter boundaries.
+var slaBoundaryTypes = sets.NewString(v1alpha1.BoundaryTypeDelay, v1alpha1.BoundaryTypeLost,
+	v1alpha1.BoundaryTypeJitter, v1alpha1.BoundaryTypeBandwidth)
+
+// resourceBoundaryType returns true if boundaryType is the name of a resource nodes report, like cpu, memory,
+// ephemeral storage, hugepages or an extended resource like nvidia.com/gpu, so it can be an inner boundary.
+func resourceBoundaryType(boundaryType string) bool {
+	switch name := corev1.ResourceName(boundaryType); name {
+	case corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage:
+		return true
+	default:
+		return strings.HasPrefix(string(name), corev1.ResourceHugePagesPrefix) || strings.Contains(string(name), "/")
+	}
+}
+
+// boundaryValue decodes the value of a boundary, a plain number or a quantity like 500m or 1Gi.
+func boundaryValue(boundary v1alpha1.Boundary) (resource.Quantity, error) {
+	value, err := resource.ParseQuantity(strings.TrimSpace(boundary.Value))
+	if err != nil {
+		return value, fmt.Errorf("invalid value %q of boundary %q: %v", boundary.Value, boundary.Name, err)
+	}
+	return value, nil
+}
+
+// replicaRequests returns the resources one replica of comp requests, raised to the inner boundaries of desc.
+func replicaRequests(desc *v1alpha1.Description, comp *v1alpha1.Component) corev1.ResourceList {
+	requests, _ := calculateResource(comp.Module)
+	for _, boundary := range desc.Spec.ExpectedPerformance.Boundaries.Inner {
+		if boundary.Subject != comp.Name || !resourceBoundaryType(boundary.Type) {
+			continue
+		}
+		value, err := boundaryValue(boundary)
+		if err != nil {
+			klog.V(4).InfoS("Skipping inner boundary", "description", klog.KObj(desc), "err", err)
+			continue
+		}
+		name := corev1.ResourceName(boundary.Type)
+		if current, ok := requests[name]; !ok || value.Cmp(current) > 0 {
+			requests[name] = value
+		}
+	}
+	return requests
+}
+
+// withInterBoundaries returns a copy of nwr whose slas of the subject components are tightened by the inter
+// boundaries of desc, so the network filter keeps only the paths within them.
+func withInterBoundaries(desc *v1alpha1.Description, nwr *v1alpha1.NetworkRequirement) *v1alpha1.NetworkRequirement {
+	if nwr == nil || len(desc.Spec.ExpectedPerformance.Boundaries.Inter) == 0 {
+		return nwr
+	}
+	nwr = nwr.DeepCopy()
+	for _, boundary := range desc.Spec.ExpectedPerformance.Boundaries.Inter {
+		value, err := boundaryValue(boundary)
+		if err != nil {
+			klog.V(4).InfoS("Skipping inter boundary", "description", klog.KObj(desc), "err", err)
+			continue
+		}
+		for i := range nwr.Spec.NetworkCommunication {
+			netCom := &nwr.Spec.NetworkCommunication[i]
+			if netCom.Name != boundary.Subject {
+				continue
+			}
+			for j := range netCom.InterSCNID {
+				tightenSla(&netCom.InterSCNID[j].Sla, boundary.Type, value.Value())
+			}
+		}
+	}
+	return nwr
+}
+
+// tightenSla tightens sla by the boundary of boundaryType, zero means no requirement in sla.
+func tightenSla(sla *v1alpha1.AppSlaAttr, boundaryType string, value int64) {
+	atMost := func(current *int32) {
+		if *current == 0 || int32(value) < *current {
+			*current = int32(value)
+		}
+	}
+	switch boundaryType {
+	case v1alpha1.BoundaryTypeDelay:
+		atMost(&sla.Delay)
+	case v1alpha1.BoundaryTypeLost:
+		atMost(&sla.Lost)
+	case v1alpha1.BoundaryTypeJitter:
+		atMost(&sla.Jitter)
+	case v1alpha1.BoundaryTypeBandwidth:
+		if value > sla.Bandwidth {
+			sla.Bandwidth = value
+		}
+	}
+}
+
+// boundaryStatuses returns the state of every boundary of desc as if desc is placed: a boundary the scheduler
+// enforces is met, the others are unknown. nwr is the network requirement the network filter runs with.
+func boundaryStatuses(desc *v1alpha1.Description, nwr *v1alpha1.NetworkRequirement) []v1alpha1.BoundaryStatus {
+	boundaries := desc.Spec.ExpectedPerformance.Boundaries
+	var result []v1alpha1.BoundaryStatus
+	for _, boundary := range boundaries.Inner {
+		result = append(result, boundaryStatus(boundary, innerBoundaryError(desc, boundary)))
+	}
+	for _, boundary := range boundaries.Inter {
+		result = append(result, boundaryStatus(boundary, interBoundaryError(nwr, boundary)))
+	}
+	for _, boundary := range boundaries.Extra {
+		result = append(result, boundaryStatus(boundary, fmt.Errorf("extra boundaries are not enforced by the scheduler")))
+	}
+	return result
+}
+
+func boundaryStatus(boundary v1alpha1.Boundary, err error) v1alpha1.BoundaryStatus {
+	status := v1alpha1.BoundaryStatus{
+		Name:    boundary.Name,
+		Subject: boundary.Subject,
+		Type:    boundary.Type,
+		State:   v1alpha1.BoundaryMet,
+	}
+	if err != nil {
+		status.State = v1alpha1.BoundaryUnknown
+		status.Message = err.Error()
+	}
+	return status
+}
+
+// innerBoundaryError returns why the inner boundary can't be enforced, or nil.
+func innerBoundaryError(desc *v1alpha1.Description, boundary v1alpha1.Boundary) error {
+	if _, err := boundaryValue(boundary); err != nil {
+		return err
+	}
+	if !resourceBoundaryType(boundary.Type) {
+		return fmt.Errorf("unknown inner boundary type %q", boundary.Type)
+	}
+	for _, comp := range desc.Spec.Components {
+		if comp.Name == boundary.Subject {
+			return nil
+		}
+	}
+	return fmt.Errorf("no component %q", boundary.Subject)
+}
+
+// interBoundaryError returns why the inter boundary can't be enforced, or nil.
+func interBoundaryError(nwr *v1alpha1.NetworkRequirement, boundary v1alpha1.Boundary) error {
+	if _, err := boundaryValue(boundary); err != nil {
+		return err
+	}
+	if !slaBoundaryTypes.Has(boundary.Type) {
+		return fmt.Errorf("unknown inter boundary type %q", boundary.Type)
+	}
+	if nwr != nil {
+		for _, netCom := range nwr.Spec.NetworkCommunication {
+			if netCom.Name == boundary.Subject && len(netCom.InterSCNID) > 0 {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("no network requirement of component %q", boundary.Subject)
+}
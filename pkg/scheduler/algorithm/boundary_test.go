@@ -0,0 +1,118 @@
+package algorithm
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+)
+
+type BoundarySuite struct {
+	desc *v1alpha1.Description
+	nwr  *v1alpha1.NetworkRequirement
+	suite.Suite
+}
+
+func (suite *BoundarySuite) SetupTest() {
+	suite.desc = &v1alpha1.Description{
+		ObjectMeta: metav1.ObjectMeta{Name: "desc"},
+		Spec: v1alpha1.DescriptionSpec{
+			Components: []v1alpha1.Component{{
+				Name: "web",
+				Module: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+					{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("1"),
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					}}},
+				}}},
+			}},
+			ExpectedPerformance: v1alpha1.ExpectedPerformance{Boundaries: v1alpha1.Boundaries{
+				Inner: []v1alpha1.Boundary{
+					{Name: "cpu", Subject: "web", Type: "cpu", Value: "2"},
+					{Name: "memory", Subject: "web", Type: "memory", Value: "512Mi"},
+					{Name: "ghost", Subject: "db", Type: "cpu", Value: "1"},
+					{Name: "gpu", Subject: "web", Type: "nvidia.com/gpu", Value: "1"},
+					{Name: "latency", Subject: "web", Type: v1alpha1.BoundaryTypeDelay, Value: "20"},
+				},
+				Inter: []v1alpha1.Boundary{
+					{Name: "delay", Subject: "web", Type: v1alpha1.BoundaryTypeDelay, Value: "20"},
+					{Name: "bandwidth", Subject: "web", Type: v1alpha1.BoundaryTypeBandwidth, Value: "100"},
+					{Name: "cost", Subject: "web", Type: "cost", Value: "10"},
+				},
+				Extra: []v1alpha1.Boundary{{Name: "uptime", Subject: "web", Type: "availability", Value: "99"}},
+			}},
+		},
+	}
+	suite.nwr = &v1alpha1.NetworkRequirement{Spec: v1alpha1.NetworkRequirementSpec{
+		NetworkCommunication: []v1alpha1.NetworkCommunication{
+			{Name: "web", InterSCNID: []v1alpha1.InterSCNID{
+				{Sla: v1alpha1.AppSlaAttr{Delay: 50, Bandwidth: 200}},
+				{Sla: v1alpha1.AppSlaAttr{Delay: 10}},
+			}},
+			{Name: "db", InterSCNID: []v1alpha1.InterSCNID{{Sla: v1alpha1.AppSlaAttr{Delay: 50}}}},
+		},
+	}}
+}
+
+func (suite *BoundarySuite) TestReplicaRequests() {
+	requests := replicaRequests(suite.desc, &suite.desc.Spec.Components[0])
+	suite.True(resource.MustParse("2").Equal(requests[corev1.ResourceCPU]), "raised to the boundary")
+	suite.True(resource.MustParse("1Gi").Equal(requests[corev1.ResourceMemory]), "already above the boundary")
+	suite.True(resource.MustParse("1").Equal(requests["nvidia.com/gpu"]), "extended resources are requested")
+	suite.NotContains(requests, corev1.ResourceName(v1alpha1.BoundaryTypeDelay), "only resources are requested")
+}
+
+func (suite *BoundarySuite) TestWithInterBoundaries() {
+	nwr := withInterBoundaries(suite.desc, suite.nwr)
+	web := nwr.Spec.NetworkCommunication[0]
+	suite.Equal(v1alpha1.AppSlaAttr{Delay: 20, Bandwidth: 200}, web.InterSCNID[0].Sla)
+	suite.Equal(v1alpha1.AppSlaAttr{Delay: 10, Bandwidth: 100}, web.InterSCNID[1].Sla)
+	suite.Equal(v1alpha1.AppSlaAttr{Delay: 50}, nwr.Spec.NetworkCommunication[1].InterSCNID[0].Sla, "db is not the subject")
+	suite.Equal(int32(50), suite.nwr.Spec.NetworkCommunication[0].InterSCNID[0].Sla.Delay, "the network requirement is copied")
+
+	suite.Nil(withInterBoundaries(suite.desc, nil))
+}
+
+func (suite *BoundarySuite) TestBoundaryStatuses() {
+	states := func(nwr *v1alpha1.NetworkRequirement) map[string]v1alpha1.BoundaryState {
+		result := make(map[string]v1alpha1.BoundaryState)
+		for _, status := range boundaryStatuses(suite.desc, nwr) {
+			result[status.Name] = status.State
+		}
+		return result
+	}
+	suite.Equal(map[string]v1alpha1.BoundaryState{
+		"cpu":       v1alpha1.BoundaryMet,
+		"memory":    v1alpha1.BoundaryMet,
+		"ghost":     v1alpha1.BoundaryUnknown,
+		"gpu":       v1alpha1.BoundaryMet,
+		"latency":   v1alpha1.BoundaryUnknown,
+		"delay":     v1alpha1.BoundaryMet,
+		"bandwidth": v1alpha1.BoundaryMet,
+		"cost":      v1alpha1.BoundaryUnknown,
+		"uptime":    v1alpha1.BoundaryUnknown,
+	}, states(suite.nwr))
+
+	withoutNetwork := states(nil)
+	suite.Equal(v1alpha1.BoundaryUnknown, withoutNetwork["delay"], "no network filter runs without a network requirement")
+	suite.Equal(v1alpha1.BoundaryMet, withoutNetwork["cpu"])
+}
+
+func (suite *BoundarySuite) TestDecodeBoundary() {
+	// the shape of a boundary in a description manifest, following the crd schema.
+	fixture := `{"name": "cpu", "subject": "web", "type": "cpu", "value": "500m"}`
+	var boundary v1alpha1.Boundary
+	suite.NoError(json.Unmarshal([]byte(fixture), &boundary))
+	value, err := boundaryValue(boundary)
+	suite.NoError(err)
+	suite.Equal(int64(500), value.MilliValue())
+}
+
+func TestBoundary(t *testing.T) {
+	suite.Run(t, new(BoundarySuite))
+}
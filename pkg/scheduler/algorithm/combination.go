@@ -0,0 +1,175 @@
+package algorithm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	appv1alpha1 "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	"github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+	"github.com/lmxia/gaia/pkg/common"
+	framework "github.com/lmxia/gaia/pkg/scheduler/framework/interfaces"
+)
+
+const (
+	// defaultBeamWidth is how many partial combinations are kept after each component is added.
+	defaultBeamWidth = 16
+	// maxResourceBindingCandidates is how many resource bindings are spawned for one description.
+	maxResourceBindingCandidates = 5
+)
+
+// combination is one plan row picked for each of the first components of a description.
+type combination [][]float64
+
+// matrix turns the combination into a components x clusters matrix.
+func (c combination) matrix() mat.Matrix {
+	cols := len(c[0])
+	data := make([]float64, 0, len(c)*cols)
+	for _, row := range c {
+		data = append(data, row...)
+	}
+	return mat.NewDense(len(c), cols, data)
+}
+
+// key identifies the combination, equal plans have the same key.
+func (c combination) key() string {
+	var b strings.Builder
+	for _, row := range c {
+		for _, v := range row {
+			fmt.Fprintf(&b, "%v,", v)
+		}
+		b.WriteByte(';')
+	}
+	return b.String()
+}
+
+// combineComponentPlans picks one plan row for every component on one spread level with a beam search.
+// Components are added one by one, and whenever there are more than beamWidth partial combinations,
+// they are scored with the score plugins and only the best beamWidth ones are kept.
+// So memory and scoring work grow linearly with the number of components instead of exponentially.
+// Combinations that break the inter-component affinity of desc are dropped before they are scored.
+func combineComponentPlans(ctx context.Context, fwk framework.Framework, in []mat.Matrix,
+	allClusters []*v1alpha1.ManagedCluster, desc *appv1alpha1.Description, beamWidth int) ([]combination, error) {
+	if len(in) == 0 {
+		return nil, errors.New("no component plans to combine")
+	}
+	affinity := newComponentAffinity(desc, allClusters)
+	beam := []combination{{}}
+	for i, componentPlans := range in {
+		if componentPlans == nil {
+			return nil, fmt.Errorf("component %d has no plan on this spread level", i)
+		}
+		rows, _ := componentPlans.Dims()
+		next := make([]combination, 0, len(beam)*rows)
+		for _, partial := range beam {
+			for r := 0; r < rows; r++ {
+				grown := make(combination, len(partial), len(partial)+1)
+				copy(grown, partial)
+				grown = append(grown, mat.Row(nil, r, componentPlans))
+				if affinity != nil && !affinity.fits(grown) {
+					continue
+				}
+				next = append(next, grown)
+			}
+		}
+		if len(next) == 0 {
+			return nil, fmt.Errorf("component %q: %w", desc.Spec.Components[i].Name, ErrComponentAffinity)
+		}
+		beam = pruneCombinations(ctx, fwk, next, allClusters, desc, beamWidth)
+	}
+	return beam, nil
+}
+
+// pruneCombinations keeps the best beamWidth combinations. Order is kept when there is no score plugin.
+func pruneCombinations(ctx context.Context, fwk framework.Framework, combinations []combination,
+	allClusters []*v1alpha1.ManagedCluster, desc *appv1alpha1.Description, beamWidth int) []combination {
+	if len(combinations) <= beamWidth {
+		return combinations
+	}
+	if fwk == nil || !fwk.HasScorePlugins() {
+		return combinations[:beamWidth]
+	}
+
+	rbs := make([]*appv1alpha1.ResourceBinding, len(combinations))
+	for i, item := range combinations {
+		rbs[i] = &appv1alpha1.ResourceBinding{
+			Spec: appv1alpha1.ResourceBindingSpec{
+				AppID:  desc.Name,
+				RbApps: spawnResourceBindingApps(item.matrix(), allClusters, desc),
+			},
+		}
+	}
+	priorityList, _, err := prioritizeResourcebindings(ctx, fwk, desc, allClusters, rbs)
+	if err != nil {
+		klog.Warningf("score plugins failed on partial resource bindings, keep the first %d: %v", beamWidth, err)
+		return combinations[:beamWidth]
+	}
+	// keep the same order selectResourceBindings picks the final resource bindings in.
+	sort.Stable(priorityList)
+	result := make([]combination, 0, beamWidth)
+	for _, item := range priorityList[:beamWidth] {
+		result = append(result, combinations[item.Index])
+	}
+	return result
+}
+
+// spawnResourceBindings spawns brand new resourcebindings on multi spread level.
+// candidates are taken from every spread level in turn, so each level is represented in the result.
+func spawnResourceBindings(ctx context.Context, fwk framework.Framework, ins [][]mat.Matrix,
+	allClusters []*v1alpha1.ManagedCluster, desc *appv1alpha1.Description) []*appv1alpha1.ResourceBinding {
+	result := make([]*appv1alpha1.ResourceBinding, 0)
+	seen := make(map[string]bool)
+	rbLabels := fillRBLabels(desc)
+	rbLabels[common.GaiaDescriptionLabel] = desc.Name
+
+	levelCombinations := make([][]combination, 0, len(ins))
+	maxLen := 0
+	for _, items := range ins {
+		combinations, err := combineComponentPlans(ctx, fwk, items, allClusters, desc, defaultBeamWidth)
+		if err != nil {
+			// leave this spread level alone.
+			klog.V(5).Infof("skip spread level of description %s: %v", klog.KObj(desc), err)
+			continue
+		}
+		levelCombinations = append(levelCombinations, combinations)
+		if len(combinations) > maxLen {
+			maxLen = len(combinations)
+		}
+	}
+
+	for i := 0; i < maxLen; i++ {
+		for _, combinations := range levelCombinations {
+			if i >= len(combinations) {
+				continue
+			}
+			item := combinations[i]
+			if seen[item.key()] {
+				continue
+			}
+			seen[item.key()] = true
+			rb := &appv1alpha1.ResourceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   fmt.Sprintf("%s-rs-%d", desc.Name, len(result)),
+					Labels: rbLabels,
+				},
+				Spec: appv1alpha1.ResourceBindingSpec{
+					AppID:  desc.Name,
+					RbApps: spawnResourceBindingApps(item.matrix(), allClusters, desc),
+				},
+			}
+			rb.Kind = "ResourceBinding"
+			rb.APIVersion = "apps.gaia.io/v1alpha1"
+			result = append(result, rb)
+			if len(result) == maxResourceBindingCandidates {
+				return result
+			}
+		}
+	}
+	return result
+}
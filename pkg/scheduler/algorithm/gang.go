@@ -0,0 +1,98 @@
+package algorithm
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	clusterapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+	framework2 "github.com/lmxia/gaia/pkg/scheduler/framework"
+	framework "github.com/lmxia/gaia/pkg/scheduler/framework/interfaces"
+)
+
+// ErrGangUnschedulable is used to describe the error that no resource binding places all components of a gang
+// description at the same time.
+var ErrGangUnschedulable = fmt.Errorf("%w: components of the gang don't fit on the clusters at the same time", ErrInfeasiblePlan)
+
+// gangReplicas returns the replicas planned for a deployment component of a gang description. When the clusters
+// can't hold all replicas, as many of them as the clusters hold are planned, as long as there are at least
+// the minimum replicas of the component.
+func gangReplicas(desc *v1alpha1.Description, comp *v1alpha1.Component, capability []*framework2.ClusterInfo, replicas int64) int64 {
+	minReplicas, ok := desc.Spec.GangScheduling.MinReplicas[comp.Name]
+	if !ok || int64(minReplicas) >= replicas {
+		return replicas
+	}
+	total := int64(0)
+	for _, item := range capability {
+		if item != nil && item.Total > 0 {
+			total += item.Total
+		}
+	}
+	if total >= replicas || total < int64(minReplicas) {
+		return replicas
+	}
+	return total
+}
+
+// withoutGangFailedClusters returns clusters without the ones that failed to place their share of this generation
+// of the gang description desc, which are recorded as unresolvable in diagnosis.
+func withoutGangFailedClusters(desc *v1alpha1.Description, clusters []*clusterapi.ManagedCluster,
+	diagnosis framework.Diagnosis) []*clusterapi.ManagedCluster {
+	if desc.Spec.GangScheduling == nil || len(desc.Status.GangFailedClusters) == 0 ||
+		desc.Status.ObservedGeneration != desc.Generation {
+		return clusters
+	}
+	failed := sets.NewString(desc.Status.GangFailedClusters...)
+	result := make([]*clusterapi.ManagedCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		if !failed.Has(cluster.Name) {
+			result = append(result, cluster)
+			continue
+		}
+		diagnosis.ClusterToStatusMap[klog.KObj(cluster).String()] = framework.NewStatus(
+			framework.UnschedulableAndUnresolvable, "cluster failed to place its share of the gang")
+	}
+	return result
+}
+
+// filterGangResourceBindings keeps the rbs whose rb apps fit on clusters with all components at the same time.
+// Every component is planned against the free resources of the clusters on its own, so two components that fit
+// one by one may together ask a cluster for more than it has.
+func filterGangResourceBindings(desc *v1alpha1.Description, rbs []*v1alpha1.ResourceBinding,
+	clusters []*clusterapi.ManagedCluster) []*v1alpha1.ResourceBinding {
+	requestsOfComponents := ComponentRequests(desc)
+	available := make(map[string]corev1.ResourceList, len(clusters))
+	for _, cluster := range clusters {
+		available[cluster.Name] = cluster.Status.Available
+	}
+
+	result := make([]*v1alpha1.ResourceBinding, 0, len(rbs))
+	for _, rb := range rbs {
+		if cluster, ok := fitsAtTheSameTime(rbAppsRequests(requestsOfComponents, rb.Spec.RbApps), available); !ok {
+			klog.V(4).InfoS("resource binding doesn't place the gang at the same time", "description", klog.KObj(desc), "cluster", cluster)
+			continue
+		}
+		result = append(result, rb)
+	}
+	return result
+}
+
+// fitsAtTheSameTime returns true if the available resources of every cluster cover its requests,
+// or the first cluster that can't hold its requests.
+func fitsAtTheSameTime(requests, available map[string]corev1.ResourceList) (string, bool) {
+	for clusterName, clusterRequests := range requests {
+		for name, quantity := range clusterRequests {
+			if quantity.IsZero() {
+				continue
+			}
+			free := available[clusterName][name]
+			if quantity.Cmp(free) > 0 {
+				return clusterName, false
+			}
+		}
+	}
+	return "", true
+}
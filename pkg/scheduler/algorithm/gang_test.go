@@ -0,0 +1,108 @@
+package algorithm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	platformv1alpha1 "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+	"github.com/lmxia/gaia/pkg/scheduler/framework"
+	frameworkinterfaces "github.com/lmxia/gaia/pkg/scheduler/framework/interfaces"
+)
+
+type GangSuite struct {
+	desc     *v1alpha1.Description
+	clusters []*platformv1alpha1.ManagedCluster
+	suite.Suite
+}
+
+func (suite *GangSuite) SetupTest() {
+	template := func(cpu string) corev1.PodTemplateSpec {
+		return corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			}}},
+		}}}
+	}
+	suite.desc = &v1alpha1.Description{
+		ObjectMeta: metav1.ObjectMeta{Name: "desc"},
+		Spec: v1alpha1.DescriptionSpec{
+			GangScheduling: &v1alpha1.GangScheduling{MinReplicas: map[string]int32{"web": 2}},
+			Components: []v1alpha1.Component{
+				{Name: "web", Module: template("2")},
+				{Name: "db", Module: template("3")},
+			},
+		},
+	}
+	suite.clusters = []*platformv1alpha1.ManagedCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "c1"}, Status: platformv1alpha1.ManagedClusterStatus{
+			Available: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("16Gi"),
+			},
+		}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c2"}, Status: platformv1alpha1.ManagedClusterStatus{
+			Available: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("8"),
+				corev1.ResourceMemory: resource.MustParse("16Gi"),
+			},
+		}},
+	}
+}
+
+func (suite *GangSuite) TestFilterGangResourceBindings() {
+	together := &v1alpha1.ResourceBinding{Spec: v1alpha1.ResourceBindingSpec{RbApps: []*v1alpha1.ResourceBindingApps{
+		{ClusterName: "c1", Replicas: map[string]int32{"web": 2, "db": 0}},
+		{ClusterName: "c2", Replicas: map[string]int32{"web": 1, "db": 2}},
+	}}}
+	apart := &v1alpha1.ResourceBinding{Spec: v1alpha1.ResourceBindingSpec{RbApps: []*v1alpha1.ResourceBindingApps{
+		{ClusterName: "c1", Replicas: map[string]int32{"web": 1, "db": 1}},
+		{ClusterName: "c2", Replicas: map[string]int32{"web": 2, "db": 1}},
+	}}}
+
+	result := filterGangResourceBindings(suite.desc, []*v1alpha1.ResourceBinding{apart, together}, suite.clusters)
+	suite.Equal([]*v1alpha1.ResourceBinding{together}, result, "web and db fit c1 one by one, but not together")
+}
+
+func (suite *GangSuite) TestGangReplicas() {
+	web := &suite.desc.Spec.Components[0]
+	capability := []*framework.ClusterInfo{{Total: 2}, {Total: 1}}
+	suite.Equal(int64(3), gangReplicas(suite.desc, web, capability, 5), "as many replicas as the clusters hold")
+	suite.Equal(int64(3), gangReplicas(suite.desc, web, capability, 3))
+
+	capability[1].Total = 0
+	suite.Equal(int64(2), gangReplicas(suite.desc, web, capability, 5), "the minimum replicas")
+	capability[0].Total = 1
+	suite.Equal(int64(5), gangReplicas(suite.desc, web, capability, 5), "below the minimum, all replicas are planned and fail")
+
+	db := &suite.desc.Spec.Components[1]
+	suite.Equal(int64(5), gangReplicas(suite.desc, db, capability, 5), "a component without minimum needs all replicas")
+}
+
+func (suite *GangSuite) TestErrGangUnschedulable() {
+	suite.True(errors.Is(ErrGangUnschedulable, ErrInfeasiblePlan), "preemption handles a gang as an infeasible plan")
+}
+
+func (suite *GangSuite) TestWithoutGangFailedClusters() {
+	diagnosis := frameworkinterfaces.Diagnosis{ClusterToStatusMap: make(frameworkinterfaces.ClusterToStatusMap)}
+	suite.desc.Generation = 2
+	suite.desc.Status.ObservedGeneration = 2
+	suite.desc.Status.GangFailedClusters = []string{"c1"}
+	clusters := withoutGangFailedClusters(suite.desc, suite.clusters, diagnosis)
+	suite.Len(clusters, 1)
+	suite.Equal("c2", clusters[0].Name)
+	suite.Equal(frameworkinterfaces.UnschedulableAndUnresolvable, diagnosis.ClusterToStatusMap["c1"].Code())
+
+	suite.desc.Generation = 3
+	suite.Len(withoutGangFailedClusters(suite.desc, suite.clusters, diagnosis), 2, "a new spec may fit c1")
+}
+
+func TestGang(t *testing.T) {
+	suite.Run(t, new(GangSuite))
+}
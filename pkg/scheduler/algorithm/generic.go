@@ -34,10 +34,14 @@ import (
 // ErrNoClustersAvailable is used to describe the error that no clusters available to schedule subscriptions.
 var ErrNoClustersAvailable = fmt.Errorf("no clusters available to schedule subscriptions")
 
+// ErrNoNetworkPath is used to describe the error that the network filter drops all candidate resource bindings.
+var ErrNoNetworkPath = errors.New("network filter can't find path for current rbs")
+
 type genericScheduler struct {
 	cache                       schedulercache.Cache
 	percentageOfClustersToScore int32
 	nextStartClusterIndex       int
+	networkFilterLock           sync.Mutex
 }
 
 func (g *genericScheduler) SetSelfClusterName(name string) {
@@ -46,8 +50,32 @@ func (g *genericScheduler) SetSelfClusterName(name string) {
 
 // Schedule
 func (g *genericScheduler) Schedule(ctx context.Context, fwk framework.Framework, rbs []*v1alpha1.ResourceBinding, desc *v1alpha1.Description) (result ScheduleResult, err error) {
+	var nwr *v1alpha1.NetworkRequirement
+	if desc.Namespace == common.GaiaReservedNamespace {
+		// add networkFilter only if we can get nwr
+		if item, nwrErr := g.cache.GetNetworkRequirement(desc); nwrErr == nil {
+			nwr = item
+		}
+	}
+	return g.schedule(ctx, fwk, rbs, desc, nwr)
+}
+
+// Simulate schedules desc as a description from the reserved namespace against the current cache,
+// with nwr as its network requirement. Nothing is written to the clusters.
+func (g *genericScheduler) Simulate(ctx context.Context, fwk framework.Framework, desc *v1alpha1.Description,
+	nwr *v1alpha1.NetworkRequirement) (ScheduleResult, error) {
+	desc = desc.DeepCopy()
+	desc.Namespace = common.GaiaReservedNamespace
+	return g.schedule(ctx, fwk, nil, desc, nwr)
+}
+
+func (g *genericScheduler) schedule(ctx context.Context, fwk framework.Framework, rbs []*v1alpha1.ResourceBinding,
+	desc *v1alpha1.Description, nwr *v1alpha1.NetworkRequirement) (result ScheduleResult, err error) {
 	trace := utiltrace.New("Scheduling", utiltrace.Field{Key: "namespace", Value: desc.Namespace}, utiltrace.Field{Key: "name", Value: desc.Name})
 	defer trace.LogIfLong(100 * time.Millisecond)
+	ctx = framework.WithDescription(ctx, desc)
+	nwr = withInterBoundaries(desc, nwr)
+	result.Boundaries = boundaryStatuses(desc, nwr)
 
 	// 1. get backup clusters.
 	if g.cache.NumClusters() == 0 {
@@ -55,52 +83,71 @@ func (g *genericScheduler) Schedule(ctx context.Context, fwk framework.Framework
 	}
 	allClusters, _ := g.cache.ListClusters(&metav1.LabelSelector{})
 	numComponent := len(desc.Spec.Components)
-	// 2, means allspread, one spread, 2 spread.
-	allResultGlobal := make([][]mat.Matrix, 3)
-	for i := 0; i < 3; i++ {
+	// first dim means spread level, second means component.
+	allResultGlobal := make([][]mat.Matrix, len(defaultSpreadLevels))
+	for i := range defaultSpreadLevels {
 		allResultGlobal[i] = make([]mat.Matrix, numComponent)
 	}
 	// has parent
-	// first dim means resouce binding, second means component, third means spread level.
+	// first dim means resouce binding, second means spread level, third means component.
 	allResultWithRB := make([][][]mat.Matrix, len(rbs))
 	if desc.Namespace != common.GaiaReservedNamespace {
 		// desc has resource binding
 		for i := 0; i < len(rbs); i++ {
-			allResultWithRB[i] = make([][]mat.Matrix, 3)
-			for j := 0; j < 3; j++ {
+			allResultWithRB[i] = make([][]mat.Matrix, len(defaultSpreadLevels))
+			for j := range defaultSpreadLevels {
 				allResultWithRB[i][j] = make([]mat.Matrix, numComponent)
 			}
 		}
 	}
 
+	result.Diagnoses = make(map[string]framework.Diagnosis, numComponent)
+	var fitErr *framework.FitError
 	for i, comm := range desc.Spec.Components {
 		localComponent := &comm
 		// NO.1 pre filter
-		feasibleClusters, diagnosis, _ := g.findClustersThatFitComponent(ctx, fwk, localComponent)
+		feasibleClusters, diagnosis, _ := g.findClustersThatFitComponent(ctx, fwk, desc, localComponent)
 		// if err != nil {
 		//	return result, err
 		// }
+		result.Diagnoses[comm.Name] = diagnosis
 		// case global
 		// if desc.Namespace == common.GaiaReservedNamespace {
-		if len(feasibleClusters) == 0 {
-			return result, &framework.FitError{
+		if len(feasibleClusters) == 0 && fitErr == nil {
+			fitErr = &framework.FitError{
 				Description:    desc,
 				NumAllClusters: g.cache.NumClusters(),
 				Diagnosis:      diagnosis,
 			}
 		}
+		if fitErr != nil {
+			// keep diagnosing the rest components, there is no need to plan them.
+			continue
+		}
 		// }
 
-		// spread level info: full level, 2 level, 1 level
-		// spreadLevels := []int64{int64(len(feasibleClusters)), 2, 1}
-		// todo only 1 as default spread level
-		spreadLevels := []int64{1}
 		allPlan := nomalizeClusters(feasibleClusters, allClusters)
+		capableClusters := countCapableClusters(feasibleClusters)
 		// desc come from reserved namespace, that means no resource bindings
 		if desc.Namespace == common.GaiaReservedNamespace {
-			for j, _ := range spreadLevels {
+			replicas := int64(0)
+			if comm.Workload.TraitDeployment != nil {
+				replicas = int64(comm.Workload.TraitDeployment.Replicas)
+			}
+			if desc.Spec.GangScheduling != nil && comm.Workload.Workloadtype == v1alpha1.WorkloadTypeDeployment {
+				replicas = gangReplicas(desc, localComponent, allPlan, replicas)
+			}
+			spreadLevels, err := getSpreadLevels(capableClusters, int64(comm.Dispersion), replicas)
+			if err != nil && comm.Workload.Workloadtype == v1alpha1.WorkloadTypeDeployment {
+				return result, fmt.Errorf("component %q: %w", comm.Name, err)
+			}
+			for j := range defaultSpreadLevels {
 				if comm.Workload.Workloadtype == v1alpha1.WorkloadTypeDeployment {
-					componentMat := makeDeployPlans(allPlan, int64(comm.Workload.TraitDeployment.Replicas), int64(comm.Dispersion))
+					componentMat, err := makeDeployPlans(allPlan, replicas, spreadLevels[j])
+					if err != nil {
+						klog.V(4).InfoS("no plan for component on spread level", "component", comm.Name, "spreadLevel", spreadLevels[j], "err", err)
+						continue
+					}
 					allResultGlobal[j][i] = componentMat
 				} else if comm.Workload.Workloadtype == v1alpha1.WorkloadTypeServerless {
 					componentMat := makeServelessPlan(allPlan, 1)
@@ -117,9 +164,17 @@ func (g *genericScheduler) Schedule(ctx context.Context, fwk framework.Framework
 		} else {
 			for j, rb := range rbs {
 				replicas := getComponentClusterTotal(rb.Spec.RbApps, g.cache.GetSelfClusterName(), comm.Name)
-				for k, _ := range spreadLevels {
+				spreadLevels, err := getSpreadLevels(capableClusters, int64(comm.Dispersion), replicas)
+				if err != nil && comm.Workload.Workloadtype == v1alpha1.WorkloadTypeDeployment {
+					return result, fmt.Errorf("component %q in resource binding %q: %w", comm.Name, rb.Name, err)
+				}
+				for k := range defaultSpreadLevels {
 					if comm.Workload.Workloadtype == v1alpha1.WorkloadTypeDeployment {
-						componentMat := makeDeployPlans(allPlan, replicas, spreadLevels[k])
+						componentMat, err := makeDeployPlans(allPlan, replicas, spreadLevels[k])
+						if err != nil {
+							klog.V(4).InfoS("no plan for component on spread level", "component", comm.Name, "spreadLevel", spreadLevels[k], "err", err)
+							continue
+						}
 						allResultWithRB[j][k][i] = componentMat
 					} else if comm.Workload.Workloadtype == v1alpha1.WorkloadTypeServerless {
 						componentMat := makeServelessPlan(allPlan, replicas)
@@ -137,29 +192,49 @@ func (g *genericScheduler) Schedule(ctx context.Context, fwk framework.Framework
 		}
 	}
 
+	if fitErr != nil {
+		return result, fitErr
+	}
+
 	rbsResultFinal := make([]*v1alpha1.ResourceBinding, 0)
 
 	// NO.2 first we should spawn rbs.
 	if desc.Namespace == common.GaiaReservedNamespace {
 		// all 5
-		rbsResultFinal = spawnResourceBindings(allResultGlobal, allClusters, desc)
+		rbsResultFinal = spawnResourceBindings(ctx, fwk, allResultGlobal, allClusters, desc)
+		if len(rbsResultFinal) == 0 {
+			return result, fmt.Errorf("%w: can't find any spread level that fits all components", ErrInfeasiblePlan)
+		}
+		if desc.Spec.GangScheduling != nil {
+			rbsResultFinal = filterGangResourceBindings(desc, rbsResultFinal, allClusters)
+			if len(rbsResultFinal) == 0 {
+				return result, ErrGangUnschedulable
+			}
+		}
 		// 1. add networkFilter only if we can get nwr
-		if nwr, err := g.cache.GetNetworkRequirement(desc); err == nil {
+		if nwr != nil {
 			networkInfoMap := g.getTopologyInfoMap()
 			klog.Infof("Log: networkInfoMap is %v", networkInfoMap)
 			klog.Infof("resource binding before net filter %v", rbsResultFinal)
-			rbsResultFinal = npcore.NetworkFilter(rbsResultFinal, nwr, networkInfoMap)
+			candidates := rbsResultFinal
+			// the network filter keeps its graphs in package level state.
+			g.networkFilterLock.Lock()
+			rbsResultFinal = npcore.NetworkFilter(candidates, nwr, networkInfoMap)
+			g.networkFilterLock.Unlock()
 			klog.Infof("resource binding after net filter %v", rbsResultFinal)
+			result.NetworkFilteredResourceBindings = droppedResourceBindings(candidates, rbsResultFinal)
 			if len(rbsResultFinal) == 0 {
-				return result, errors.New("network filter can't find path for current rbs")
+				return result, ErrNoNetworkPath
 			}
 		}
+		result.Candidates = rbsResultFinal
+		// score plugins.
+		priorityList, pluginScores, scoreError := prioritizeResourcebindings(ctx, fwk, desc, allClusters, rbsResultFinal)
+		if scoreError != nil {
+			klog.Warningf("score pulgin run error %v", scoreError)
+		}
+		result.PluginScores = pluginScores
 		if len(rbsResultFinal) > common.DefaultResouceBindingNumber {
-			// score plugins.
-			priorityList, scoreError := prioritizeResourcebindings(ctx, fwk, desc, allClusters, rbsResultFinal)
-			if scoreError != nil {
-				klog.Warningf("score pulgin run error %v", scoreError)
-			}
 			// select 2
 			rbsResultFinal, err = g.selectResourceBindings(priorityList, rbsResultFinal)
 		}
@@ -167,7 +242,11 @@ func (g *genericScheduler) Schedule(ctx context.Context, fwk framework.Framework
 		rbIndex := 0
 		for i, rbOld := range rbs {
 			rbsResult := make([]*v1alpha1.ResourceBinding, 0)
-			rbForrb := spawnResourceBindings(allResultWithRB[i], allClusters, desc)
+			rbForrb := spawnResourceBindings(ctx, fwk, allResultWithRB[i], allClusters, desc)
+			if desc.Spec.GangScheduling != nil {
+				// the share of the parent is placed as a whole or not at all.
+				rbForrb = filterGangResourceBindings(desc, rbForrb, allClusters)
+			}
 			for j, _ := range rbForrb {
 				subRBApps := make([]*v1alpha1.ResourceBindingApps, 0)
 				for _, rbapp := range rbOld.Spec.RbApps {
@@ -199,7 +278,7 @@ func (g *genericScheduler) Schedule(ctx context.Context, fwk framework.Framework
 			}
 			if len(rbsResult) > common.DefaultResouceBindingNumber {
 				// score plugins.
-				priorityList, scoreError := prioritizeResourcebindings(ctx, fwk, desc, allClusters, rbsResult)
+				priorityList, _, scoreError := prioritizeResourcebindings(ctx, fwk, desc, allClusters, rbsResult)
 				if scoreError != nil {
 					klog.Warningf("score pulgin run error %v", scoreError)
 				}
@@ -208,11 +287,31 @@ func (g *genericScheduler) Schedule(ctx context.Context, fwk framework.Framework
 			}
 			rbsResultFinal = append(rbsResultFinal, rbsResult...)
 		}
+		if len(rbsResultFinal) == 0 && desc.Spec.GangScheduling != nil {
+			return result, ErrGangUnschedulable
+		}
+		if len(rbsResultFinal) == 0 {
+			return result, fmt.Errorf("%w: can't find any spread level that fits all components", ErrInfeasiblePlan)
+		}
 	}
 
-	return ScheduleResult{
-		ResourceBindings: rbsResultFinal,
-	}, err
+	result.ResourceBindings = rbsResultFinal
+	return result, err
+}
+
+// droppedResourceBindings returns the candidates that are not selected, keyed by their index among candidates.
+func droppedResourceBindings(candidates, selected []*v1alpha1.ResourceBinding) map[int]*v1alpha1.ResourceBinding {
+	kept := make(map[*v1alpha1.ResourceBinding]bool, len(selected))
+	for _, rb := range selected {
+		kept[rb] = true
+	}
+	dropped := make(map[int]*v1alpha1.ResourceBinding)
+	for i, rb := range candidates {
+		if !kept[rb] {
+			dropped[i] = rb
+		}
+	}
+	return dropped
 }
 
 func (g *genericScheduler) getTopologyInfoMap() map[string]clusterapi.Topo {
@@ -224,8 +323,9 @@ func (g *genericScheduler) getTopologyInfoMap() map[string]clusterapi.Topo {
 	return networkInfoMap
 }
 
+// prioritizeResourcebindings sums up the scores given to rbs by the score plugins, the scores of each plugin are returned as well.
 func prioritizeResourcebindings(ctx context.Context, fwk framework.Framework, _ *v1alpha1.Description,
-	clusters []*clusterapi.ManagedCluster, rbs []*v1alpha1.ResourceBinding) (framework.ResourceBindingScoreList, error) {
+	clusters []*clusterapi.ManagedCluster, rbs []*v1alpha1.ResourceBinding) (framework.ResourceBindingScoreList, framework.PluginToRBScores, error) {
 	if !fwk.HasScorePlugins() {
 		result := make(framework.ResourceBindingScoreList, 0, len(rbs))
 		for i := range rbs {
@@ -234,12 +334,12 @@ func prioritizeResourcebindings(ctx context.Context, fwk framework.Framework, _
 				Score: 1,
 			})
 		}
-		return result, nil
+		return result, nil, nil
 	}
 
 	scoresMap, scoreStatus := fwk.RunScorePlugins(ctx, rbs, clusters)
 	if !scoreStatus.IsSuccess() {
-		return nil, scoreStatus.AsError()
+		return nil, nil, scoreStatus.AsError()
 	}
 
 	// Summarize all scores.
@@ -251,7 +351,7 @@ func prioritizeResourcebindings(ctx context.Context, fwk framework.Framework, _
 			result[i].Score += scoresMap[j][i].Score
 		}
 	}
-	return result, nil
+	return result, scoresMap, nil
 }
 
 func nomalizeClusters(feasibleClusters []*framework2.ClusterInfo, allClusters []*clusterapi.ManagedCluster) []*framework2.ClusterInfo {
@@ -292,7 +392,8 @@ func (g *genericScheduler) selectResourceBindings(rbScoreList framework.Resource
 }
 
 // Filters the clusters to find the ones that fit the subscription based on the framework filter plugins.
-func (g *genericScheduler) findClustersThatFitComponent(ctx context.Context, fwk framework.Framework, comm *v1alpha1.Component) ([]*framework2.ClusterInfo, framework.Diagnosis, error) {
+func (g *genericScheduler) findClustersThatFitComponent(ctx context.Context, fwk framework.Framework, desc *v1alpha1.Description,
+	comm *v1alpha1.Component) ([]*framework2.ClusterInfo, framework.Diagnosis, error) {
 	diagnosis := framework.Diagnosis{
 		ClusterToStatusMap:   make(framework.ClusterToStatusMap),
 		UnschedulablePlugins: sets.NewString(),
@@ -307,7 +408,7 @@ func (g *genericScheduler) findClustersThatFitComponent(ctx context.Context, fwk
 	}
 	allClusters = append(allClusters, clusters...)
 
-	allClusters = normalizedClusters(allClusters)
+	allClusters = withoutGangFailedClusters(desc, normalizedClusters(allClusters), diagnosis)
 	// Return immediately if no clusters match the cluster affinity.
 	if len(allClusters) == 0 {
 		return nil, diagnosis, nil
@@ -335,8 +436,8 @@ func (g *genericScheduler) findClustersThatFitComponent(ctx context.Context, fwk
 	}
 
 	// aggregate all container resource
-	non0CPU, non0MEM, _ := calculateResource(comm.Module)
-	result, _ := scheduleWorkload(non0CPU, non0MEM, feasibleClusters)
+	requests := replicaRequests(desc, comm)
+	result, _ := scheduleWorkload(requests, feasibleClusters)
 
 	return result, diagnosis, nil
 }
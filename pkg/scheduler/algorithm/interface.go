@@ -11,6 +11,8 @@ import (
 // managed clusters.
 type ScheduleAlgorithm interface {
 	Schedule(context.Context, framework.Framework, []*v1alpha1.ResourceBinding, *v1alpha1.Description) (scheduleResult ScheduleResult, err error)
+	// Simulate runs a dry-run scheduling of a description with the given network requirement.
+	Simulate(context.Context, framework.Framework, *v1alpha1.Description, *v1alpha1.NetworkRequirement) (scheduleResult ScheduleResult, err error)
 	SetSelfClusterName(name string)
 }
 
@@ -18,4 +20,17 @@ type ScheduleAlgorithm interface {
 type ScheduleResult struct {
 	// the final rbs.
 	ResourceBindings []*v1alpha1.ResourceBinding
+	// Candidates are the rbs left by the network filter, which the final rbs are selected from.
+	// Only descriptions from the reserved namespace have them.
+	Candidates []*v1alpha1.ResourceBinding
+	// PluginScores are the scores each score plugin gives to Candidates.
+	PluginScores framework.PluginToRBScores
+	// Diagnoses records the clusters rejected by filter plugins, keyed by component name.
+	// It is filled in even if scheduling fails.
+	Diagnoses map[string]framework.Diagnosis
+	// NetworkFilteredResourceBindings are the candidate rbs dropped by the network filter, keyed by
+	// their index among the spawned candidates.
+	NetworkFilteredResourceBindings map[int]*v1alpha1.ResourceBinding
+	// Boundaries are the states of the boundaries of the description if it is placed.
+	Boundaries []v1alpha1.BoundaryStatus
 }
@@ -0,0 +1,170 @@
+package algorithm
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/lmxia/gaia/pkg/scheduler/framework"
+)
+
+// ErrInfeasiblePlan is returned when no group of clusters on a spread level can hold all replicas of a component.
+var ErrInfeasiblePlan = errors.New("no placement plan can hold all replicas")
+
+const (
+	// maxPlansPerSpreadLevel is how many distinct plans are made for a component on one spread level.
+	maxPlansPerSpreadLevel = 2
+	// maxPlanCandidates bounds the cluster groups searched for one spread level, so planning always terminates.
+	maxPlanCandidates = 1024
+)
+
+// placePlans returns at most maxPlans distinct plans which spread total replicas over exactly spreadOver clusters,
+// or over total clusters if there are fewer replicas. Clusters are ordered by capacity (then by name), and groups
+// of clusters are searched in lexicographic order of that ordering, so the first plan always uses the biggest
+// clusters and results are repeatable. Each plan has one item per cluster in capability.
+func placePlans(capability []*framework.ClusterInfo, total int64, spreadOver, maxPlans int) ([][]float64, error) {
+	candidates := sortedCapableClusters(capability)
+	if spreadOver <= 0 || spreadOver > len(candidates) {
+		spreadOver = len(candidates)
+	}
+	if total > 0 && int64(spreadOver) > total {
+		// a cluster without replicas doesn't spread the component, the groups would give the same plans.
+		spreadOver = int(total)
+	}
+	if spreadOver == 0 || sumCapacity(capability, candidates[:spreadOver]) < total {
+		return nil, ErrInfeasiblePlan
+	}
+
+	result := make([][]float64, 0, maxPlans)
+	chosen := make([]int, spreadOver)
+	for i := range chosen {
+		chosen[i] = i
+	}
+	for searched := 0; searched < maxPlanCandidates && len(result) < maxPlans; searched++ {
+		group := make([]int, spreadOver)
+		for i, c := range chosen {
+			group[i] = candidates[c]
+		}
+		if sumCapacity(capability, group) >= total {
+			if plan := allocateReplicas(capability, group, total); !containsPlan(result, plan) {
+				result = append(result, plan)
+			}
+		}
+		if !nextCombination(chosen, len(candidates)) {
+			break
+		}
+	}
+	if len(result) == 0 {
+		return nil, ErrInfeasiblePlan
+	}
+	return result, nil
+}
+
+// containsPlan returns true if plans holds a plan equal to plan.
+func containsPlan(plans [][]float64, plan []float64) bool {
+	for _, item := range plans {
+		same := len(item) == len(plan)
+		for i := 0; same && i < len(item); i++ {
+			same = item[i] == plan[i]
+		}
+		if same {
+			return true
+		}
+	}
+	return false
+}
+
+// allocateReplicas spreads total replicas over the group of clusters in proportion to their capacity,
+// never exceeding the capacity of a cluster. When there are enough replicas, every cluster of the group gets one.
+func allocateReplicas(capability []*framework.ClusterInfo, group []int, total int64) []float64 {
+	assigned := make([]int64, len(group))
+	remaining := total
+	if total >= int64(len(group)) {
+		for i := range group {
+			assigned[i] = 1
+		}
+		remaining -= int64(len(group))
+	}
+
+	for remaining > 0 {
+		free := int64(0)
+		for i, c := range group {
+			free += capability[c].Total - assigned[i]
+		}
+		if free <= 0 {
+			break
+		}
+		progress := int64(0)
+		left := remaining
+		for i, c := range group {
+			share := left * (capability[c].Total - assigned[i]) / free
+			assigned[i] += share
+			progress += share
+		}
+		remaining -= progress
+		if progress == 0 {
+			// hand out what is left one by one, to the cluster with the most free capacity first.
+			for remaining > 0 {
+				best := -1
+				for i, c := range group {
+					if capability[c].Total-assigned[i] > 0 && (best == -1 ||
+						capability[c].Total-assigned[i] > capability[group[best]].Total-assigned[best]) {
+						best = i
+					}
+				}
+				if best == -1 {
+					break
+				}
+				assigned[best]++
+				remaining--
+			}
+		}
+	}
+
+	row := make([]float64, len(capability))
+	for i, c := range group {
+		row[c] = float64(assigned[i])
+	}
+	return row
+}
+
+// sortedCapableClusters returns indexes of clusters that have capacity, biggest first.
+func sortedCapableClusters(capability []*framework.ClusterInfo) []int {
+	result := make([]int, 0, len(capability))
+	for i, item := range capability {
+		if item != nil && item.Total > 0 {
+			result = append(result, i)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := capability[result[i]], capability[result[j]]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.Cluster.Name < b.Cluster.Name
+	})
+	return result
+}
+
+func sumCapacity(capability []*framework.ClusterInfo, group []int) int64 {
+	sum := int64(0)
+	for _, c := range group {
+		sum += capability[c].Total
+	}
+	return sum
+}
+
+// nextCombination advances chosen to the next k-combination of n items in lexicographic order.
+// It returns false when chosen is already the last one.
+func nextCombination(chosen []int, n int) bool {
+	k := len(chosen)
+	for i := k - 1; i >= 0; i-- {
+		if chosen[i] < n-k+i {
+			chosen[i]++
+			for j := i + 1; j < k; j++ {
+				chosen[j] = chosen[j-1] + 1
+			}
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package apis
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto copies the receiver into out.
+func (in *VirtualNodeArgs) DeepCopyInto(out *VirtualNodeArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+}
+
+// DeepCopy creates a new VirtualNodeArgs by copying the receiver.
+func (in *VirtualNodeArgs) DeepCopy() *VirtualNodeArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualNodeArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject copies the receiver, creating a new runtime.Object.
+func (in *VirtualNodeArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *CorePriorityArgs) DeepCopyInto(out *CorePriorityArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+}
+
+// DeepCopy creates a new CorePriorityArgs by copying the receiver.
+func (in *CorePriorityArgs) DeepCopy() *CorePriorityArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(CorePriorityArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject copies the receiver, creating a new runtime.Object.
+func (in *CorePriorityArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package scheme
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	schedulerapis "github.com/lmxia/gaia/pkg/scheduler/apis"
+	schedulerapisv1alpha1 "github.com/lmxia/gaia/pkg/scheduler/apis/v1alpha1"
+)
+
+var (
+	// Scheme is the runtime.Scheme to which all gaia-scheduler plugin args are registered.
+	Scheme = runtime.NewScheme()
+
+	// Codecs provides access to encoding and decoding for the scheme.
+	Codecs = serializer.NewCodecFactory(Scheme, serializer.EnableStrict)
+)
+
+func init() {
+	AddToScheme(Scheme)
+}
+
+// AddToScheme builds the gaia-scheduler scheme using all known versions of the plugin args.
+func AddToScheme(scheme *runtime.Scheme) {
+	utilruntime.Must(schedulerapis.AddToScheme(scheme))
+	utilruntime.Must(schedulerapisv1alpha1.AddToScheme(scheme))
+}
@@ -5,9 +5,53 @@ package apis
 import (
 	"math"
 
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
+// DefaultSchedulerName is the name of the profile used when a Description doesn't choose one.
+const DefaultSchedulerName = "default-scheduler"
+
+// SchedulerConfiguration configures a scheduler.
+type SchedulerConfiguration struct {
+	// Profiles are scheduling profiles that gaia-scheduler supports. Descriptions can
+	// choose to be scheduled under a particular profile by setting its scheduler name.
+	// Descriptions that don't specify any scheduler name are scheduled with the
+	// "default-scheduler" profile, if present here.
+	Profiles []SchedulerProfile
+
+	// Workers is the number of descriptions each of the local, parent and rescheduling
+	// loops schedules at the same time.
+	Workers int32
+}
+
+// SchedulerProfile is a scheduling profile.
+type SchedulerProfile struct {
+	// SchedulerName is the name of the scheduler associated to this profile.
+	SchedulerName string
+
+	// Plugins specify the set of plugins that should be enabled or disabled.
+	// Enabled plugins are the ones that should be enabled in addition to the
+	// default plugins. Disabled plugins are any of the default plugins that
+	// should be disabled.
+	Plugins *Plugins
+
+	// PluginConfig is an optional set of custom plugin arguments for each plugin.
+	// Omitting config args for a plugin is equivalent to using the default config
+	// for that plugin.
+	PluginConfig []PluginConfig
+}
+
+// PluginConfig specifies arguments that should be passed to a plugin at the time of initialization.
+// A plugin that is invoked at multiple extension points is initialized once. Args can have arbitrary structure.
+// It is up to the plugin to process these Args.
+type PluginConfig struct {
+	// Name defines the name of plugin being configured
+	Name string
+	// Args defines the arguments passed to the plugins at the time of initialization. Args can have arbitrary structure.
+	Args runtime.Object
+}
+
 // Plugins include multiple extension points. When specified, the list of plugins for
 // a particular extension point are the only ones enabled. If an extension point is
 // omitted from the config, then the default set of plugins is used for that extension point.
@@ -75,6 +119,9 @@ const (
 	// A value of 0 means adaptive, meaning the scheduler figures out a proper default.
 	DefaultPercentageOfClustersToScore = 0
 
+	// DefaultWorkers is the number of descriptions scheduled at the same time by each scheduling loop.
+	DefaultWorkers int32 = 1
+
 	// MaxCustomPriorityScore is the max score UtilizationShapePoint expects.
 	MaxCustomPriorityScore int64 = 10
 
@@ -0,0 +1,23 @@
+// This file was copied from k8s.io/kubernetes/pkg/scheduler/apis/config/types_pluginargs.go and modified
+
+package apis
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VirtualNodeArgs holds arguments used to configure the VirtualNode plugin.
+type VirtualNodeArgs struct {
+	metav1.TypeMeta
+
+	// ResourceForm is the hypernode resource form of clusters whose replicas are scored as virtual nodes.
+	ResourceForm string
+}
+
+// CorePriorityArgs holds arguments used to configure the CorePriority plugin.
+type CorePriorityArgs struct {
+	metav1.TypeMeta
+
+	// NetEnvironment is the hypernode net environment of clusters whose replicas are scored as core network.
+	NetEnvironment string
+}
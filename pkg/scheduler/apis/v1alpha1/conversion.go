@@ -0,0 +1,70 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+
+	schedulerapis "github.com/lmxia/gaia/pkg/scheduler/apis"
+)
+
+// Convert_v1alpha1_GaiaSchedulerConfiguration_To_apis_SchedulerConfiguration converts a versioned
+// configuration into the internal one used by gaia-scheduler.
+func Convert_v1alpha1_GaiaSchedulerConfiguration_To_apis_SchedulerConfiguration(in *GaiaSchedulerConfiguration,
+	out *schedulerapis.SchedulerConfiguration) {
+	out.Profiles = make([]schedulerapis.SchedulerProfile, 0, len(in.Profiles))
+	for _, prof := range in.Profiles {
+		outProf := schedulerapis.SchedulerProfile{}
+		if prof.SchedulerName != nil {
+			outProf.SchedulerName = *prof.SchedulerName
+		}
+		if prof.Plugins != nil {
+			outProf.Plugins = convertPlugins(prof.Plugins)
+		}
+		for _, pc := range prof.PluginConfig {
+			outPC := schedulerapis.PluginConfig{Name: pc.Name}
+			if pc.Args.Object != nil {
+				outPC.Args = pc.Args.Object
+			} else if len(pc.Args.Raw) > 0 {
+				outPC.Args = &runtime.Unknown{Raw: pc.Args.Raw, ContentType: runtime.ContentTypeJSON}
+			}
+			outProf.PluginConfig = append(outProf.PluginConfig, outPC)
+		}
+		out.Profiles = append(out.Profiles, outProf)
+	}
+	if in.Workers != nil {
+		out.Workers = *in.Workers
+	}
+}
+
+func convertPlugins(in *Plugins) *schedulerapis.Plugins {
+	return &schedulerapis.Plugins{
+		PreFilter:  convertPluginSet(in.PreFilter),
+		Filter:     convertPluginSet(in.Filter),
+		PostFilter: convertPluginSet(in.PostFilter),
+		PreScore:   convertPluginSet(in.PreScore),
+		Score:      convertPluginSet(in.Score),
+		Reserve:    convertPluginSet(in.Reserve),
+		Permit:     convertPluginSet(in.Permit),
+		PreBind:    convertPluginSet(in.PreBind),
+		Bind:       convertPluginSet(in.Bind),
+		PostBind:   convertPluginSet(in.PostBind),
+	}
+}
+
+func convertPluginSet(in PluginSet) schedulerapis.PluginSet {
+	out := schedulerapis.PluginSet{}
+	for _, p := range in.Enabled {
+		out.Enabled = append(out.Enabled, convertPlugin(p))
+	}
+	for _, p := range in.Disabled {
+		out.Disabled = append(out.Disabled, convertPlugin(p))
+	}
+	return out
+}
+
+func convertPlugin(in Plugin) schedulerapis.Plugin {
+	out := schedulerapis.Plugin{Name: in.Name}
+	if in.Weight != nil {
+		out.Weight = *in.Weight
+	}
+	return out
+}
@@ -0,0 +1,41 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	schedulerapis "github.com/lmxia/gaia/pkg/scheduler/apis"
+)
+
+func addConversionFuncs(scheme *runtime.Scheme) error {
+	if err := scheme.AddConversionFunc((*VirtualNodeArgs)(nil), (*schedulerapis.VirtualNodeArgs)(nil),
+		func(a, b interface{}, scope conversion.Scope) error {
+			return Convert_v1alpha1_VirtualNodeArgs_To_apis_VirtualNodeArgs(a.(*VirtualNodeArgs),
+				b.(*schedulerapis.VirtualNodeArgs), scope)
+		}); err != nil {
+		return err
+	}
+	return scheme.AddConversionFunc((*CorePriorityArgs)(nil), (*schedulerapis.CorePriorityArgs)(nil),
+		func(a, b interface{}, scope conversion.Scope) error {
+			return Convert_v1alpha1_CorePriorityArgs_To_apis_CorePriorityArgs(a.(*CorePriorityArgs),
+				b.(*schedulerapis.CorePriorityArgs), scope)
+		})
+}
+
+// Convert_v1alpha1_VirtualNodeArgs_To_apis_VirtualNodeArgs is a conversion function.
+func Convert_v1alpha1_VirtualNodeArgs_To_apis_VirtualNodeArgs(in *VirtualNodeArgs,
+	out *schedulerapis.VirtualNodeArgs, _ conversion.Scope) error {
+	if in.ResourceForm != nil {
+		out.ResourceForm = *in.ResourceForm
+	}
+	return nil
+}
+
+// Convert_v1alpha1_CorePriorityArgs_To_apis_CorePriorityArgs is a conversion function.
+func Convert_v1alpha1_CorePriorityArgs_To_apis_CorePriorityArgs(in *CorePriorityArgs,
+	out *schedulerapis.CorePriorityArgs, _ conversion.Scope) error {
+	if in.NetEnvironment != nil {
+		out.NetEnvironment = *in.NetEnvironment
+	}
+	return nil
+}
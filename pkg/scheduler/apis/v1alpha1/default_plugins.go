@@ -0,0 +1,110 @@
+// This file was copied from k8s.io/kubernetes/pkg/scheduler/apis/config/v1beta2/default_plugins.go and modified
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
+
+	"github.com/lmxia/gaia/pkg/scheduler/framework/plugins/names"
+)
+
+// getDefaultPlugins returns the default set of plugins.
+func getDefaultPlugins() *Plugins {
+	return &Plugins{
+		PreFilter: PluginSet{},
+		Filter: PluginSet{
+			Enabled: []Plugin{
+				{Name: names.TaintToleration},
+				{Name: names.SpecificResource},
+				{Name: names.AffinityDaemon},
+				{Name: names.NetEnviroment},
+				{Name: names.Geolocation},
+				{Name: names.SupplierName},
+				{Name: names.UserAPP},
+				{Name: names.DeploymentCondition},
+				{Name: names.Sandbox},
+			},
+		},
+		PostFilter: PluginSet{
+			Enabled: []Plugin{
+				{Name: names.DefaultPreemption},
+			},
+		},
+		PreScore: PluginSet{},
+		Score: PluginSet{
+			Enabled: []Plugin{
+				{Name: names.CorePriority, Weight: pointer.Int32Ptr(1)},
+				{Name: names.VirtualNode, Weight: pointer.Int32Ptr(1)},
+				{Name: names.DeploymentCondition, Weight: pointer.Int32Ptr(1)},
+			},
+		},
+		Bind: PluginSet{
+			Enabled: []Plugin{
+				{Name: names.DefaultBinder},
+			},
+		},
+	}
+}
+
+// mergePlugins merges the custom set into the given default one, handling disabled sets.
+func mergePlugins(defaultPlugins, customPlugins *Plugins) *Plugins {
+	if customPlugins == nil {
+		return defaultPlugins
+	}
+
+	defaultPlugins.PreFilter = mergePluginSet(defaultPlugins.PreFilter, customPlugins.PreFilter)
+	defaultPlugins.Filter = mergePluginSet(defaultPlugins.Filter, customPlugins.Filter)
+	defaultPlugins.PostFilter = mergePluginSet(defaultPlugins.PostFilter, customPlugins.PostFilter)
+	defaultPlugins.PreScore = mergePluginSet(defaultPlugins.PreScore, customPlugins.PreScore)
+	defaultPlugins.Score = mergePluginSet(defaultPlugins.Score, customPlugins.Score)
+	defaultPlugins.Reserve = mergePluginSet(defaultPlugins.Reserve, customPlugins.Reserve)
+	defaultPlugins.Permit = mergePluginSet(defaultPlugins.Permit, customPlugins.Permit)
+	defaultPlugins.PreBind = mergePluginSet(defaultPlugins.PreBind, customPlugins.PreBind)
+	defaultPlugins.Bind = mergePluginSet(defaultPlugins.Bind, customPlugins.Bind)
+	defaultPlugins.PostBind = mergePluginSet(defaultPlugins.PostBind, customPlugins.PostBind)
+	return defaultPlugins
+}
+
+type pluginIndex struct {
+	index  int
+	plugin Plugin
+}
+
+func mergePluginSet(defaultPluginSet, customPluginSet PluginSet) PluginSet {
+	disabledPlugins := sets.NewString()
+	enabledCustomPlugins := make(map[string]pluginIndex)
+	// replacedPluginIndex is a set of index of plugins, which have replaced the default plugins.
+	replacedPluginIndex := sets.NewInt()
+	for _, disabledPlugin := range customPluginSet.Disabled {
+		disabledPlugins.Insert(disabledPlugin.Name)
+	}
+	for index, enabledPlugin := range customPluginSet.Enabled {
+		enabledCustomPlugins[enabledPlugin.Name] = pluginIndex{index, enabledPlugin}
+	}
+	var enabledPlugins []Plugin
+	if !disabledPlugins.Has("*") {
+		for _, defaultEnabledPlugin := range defaultPluginSet.Enabled {
+			if disabledPlugins.Has(defaultEnabledPlugin.Name) {
+				continue
+			}
+			// The default plugin is explicitly re-configured, update the default plugin accordingly.
+			if customPlugin, ok := enabledCustomPlugins[defaultEnabledPlugin.Name]; ok {
+				// Update the default plugin in place to preserve order.
+				defaultEnabledPlugin = customPlugin.plugin
+				replacedPluginIndex.Insert(customPlugin.index)
+			}
+			enabledPlugins = append(enabledPlugins, defaultEnabledPlugin)
+		}
+	}
+
+	// Append all the custom plugins which haven't replaced any default plugins.
+	// Note: duplicated custom plugins will still be appended here.
+	// If so, the instantiation of scheduler framework will detect it and abort.
+	for index, plugin := range customPluginSet.Enabled {
+		if !replacedPluginIndex.Has(index) {
+			enabledPlugins = append(enabledPlugins, plugin)
+		}
+	}
+	return PluginSet{Enabled: enabledPlugins}
+}
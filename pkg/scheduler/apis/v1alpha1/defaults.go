@@ -0,0 +1,41 @@
+package v1alpha1
+
+import (
+	"k8s.io/utils/pointer"
+
+	schedulerapis "github.com/lmxia/gaia/pkg/scheduler/apis"
+)
+
+// SetDefaultsGaiaSchedulerConfiguration sets additional defaults
+func SetDefaultsGaiaSchedulerConfiguration(obj *GaiaSchedulerConfiguration) {
+	if obj.APIVersion == "" {
+		obj.APIVersion = SchemeGroupVersion.String()
+	}
+	if obj.Kind == "" {
+		obj.Kind = Kind
+	}
+
+	if len(obj.Profiles) == 0 {
+		obj.Profiles = append(obj.Profiles, SchedulerProfile{})
+	}
+	// Only apply a default scheduler name when there is a single profile.
+	// Validation will ensure that every profile has a non-empty unique name.
+	if len(obj.Profiles) == 1 && obj.Profiles[0].SchedulerName == nil {
+		obj.Profiles[0].SchedulerName = pointer.StringPtr(schedulerapis.DefaultSchedulerName)
+	}
+
+	if obj.Workers == nil {
+		obj.Workers = pointer.Int32Ptr(schedulerapis.DefaultWorkers)
+	}
+
+	for i := range obj.Profiles {
+		prof := &obj.Profiles[i]
+		prof.Plugins = mergePlugins(getDefaultPlugins(), prof.Plugins)
+		// a score plugin enabled without a weight counts once, the same as upstream.
+		for j := range prof.Plugins.Score.Enabled {
+			if prof.Plugins.Score.Enabled[j].Weight == nil {
+				prof.Plugins.Score.Enabled[j].Weight = pointer.Int32Ptr(1)
+			}
+		}
+	}
+}
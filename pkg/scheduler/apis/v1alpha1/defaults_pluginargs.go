@@ -0,0 +1,32 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	"github.com/lmxia/gaia/pkg/common"
+)
+
+func addDefaultingFuncs(scheme *runtime.Scheme) error {
+	scheme.AddTypeDefaultingFunc(&VirtualNodeArgs{}, func(obj interface{}) {
+		SetDefaults_VirtualNodeArgs(obj.(*VirtualNodeArgs))
+	})
+	scheme.AddTypeDefaultingFunc(&CorePriorityArgs{}, func(obj interface{}) {
+		SetDefaults_CorePriorityArgs(obj.(*CorePriorityArgs))
+	})
+	return nil
+}
+
+// SetDefaults_VirtualNodeArgs sets the default parameters for the VirtualNode plugin.
+func SetDefaults_VirtualNodeArgs(obj *VirtualNodeArgs) {
+	if obj.ResourceForm == nil {
+		obj.ResourceForm = pointer.StringPtr(common.NodeResourceForm)
+	}
+}
+
+// SetDefaults_CorePriorityArgs sets the default parameters for the CorePriority plugin.
+func SetDefaults_CorePriorityArgs(obj *CorePriorityArgs) {
+	if obj.NetEnvironment == nil {
+		obj.NetEnvironment = pointer.StringPtr(common.NetworkLocationCore)
+	}
+}
@@ -0,0 +1,90 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"k8s.io/utils/pointer"
+
+	schedulerapis "github.com/lmxia/gaia/pkg/scheduler/apis"
+	"github.com/lmxia/gaia/pkg/scheduler/framework/plugins/names"
+)
+
+type DefaultsSuite struct {
+	suite.Suite
+}
+
+func (suite *DefaultsSuite) TestDefaultProfile() {
+	cfg := &GaiaSchedulerConfiguration{}
+	SetDefaultsGaiaSchedulerConfiguration(cfg)
+
+	suite.Equal(SchemeGroupVersion.String(), cfg.APIVersion)
+	suite.Equal(Kind, cfg.Kind)
+	suite.Len(cfg.Profiles, 1)
+	suite.Equal(schedulerapis.DefaultSchedulerName, *cfg.Profiles[0].SchedulerName)
+	suite.Equal(getDefaultPlugins(), cfg.Profiles[0].Plugins)
+	suite.Equal(schedulerapis.DefaultWorkers, *cfg.Workers)
+}
+
+func (suite *DefaultsSuite) TestWorkers() {
+	cfg := &GaiaSchedulerConfiguration{Workers: pointer.Int32Ptr(8)}
+	SetDefaultsGaiaSchedulerConfiguration(cfg)
+	out := &schedulerapis.SchedulerConfiguration{}
+	Convert_v1alpha1_GaiaSchedulerConfiguration_To_apis_SchedulerConfiguration(cfg, out)
+	suite.NoError(schedulerapis.ValidateSchedulerConfiguration(out))
+	suite.Equal(int32(8), out.Workers)
+
+	cfg.Workers = pointer.Int32Ptr(0)
+	Convert_v1alpha1_GaiaSchedulerConfiguration_To_apis_SchedulerConfiguration(cfg, out)
+	suite.Error(schedulerapis.ValidateSchedulerConfiguration(out))
+}
+
+func (suite *DefaultsSuite) TestMergeCustomPlugins() {
+	cfg := &GaiaSchedulerConfiguration{
+		Profiles: []SchedulerProfile{
+			{
+				SchedulerName: pointer.StringPtr("edge-scheduler"),
+				Plugins: &Plugins{
+					Score: PluginSet{
+						Enabled:  []Plugin{{Name: names.CorePriority, Weight: pointer.Int32Ptr(3)}},
+						Disabled: []Plugin{{Name: names.VirtualNode}},
+					},
+				},
+			},
+			{
+				SchedulerName: pointer.StringPtr(schedulerapis.DefaultSchedulerName),
+			},
+		},
+	}
+	SetDefaultsGaiaSchedulerConfiguration(cfg)
+
+	suite.Equal([]Plugin{
+		{Name: names.CorePriority, Weight: pointer.Int32Ptr(3)},
+		{Name: names.DeploymentCondition, Weight: pointer.Int32Ptr(1)},
+	}, cfg.Profiles[0].Plugins.Score.Enabled)
+	suite.Equal(getDefaultPlugins().Filter, cfg.Profiles[0].Plugins.Filter)
+
+	out := &schedulerapis.SchedulerConfiguration{}
+	Convert_v1alpha1_GaiaSchedulerConfiguration_To_apis_SchedulerConfiguration(cfg, out)
+	suite.NoError(schedulerapis.ValidateSchedulerConfiguration(out))
+	suite.Equal("edge-scheduler", out.Profiles[0].SchedulerName)
+	suite.Equal(int32(3), out.Profiles[0].Plugins.Score.Enabled[0].Weight)
+}
+
+func (suite *DefaultsSuite) TestDefaultScoreWeight() {
+	cfg := &GaiaSchedulerConfiguration{
+		Profiles: []SchedulerProfile{{
+			Plugins: &Plugins{Score: PluginSet{Enabled: []Plugin{{Name: names.CorePriority}}}},
+		}},
+	}
+	SetDefaultsGaiaSchedulerConfiguration(cfg)
+	suite.Equal(pointer.Int32Ptr(1), cfg.Profiles[0].Plugins.Score.Enabled[0].Weight)
+
+	out := &schedulerapis.SchedulerConfiguration{}
+	Convert_v1alpha1_GaiaSchedulerConfiguration_To_apis_SchedulerConfiguration(cfg, out)
+	suite.NoError(schedulerapis.ValidateSchedulerConfiguration(out))
+}
+
+func TestDefaultsSuite(t *testing.T) {
+	suite.Run(t, new(DefaultsSuite))
+}
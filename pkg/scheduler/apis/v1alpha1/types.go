@@ -0,0 +1,132 @@
+// This file was copied from k8s.io/kube-scheduler/config/v1beta2/types.go and modified
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// GroupName is the group name of the gaia-scheduler configuration.
+	GroupName = "scheduler.gaia.io"
+	// Version is the version of the gaia-scheduler configuration.
+	Version = "v1alpha1"
+	// Kind is the kind of the gaia-scheduler configuration.
+	Kind = "GaiaSchedulerConfiguration"
+)
+
+// GaiaSchedulerConfiguration configures a scheduler
+type GaiaSchedulerConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Profiles are scheduling profiles that gaia-scheduler supports. Descriptions can
+	// choose to be scheduled under a particular profile by setting spec.schedulerName.
+	// Descriptions that don't specify any scheduler name are scheduled
+	// with the "default-scheduler" profile, if present here.
+	// +listType=map
+	// +listMapKey=schedulerName
+	Profiles []SchedulerProfile `json:"profiles,omitempty"`
+
+	// Workers is the number of descriptions each of the local, parent and rescheduling
+	// loops schedules at the same time. Workers share the scheduler cache, a description
+	// whose resources were taken by another worker meanwhile is scheduled again.
+	// Defaults to 1.
+	Workers *int32 `json:"workers,omitempty"`
+}
+
+// SchedulerProfile is a scheduling profile.
+type SchedulerProfile struct {
+	// SchedulerName is the name of the scheduler associated to this profile.
+	// If SchedulerName matches with the description's "spec.schedulerName", then the description
+	// is scheduled with this profile.
+	SchedulerName *string `json:"schedulerName,omitempty"`
+
+	// Plugins specify the set of plugins that should be enabled or disabled.
+	// Enabled plugins are the ones that should be enabled in addition to the
+	// default plugins. Disabled plugins are any of the default plugins that
+	// should be disabled.
+	// When no enabled or disabled plugin is specified for an extension point,
+	// default plugins for that extension point will be used if there is any.
+	Plugins *Plugins `json:"plugins,omitempty"`
+
+	// PluginConfig is an optional set of custom plugin arguments for each plugin.
+	// Omitting config args for a plugin is equivalent to using the default config
+	// for that plugin.
+	// +listType=map
+	// +listMapKey=name
+	PluginConfig []PluginConfig `json:"pluginConfig,omitempty"`
+}
+
+// Plugins include multiple extension points. When specified, the list of plugins for
+// a particular extension point are the only ones enabled. If an extension point is
+// omitted from the config, then the default set of plugins is used for that extension point.
+// Enabled plugins are called in the order specified here, after default plugins. If they need to
+// be invoked before default plugins, default plugins must be disabled and re-enabled here in desired order.
+type Plugins struct {
+	// PreFilter is a list of plugins that should be invoked at "PreFilter" extension point of the scheduling framework.
+	PreFilter PluginSet `json:"preFilter,omitempty"`
+
+	// Filter is a list of plugins that should be invoked when filtering out clusters that cannot run the Description.
+	Filter PluginSet `json:"filter,omitempty"`
+
+	// PostFilter is a list of plugins that are invoked after filtering phase, no matter whether filtering succeeds or not.
+	PostFilter PluginSet `json:"postFilter,omitempty"`
+
+	// PreScore is a list of plugins that are invoked before scoring.
+	PreScore PluginSet `json:"preScore,omitempty"`
+
+	// Score is a list of plugins that should be invoked when ranking clusters that have passed the filtering phase.
+	Score PluginSet `json:"score,omitempty"`
+
+	// Reserve is a list of plugins invoked when reserving/unreserving resources
+	// after a cluster is assigned to run the Description.
+	Reserve PluginSet `json:"reserve,omitempty"`
+
+	// Permit is a list of plugins that control binding of a Description. These plugins can prevent or delay binding of a Description.
+	Permit PluginSet `json:"permit,omitempty"`
+
+	// PreBind is a list of plugins that should be invoked before a Description is bound.
+	PreBind PluginSet `json:"preBind,omitempty"`
+
+	// Bind is a list of plugins that should be invoked at "Bind" extension point of the scheduling framework.
+	// The scheduler call these plugins in order. Scheduler skips the rest of these plugins as soon as one returns success.
+	Bind PluginSet `json:"bind,omitempty"`
+
+	// PostBind is a list of plugins that should be invoked after a Description is successfully bound.
+	PostBind PluginSet `json:"postBind,omitempty"`
+}
+
+// PluginSet specifies enabled and disabled plugins for an extension point.
+// If an array is empty, missing, or nil, default plugins at that extension point will be used.
+type PluginSet struct {
+	// Enabled specifies plugins that should be enabled in addition to default plugins.
+	// If the default plugin is also configured in the scheduler config file, the weight of plugin will
+	// be overridden accordingly.
+	// These are called after default plugins and in the same order specified here.
+	// +listType=atomic
+	Enabled []Plugin `json:"enabled,omitempty"`
+	// Disabled specifies default plugins that should be disabled.
+	// When all default plugins need to be disabled, an array containing only one "*" should be provided.
+	// +listType=map
+	// +listMapKey=name
+	Disabled []Plugin `json:"disabled,omitempty"`
+}
+
+// Plugin specifies a plugin name and its weight when applicable. Weight is used only for Score plugins.
+type Plugin struct {
+	// Name defines the name of plugin
+	Name string `json:"name"`
+	// Weight defines the weight of plugin, only used for Score plugins.
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+// PluginConfig specifies arguments that should be passed to a plugin at the time of initialization.
+// A plugin that is invoked at multiple extension points is initialized once. Args can have arbitrary structure.
+// It is up to the plugin to process these Args.
+type PluginConfig struct {
+	// Name defines the name of plugin being configured
+	Name string `json:"name"`
+	// Args defines the arguments passed to the plugins at the time of initialization. Args can have arbitrary structure.
+	Args runtime.RawExtension `json:"args,omitempty"`
+}
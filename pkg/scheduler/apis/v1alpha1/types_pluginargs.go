@@ -0,0 +1,25 @@
+// This file was copied from k8s.io/kube-scheduler/config/v1beta2/types_pluginargs.go and modified
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VirtualNodeArgs holds arguments used to configure the VirtualNode plugin.
+type VirtualNodeArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// ResourceForm is the hypernode resource form of clusters whose replicas are scored as virtual nodes.
+	// Defaults to "pool".
+	ResourceForm *string `json:"resourceForm,omitempty"`
+}
+
+// CorePriorityArgs holds arguments used to configure the CorePriority plugin.
+type CorePriorityArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// NetEnvironment is the hypernode net environment of clusters whose replicas are scored as core network.
+	// Defaults to "core".
+	NetEnvironment *string `json:"netEnvironment,omitempty"`
+}
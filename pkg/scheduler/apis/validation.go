@@ -0,0 +1,46 @@
+package apis
+
+import (
+	"fmt"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ValidateSchedulerConfiguration ensures validation of the SchedulerConfiguration struct
+func ValidateSchedulerConfiguration(cc *SchedulerConfiguration) error {
+	var errs []error
+	if len(cc.Profiles) == 0 {
+		errs = append(errs, fmt.Errorf("profiles: at least one profile is required"))
+	}
+	if cc.Workers <= 0 {
+		errs = append(errs, fmt.Errorf("workers: must be greater than 0, got %d", cc.Workers))
+	}
+	names := sets.NewString()
+	for i, prof := range cc.Profiles {
+		if len(prof.SchedulerName) == 0 {
+			errs = append(errs, fmt.Errorf("profiles[%d].schedulerName: required value", i))
+		} else if names.Has(prof.SchedulerName) {
+			errs = append(errs, fmt.Errorf("profiles[%d].schedulerName: duplicate value %q", i, prof.SchedulerName))
+		}
+		names.Insert(prof.SchedulerName)
+
+		if prof.Plugins != nil {
+			for _, p := range prof.Plugins.Score.Enabled {
+				if p.Weight <= 0 || int64(p.Weight) > MaxWeight {
+					errs = append(errs, fmt.Errorf("profiles[%d].plugins.score.enabled[%q].weight: must be in the range of (0, %d]",
+						i, p.Name, MaxWeight))
+				}
+			}
+		}
+
+		argNames := sets.NewString()
+		for j, pc := range prof.PluginConfig {
+			if argNames.Has(pc.Name) {
+				errs = append(errs, fmt.Errorf("profiles[%d].pluginConfig[%d]: duplicate plugin %q", i, j, pc.Name))
+			}
+			argNames.Insert(pc.Name)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
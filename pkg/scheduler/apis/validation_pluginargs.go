@@ -0,0 +1,21 @@
+package apis
+
+import (
+	"fmt"
+)
+
+// ValidateVirtualNodeArgs validates that VirtualNodeArgs are correct.
+func ValidateVirtualNodeArgs(args *VirtualNodeArgs) error {
+	if len(args.ResourceForm) == 0 {
+		return fmt.Errorf("resourceForm: required value")
+	}
+	return nil
+}
+
+// ValidateCorePriorityArgs validates that CorePriorityArgs are correct.
+func ValidateCorePriorityArgs(args *CorePriorityArgs) error {
+	if len(args.NetEnvironment) == 0 {
+		return fmt.Errorf("netEnvironment: required value")
+	}
+	return nil
+}
@@ -2,13 +2,21 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lmxia/gaia/pkg/common"
 	gaiaClientSet "github.com/lmxia/gaia/pkg/generated/clientset/versioned"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 
 	"github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
 	clusterapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
@@ -33,6 +41,55 @@ type Cache interface {
 	SetSelfClusterName(name string)
 
 	GetSelfClusterName() string
+
+	// Generation returns the number of assumptions made so far. It is read before a description is scheduled
+	// and passed to AssumeDescription once the description is placed.
+	Generation() int64
+
+	// AssumeDescription assumes the resources requested by a description on each cluster, keyed by cluster name,
+	// are taken. They are subtracted from the available resources of the clusters listed by the cache until
+	// the clusters report a status observed a collection period after the binding finished. An earlier assumption of the same
+	// description, e.g. before it was rescheduled, is replaced.
+	// Descriptions are scheduled concurrently, so the clusters may have been assumed by other descriptions
+	// since generation was read. If any cluster then lacks the requested resources, nothing is assumed and
+	// ErrSchedulingConflict is returned.
+	AssumeDescription(key string, requests map[string]corev1.ResourceList, generation int64) error
+
+	// FinishBinding signals that the binding of an assumed description is done, the assumption expires
+	// once the clusters report a status that counts the bound workloads, or after a while if they never do.
+	FinishBinding(key string) error
+
+	// ForgetDescription removes the assumption of a description, e.g. after its binding failed.
+	ForgetDescription(key string) error
+
+	// PreoccupyDescription reserves the resources of the preoccupying components of an assumed description
+	// on each cluster until a deadline, whatever the clusters report. An earlier reservation of the same
+	// description is replaced, forgetting the description releases it.
+	PreoccupyDescription(key string, requests map[string]corev1.ResourceList, until time.Time) error
+}
+
+// ErrSchedulingConflict is returned when the resources a description is placed on were assumed by
+// another description scheduled at the same time.
+var ErrSchedulingConflict = errors.New("resources were taken by a description scheduled at the same time")
+
+// durationToExpireAssumedDescription is how long an assumption lasts after its binding finished if the clusters
+// never report a newer status, e.g. they went offline.
+const durationToExpireAssumedDescription = 5 * time.Minute
+
+// durationToReflectBinding is how long after the binding finished a cluster status is observed before it counts
+// the bound workloads. A status collected earlier may have been collected before the workloads existed.
+const durationToReflectBinding = common.DefaultClusterStatusCollectFrequency
+
+// assumedDescription is the resources a description is assumed to take on each cluster.
+type assumedDescription struct {
+	requests map[string]corev1.ResourceList
+	// bindingFinished is the time the binding finished, zero if it is in progress.
+	bindingFinished time.Time
+	// preoccupied are the resources reserved on each cluster until preoccupiedUntil.
+	preoccupied      map[string]corev1.ResourceList
+	preoccupiedUntil time.Time
+	// generation is the generation of the cache the description was assumed in.
+	generation int64
 }
 
 type schedulerCache struct {
@@ -40,6 +97,16 @@ type schedulerCache struct {
 	resourcebindingLister applisters.ResourceBindingLister
 	localGaiaClient       *gaiaClientSet.Clientset
 	selfClusterName       string
+
+	clock clock.Clock
+	ttl   time.Duration
+	// settle is how long after a binding finished the status of a cluster reflects it.
+	settle time.Duration
+	// assumedDescriptions are the assumptions keyed by description key.
+	assumedDescriptions map[string]*assumedDescription
+	// generation counts the assumptions.
+	generation int64
+	mu         sync.RWMutex
 }
 
 // NumClusters returns the number of clusters in the cache.
@@ -58,7 +125,15 @@ func (s *schedulerCache) ListClusters(labelSelector *metav1.LabelSelector) ([]*c
 	if err != nil {
 		return nil, err
 	}
-	return s.clusterListers.List(selector)
+	clusters, err := s.clusterListers.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	s.cleanupAssumedDescriptions()
+	for i := range clusters {
+		clusters[i] = s.withAssumedRequests(clusters[i])
+	}
+	return clusters, nil
 }
 
 // Get returns the ManagedCluster of the given cluster.
@@ -70,13 +145,21 @@ func (s *schedulerCache) GetCLuster(namespacedName string) (*clusterapi.ManagedC
 		return nil, err
 	}
 
-	return s.clusterListers.ManagedClusters(ns).Get(name)
+	cluster, err := s.clusterListers.ManagedClusters(ns).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.withAssumedRequests(cluster), nil
 }
 
 func New(clusterListers platformlisters.ManagedClusterLister, localGaiaClient *gaiaClientSet.Clientset) Cache {
 	return &schedulerCache{
-		clusterListers:  clusterListers,
-		localGaiaClient: localGaiaClient,
+		clusterListers:      clusterListers,
+		localGaiaClient:     localGaiaClient,
+		clock:               clock.RealClock{},
+		ttl:                 durationToExpireAssumedDescription,
+		settle:              durationToReflectBinding,
+		assumedDescriptions: make(map[string]*assumedDescription),
 	}
 }
 
@@ -95,3 +178,175 @@ func (s *schedulerCache) GetNetworkRequirement(desc *v1alpha1.Description) (*v1a
 	}
 	return nwr, nil
 }
+
+// Generation returns the number of assumptions made so far.
+func (s *schedulerCache) Generation() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.generation
+}
+
+// AssumeDescription records the resources the description with key takes on each cluster, unless they were
+// taken by descriptions assumed after generation.
+func (s *schedulerCache) AssumeDescription(key string, requests map[string]corev1.ResourceList, generation int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkConflict(key, requests, generation); err != nil {
+		return err
+	}
+	s.generation++
+	s.assumedDescriptions[key] = &assumedDescription{requests: requests, generation: s.generation}
+	return nil
+}
+
+// checkConflict returns ErrSchedulingConflict if a cluster that was assumed by another description after
+// generation can't afford requests any more. The clusters nobody assumed meanwhile are left to the decision
+// of the scheduling algorithm. The caller must hold the lock.
+func (s *schedulerCache) checkConflict(key string, requests map[string]corev1.ResourceList, generation int64) error {
+	contended := make(map[string]bool)
+	for otherKey, assumed := range s.assumedDescriptions {
+		if otherKey == key || assumed.generation <= generation {
+			continue
+		}
+		for name := range assumed.requests {
+			if _, ok := requests[name]; ok {
+				contended[name] = true
+			}
+		}
+	}
+	if len(contended) == 0 {
+		return nil
+	}
+
+	clusters, err := s.clusterListers.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	now := s.clock.Now()
+	for _, cluster := range clusters {
+		if !contended[cluster.Name] {
+			continue
+		}
+		assumedRequests := s.assumedRequestsOn(cluster, key, now)
+		for name, quantity := range requests[cluster.Name] {
+			available, ok := cluster.Status.Available[name]
+			if !ok {
+				continue
+			}
+			available = available.DeepCopy()
+			if taken, ok := assumedRequests[name]; ok {
+				available.Sub(taken)
+			}
+			if available.Cmp(quantity) < 0 {
+				return fmt.Errorf("%w: %s of cluster %s", ErrSchedulingConflict, name, cluster.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// FinishBinding starts the expiry of the assumption of the description with key.
+func (s *schedulerCache) FinishBinding(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	assumed, ok := s.assumedDescriptions[key]
+	if !ok {
+		return fmt.Errorf("description %v wasn't assumed so cannot finish binding", key)
+	}
+	assumed.bindingFinished = s.clock.Now()
+	return nil
+}
+
+// ForgetDescription removes the assumption of the description with key.
+func (s *schedulerCache) ForgetDescription(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.assumedDescriptions[key]; !ok {
+		return fmt.Errorf("description %v wasn't assumed so cannot be forgotten", key)
+	}
+	delete(s.assumedDescriptions, key)
+	return nil
+}
+
+// PreoccupyDescription reserves requests for the description with key until the deadline.
+func (s *schedulerCache) PreoccupyDescription(key string, requests map[string]corev1.ResourceList, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	assumed, ok := s.assumedDescriptions[key]
+	if !ok {
+		return fmt.Errorf("description %v wasn't assumed so cannot preoccupy", key)
+	}
+	assumed.preoccupied = requests
+	assumed.preoccupiedUntil = until
+	return nil
+}
+
+// cleanupAssumedDescriptions removes the assumptions that finished binding longer than ttl ago and hold no
+// reservation any more.
+func (s *schedulerCache) cleanupAssumedDescriptions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock.Now()
+	for key, assumed := range s.assumedDescriptions {
+		if !assumed.bindingFinished.IsZero() && now.After(assumed.bindingFinished.Add(s.ttl)) && !now.Before(assumed.preoccupiedUntil) {
+			klog.V(4).InfoS("Assumed description expired", "description", key)
+			delete(s.assumedDescriptions, key)
+		}
+	}
+}
+
+// withAssumedRequests returns a copy of cluster whose available resources exclude the assumed requests that
+// its status doesn't reflect yet and the reservations that haven't expired, or cluster itself if there are none.
+func (s *schedulerCache) withAssumedRequests(cluster *clusterapi.ManagedCluster) *clusterapi.ManagedCluster {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	assumedRequests := s.assumedRequestsOn(cluster, "", s.clock.Now())
+	if len(assumedRequests) == 0 {
+		return cluster
+	}
+
+	cluster = cluster.DeepCopy()
+	for name, quantity := range assumedRequests {
+		available, ok := cluster.Status.Available[name]
+		if !ok {
+			continue
+		}
+		available.Sub(quantity)
+		if available.Sign() < 0 {
+			available.Set(0)
+		}
+		cluster.Status.Available[name] = available
+	}
+	return cluster
+}
+
+// assumedRequestsOn sums the resources assumed on cluster that its status doesn't reflect yet and the reservations
+// that haven't expired, leaving out the description with key except. The caller must hold the lock.
+func (s *schedulerCache) assumedRequestsOn(cluster *clusterapi.ManagedCluster, except string, now time.Time) corev1.ResourceList {
+	var assumedRequests corev1.ResourceList
+	for key, assumed := range s.assumedDescriptions {
+		if key == except {
+			continue
+		}
+		requests, ok := assumed.requests[cluster.Name]
+		// a status observed a collection period after the binding finished has taken the workloads into account,
+		// the reservation is held anyway until it expires.
+		if !assumed.bindingFinished.IsZero() && cluster.Status.LastObservedTime.Time.After(assumed.bindingFinished.Add(s.settle)) {
+			requests, ok = assumed.preoccupied[cluster.Name]
+			ok = ok && now.Before(assumed.preoccupiedUntil)
+		}
+		if !ok {
+			continue
+		}
+		if assumedRequests == nil {
+			assumedRequests = make(corev1.ResourceList)
+		}
+		for name, quantity := range requests {
+			total := assumedRequests[name]
+			total.Add(quantity)
+			assumedRequests[name] = total
+		}
+	}
+	return assumedRequests
+}
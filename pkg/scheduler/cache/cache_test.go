@@ -0,0 +1,147 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	clusterapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+	platformlisters "github.com/lmxia/gaia/pkg/generated/listers/platform/v1alpha1"
+)
+
+type AssumeSuite struct {
+	clock   *clocktesting.FakeClock
+	indexer cache.Indexer
+	cache   *schedulerCache
+	suite.Suite
+}
+
+func (suite *AssumeSuite) SetupTest() {
+	suite.clock = clocktesting.NewFakeClock(time.Now())
+	suite.indexer = cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	suite.NoError(suite.indexer.Add(suite.newCluster(suite.clock.Now().Add(-time.Minute))))
+	suite.cache = New(platformlisters.NewManagedClusterLister(suite.indexer), nil).(*schedulerCache)
+	suite.cache.clock = suite.clock
+}
+
+func (suite *AssumeSuite) newCluster(observed time.Time) *clusterapi.ManagedCluster {
+	return &clusterapi.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Namespace: "gaia-cluster1"},
+		Status: clusterapi.ManagedClusterStatus{
+			LastObservedTime: metav1.NewTime(observed),
+			Available: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+		},
+	}
+}
+
+func (suite *AssumeSuite) availableCPU() int64 {
+	clusters, err := suite.cache.ListClusters(&metav1.LabelSelector{})
+	suite.NoError(err)
+	suite.Len(clusters, 1)
+	return clusters[0].Status.Available.Cpu().MilliValue()
+}
+
+func (suite *AssumeSuite) assume(key, cpu string) {
+	suite.NoError(suite.assumeSince(key, cpu, suite.cache.Generation()))
+}
+
+func (suite *AssumeSuite) assumeSince(key, cpu string, generation int64) error {
+	return suite.cache.AssumeDescription(key, map[string]corev1.ResourceList{
+		"cluster1": {corev1.ResourceCPU: resource.MustParse(cpu)},
+	}, generation)
+}
+
+func (suite *AssumeSuite) TestAssumeAndForget() {
+	suite.assume("gaia-reserved/a", "1")
+	suite.assume("gaia-reserved/b", "500m")
+	suite.Equal(int64(2500), suite.availableCPU())
+
+	cluster, err := suite.cache.GetCLuster("gaia-cluster1/cluster1")
+	suite.NoError(err)
+	suite.Equal(int64(2500), cluster.Status.Available.Cpu().MilliValue())
+
+	suite.NoError(suite.cache.ForgetDescription("gaia-reserved/a"))
+	suite.Equal(int64(3500), suite.availableCPU())
+	suite.Error(suite.cache.ForgetDescription("gaia-reserved/a"))
+
+	suite.assume("gaia-reserved/b", "5")
+	suite.Equal(int64(0), suite.availableCPU(), "reassumed, and never below zero")
+
+	stored, _, _ := suite.indexer.GetByKey("gaia-cluster1/cluster1")
+	suite.Equal(int64(4000), stored.(*clusterapi.ManagedCluster).Status.Available.Cpu().MilliValue(),
+		"the informer cache is not modified")
+}
+
+func (suite *AssumeSuite) TestNewerStatusReflectsBinding() {
+	suite.assume("gaia-reserved/a", "1")
+	suite.NoError(suite.cache.FinishBinding("gaia-reserved/a"))
+	suite.Equal(int64(3000), suite.availableCPU())
+
+	suite.clock.Step(time.Second)
+	suite.NoError(suite.indexer.Update(suite.newCluster(suite.clock.Now())))
+	suite.Equal(int64(3000), suite.availableCPU(), "a status collected right after binding may miss the workloads")
+
+	suite.clock.Step(durationToReflectBinding)
+	suite.NoError(suite.indexer.Update(suite.newCluster(suite.clock.Now())))
+	suite.Equal(int64(4000), suite.availableCPU())
+}
+
+func (suite *AssumeSuite) TestExpire() {
+	suite.assume("gaia-reserved/a", "1")
+	suite.assume("gaia-reserved/b", "1")
+	suite.NoError(suite.cache.FinishBinding("gaia-reserved/a"))
+	suite.Error(suite.cache.FinishBinding("gaia-reserved/c"))
+
+	suite.clock.Step(durationToExpireAssumedDescription + time.Second)
+	suite.Equal(int64(3000), suite.availableCPU(), "bindings in progress never expire")
+	suite.Len(suite.cache.assumedDescriptions, 1)
+}
+
+func (suite *AssumeSuite) TestPreoccupy() {
+	suite.assume("gaia-reserved/a", "2")
+	suite.NoError(suite.cache.FinishBinding("gaia-reserved/a"))
+	suite.NoError(suite.cache.PreoccupyDescription("gaia-reserved/a", map[string]corev1.ResourceList{
+		"cluster1": {corev1.ResourceCPU: resource.MustParse("1")},
+	}, suite.clock.Now().Add(10*time.Minute)))
+	suite.Error(suite.cache.PreoccupyDescription("gaia-reserved/b", nil, suite.clock.Now()))
+
+	suite.clock.Step(durationToReflectBinding + time.Second)
+	suite.NoError(suite.indexer.Update(suite.newCluster(suite.clock.Now())))
+	suite.Equal(int64(3000), suite.availableCPU(), "the preoccupied resources outlive a newer status")
+
+	suite.clock.Step(durationToExpireAssumedDescription + time.Second)
+	suite.Equal(int64(3000), suite.availableCPU(), "and the expiration of the assumption")
+
+	suite.clock.Step(10 * time.Minute)
+	suite.Equal(int64(4000), suite.availableCPU())
+	suite.Empty(suite.cache.assumedDescriptions)
+}
+
+func (suite *AssumeSuite) TestConflict() {
+	// three workers list the clusters before any of them is done.
+	generation := suite.cache.Generation()
+	suite.NoError(suite.assumeSince("gaia-reserved/a", "3", generation))
+	err := suite.assumeSince("gaia-reserved/b", "2", generation)
+	suite.ErrorIs(err, ErrSchedulingConflict)
+	suite.NotContains(suite.cache.assumedDescriptions, "gaia-reserved/b")
+	suite.NoError(suite.assumeSince("gaia-reserved/c", "1", generation), "what is left still fits")
+	suite.Equal(int64(0), suite.availableCPU())
+
+	suite.NoError(suite.cache.ForgetDescription("gaia-reserved/a"))
+	suite.NoError(suite.assumeSince("gaia-reserved/b", "2", generation))
+	suite.NoError(suite.assumeSince("gaia-reserved/c", "5", suite.cache.Generation()),
+		"a description that saw the other assumptions is up to the scheduling algorithm")
+}
+
+func TestAssumeSuite(t *testing.T) {
+	suite.Run(t, new(AssumeSuite))
+}
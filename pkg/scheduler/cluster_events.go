@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	platformapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+)
+
+// The cluster events that might make unschedulable descriptions schedulable.
+const (
+	ClusterAdd           = "ManagedClusterAdd"
+	ClusterReady         = "ManagedClusterReady"
+	ClusterCapacityGrow  = "ManagedClusterCapacityGrow"
+	ClusterTaintRemove   = "ManagedClusterTaintRemove"
+	ClusterLabelChange   = "ManagedClusterLabelChange"
+	ClusterSandboxChange = "ManagedClusterSandboxChange"
+)
+
+// addClusterEventHandlers moves the unschedulable descriptions of all scheduling queues back to be scheduled
+// on the cluster events that might make them schedulable.
+func (sched *Scheduler) addClusterEventHandlers() {
+	sched.localGaiaAllFactory.Platform().V1alpha1().ManagedClusters().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			sched.moveAllToActiveOrBackoffQueue(ClusterAdd)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if event := clusterUpdateEvent(oldObj.(*platformapi.ManagedCluster), newObj.(*platformapi.ManagedCluster)); len(event) > 0 {
+				sched.moveAllToActiveOrBackoffQueue(event)
+			}
+		},
+	})
+}
+
+func (sched *Scheduler) moveAllToActiveOrBackoffQueue(event string) {
+	sched.localSchedulingQueue.MoveAllToActiveOrBackoffQueue(event)
+	sched.parentSchedulingQueue.MoveAllToActiveOrBackoffQueue(event)
+	sched.parentSchedulingRetryQueue.MoveAllToActiveOrBackoffQueue(event)
+}
+
+// clusterUpdateEvent returns the event of the update of a cluster from old to new that might make descriptions
+// schedulable, or empty if there is none. Heartbeats that only report less capacity are not events.
+func clusterUpdateEvent(old, new *platformapi.ManagedCluster) string {
+	switch {
+	case !old.Status.Readyz && new.Status.Readyz:
+		return ClusterReady
+	case grows(old.Status.Available, new.Status.Available) || grows(old.Status.MaxNodeAvailable, new.Status.MaxNodeAvailable):
+		return ClusterCapacityGrow
+	case taintRemoved(old.Spec.Taints, new.Spec.Taints):
+		return ClusterTaintRemove
+	case !reflect.DeepEqual(old.Labels, new.Labels):
+		return ClusterLabelChange
+	case !reflect.DeepEqual(old.Status.Sandboxes, new.Status.Sandboxes):
+		return ClusterSandboxChange
+	}
+	return ""
+}
+
+// grows returns true if any resource of new is more than in old.
+func grows(old, new corev1.ResourceList) bool {
+	for name, quantity := range new {
+		if value, ok := old[name]; !ok || quantity.Cmp(value) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// taintRemoved returns true if any taint of old is not in new.
+func taintRemoved(old, new []corev1.Taint) bool {
+	for i := range old {
+		found := false
+		for j := range new {
+			if old[i].MatchTaint(&new[j]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return true
+		}
+	}
+	return false
+}
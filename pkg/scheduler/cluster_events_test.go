@@ -0,0 +1,61 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	platformapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+)
+
+type ClusterEventSuite struct {
+	cluster *platformapi.ManagedCluster
+	suite.Suite
+}
+
+func (suite *ClusterEventSuite) SetupTest() {
+	suite.cluster = &platformapi.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Labels: map[string]string{"tier": "edge"}},
+		Spec: platformapi.ManagedClusterSpec{Taints: []corev1.Taint{
+			{Key: "maintenance", Effect: corev1.TaintEffectNoSchedule},
+		}},
+		Status: platformapi.ManagedClusterStatus{
+			Readyz:    true,
+			Available: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+		},
+	}
+}
+
+func (suite *ClusterEventSuite) TestClusterUpdateEvent() {
+	updated := suite.cluster.DeepCopy()
+	updated.Status.LastObservedTime = metav1.Now()
+	suite.Empty(clusterUpdateEvent(suite.cluster, updated), "a heartbeat is no event")
+
+	updated.Status.Available[corev1.ResourceCPU] = resource.MustParse("2")
+	suite.Empty(clusterUpdateEvent(suite.cluster, updated), "neither is less capacity")
+	updated.Status.Available[corev1.ResourceCPU] = resource.MustParse("6")
+	suite.Equal(ClusterCapacityGrow, clusterUpdateEvent(suite.cluster, updated))
+
+	updated = suite.cluster.DeepCopy()
+	updated.Spec.Taints = nil
+	suite.Equal(ClusterTaintRemove, clusterUpdateEvent(suite.cluster, updated))
+	suite.Empty(clusterUpdateEvent(updated, suite.cluster), "a taint added makes nothing schedulable")
+
+	updated = suite.cluster.DeepCopy()
+	updated.Labels["tier"] = "cloud"
+	suite.Equal(ClusterLabelChange, clusterUpdateEvent(suite.cluster, updated))
+
+	updated = suite.cluster.DeepCopy()
+	updated.Status.Sandboxes = []string{"kata", "runc"}
+	suite.Equal(ClusterSandboxChange, clusterUpdateEvent(suite.cluster, updated))
+
+	suite.cluster.Status.Readyz = false
+	suite.Equal(ClusterReady, clusterUpdateEvent(suite.cluster, updated))
+}
+
+func TestClusterEventSuite(t *testing.T) {
+	suite.Run(t, new(ClusterEventSuite))
+}
@@ -2,10 +2,12 @@ package interfaces
 
 import (
 	"context"
+	"math"
+	"time"
 
+	"k8s.io/apimachinery/pkg/types"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
-	"math"
 
 	appsapi "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
 	clusterapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
@@ -158,7 +160,7 @@ type PostFilterPlugin interface {
 	// Optionally, a non-nil PostFilterResult may be returned along with a Success status. For example,
 	// a preemption plugin may choose to return nominatedClusterName, so that framework can reuse that to update the
 	// preemptor subscription's .spec.status.nominatedClusterName field.
-	PostFilter(ctx context.Context, sub *appsapi.Component, filteredClusterStatusMap ClusterToStatusMap) (*PostFilterResult, *Status)
+	PostFilter(ctx context.Context, desc *appsapi.Description, filteredClusterStatusMap ClusterToStatusMap) (*PostFilterResult, *Status)
 }
 
 // PreScorePlugin is an interface for "PreScore" plugin. PreScore is an
@@ -196,6 +198,73 @@ type ScorePlugin interface {
 	ScoreExtensions() ScoreExtensions
 }
 
+// ReservePlugin is an interface for plugins with Reserve and Unreserve
+// methods. These are meant to update the state of the plugin. This concept
+// used to be called 'assume' in the original scheduler. These plugins should
+// return only Success or Error in Status.code. However, the scheduler accepts
+// other valid codes as well. Anything other than Success will lead to
+// rejection of the description.
+type ReservePlugin interface {
+	Plugin
+	// Reserve is called by the scheduling framework when the scheduler cache is
+	// updated. If this method returns a failed Status, the scheduler will call
+	// the Unreserve method for all enabled ReservePlugins.
+	Reserve(ctx context.Context, desc *appsapi.Description, rbs []*appsapi.ResourceBinding) *Status
+	// Unreserve is called by the scheduling framework when a reserved description was
+	// rejected, an error occurred during reservation of subsequent plugins, or
+	// in a later phase. The Unreserve method implementation must be idempotent
+	// and may be called by the scheduler even if the corresponding Reserve
+	// method for the same plugin was not called.
+	Unreserve(ctx context.Context, desc *appsapi.Description, rbs []*appsapi.ResourceBinding)
+}
+
+// PermitPlugin is an interface that must be implemented by "Permit" plugins.
+// These plugins are called before a description is bound to child clusters.
+type PermitPlugin interface {
+	Plugin
+	// Permit is called before binding a description (and before prebind plugins). Permit
+	// plugins are used to prevent or delay the binding of a description. A permit plugin
+	// must return success or wait with timeout duration, or the description will be rejected.
+	// The description will also be rejected if the wait timeout or the description is rejected while waiting.
+	// Note that if the plugin returns "wait", the framework will wait only
+	// after running the remaining plugins given that no other plugin rejects the description.
+	Permit(ctx context.Context, desc *appsapi.Description, rbs []*appsapi.ResourceBinding) (*Status, time.Duration)
+}
+
+// PreBindPlugin is an interface that must be implemented by "PreBind" plugins.
+// These plugins are called before a description is bound.
+type PreBindPlugin interface {
+	Plugin
+	// PreBind is called before binding a description. All prebind plugins must return
+	// success or the description will be rejected and won't be sent for binding.
+	PreBind(ctx context.Context, desc *appsapi.Description, rbs []*appsapi.ResourceBinding) *Status
+}
+
+// BindPlugin is an interface that must be implemented by "Bind" plugins. Bind
+// plugins are used to write the ResourceBindings and the Description into the
+// namespaces of the child clusters.
+type BindPlugin interface {
+	Plugin
+	// Bind plugins will not be called until all pre-bind plugins have completed. Each
+	// bind plugin is called in the configured order. A bind plugin may choose whether
+	// or not to handle the given description. If a bind plugin chooses to handle a description, the
+	// remaining bind plugins are skipped. When a bind plugin does not handle a description,
+	// it must return Skip in its Status code. If a bind plugin returns an Error, the
+	// description is rejected and will not be bound.
+	Bind(ctx context.Context, desc *appsapi.Description, rbs []*appsapi.ResourceBinding, clusters []*clusterapi.ManagedCluster) *Status
+}
+
+// PostBindPlugin is an interface that must be implemented by "PostBind" plugins.
+// These plugins are called after a description is successfully bound.
+type PostBindPlugin interface {
+	Plugin
+	// PostBind is called after a description is successfully bound. These plugins are
+	// informational. A common application of this extension point is for cleaning
+	// up. If a plugin needs to clean-up its state after a description is scheduled and
+	// bound, PostBind is the extension point that it should register.
+	PostBind(ctx context.Context, desc *appsapi.Description, rbs []*appsapi.ResourceBinding, clusters []*clusterapi.ManagedCluster)
+}
+
 // Framework manages the set of plugins in use by the scheduling framework.
 // Configured plugins are called at specified points in a scheduling context.
 type Framework interface {
@@ -211,7 +280,45 @@ type Framework interface {
 	// PostFilter plugins can either be informational, in which case should be configured
 	// to execute first and return Unschedulable status, or ones that try to change the
 	// cluster state to make the subscription potentially schedulable in a future scheduling cycle.
-	RunPostFilterPlugins(ctx context.Context, sub *appsapi.Component, filteredClusterStatusMap ClusterToStatusMap) (*PostFilterResult, *Status)
+	RunPostFilterPlugins(ctx context.Context, desc *appsapi.Description, filteredClusterStatusMap ClusterToStatusMap) (*PostFilterResult, *Status)
+
+	// RunReservePluginsReserve runs the Reserve method of the set of
+	// configured Reserve plugins. If any of these calls returns an error, it
+	// does not continue running the remaining ones and returns the error. In
+	// such case, description will not be scheduled.
+	RunReservePluginsReserve(ctx context.Context, desc *appsapi.Description, rbs []*appsapi.ResourceBinding) *Status
+
+	// RunReservePluginsUnreserve runs the Unreserve method of the set of
+	// configured Reserve plugins.
+	RunReservePluginsUnreserve(ctx context.Context, desc *appsapi.Description, rbs []*appsapi.ResourceBinding)
+
+	// RunPermitPlugins runs the set of configured Permit plugins. If any of these
+	// plugins returns a status other than "Success" or "Wait", it does not continue
+	// running the remaining plugins and returns an error. Otherwise, if any of the
+	// plugins returns "Wait", then this function will create and add waiting description
+	// to a map of currently waiting descriptions and return status with "Wait" code.
+	// Description will remain waiting description for the minimum duration returned by the Permit plugins.
+	RunPermitPlugins(ctx context.Context, desc *appsapi.Description, rbs []*appsapi.ResourceBinding) *Status
+
+	// WaitOnPermit will block, if the description is a waiting description, until the waiting description is rejected or allowed.
+	WaitOnPermit(ctx context.Context, desc *appsapi.Description) *Status
+
+	// RunPreBindPlugins runs the set of configured PreBind plugins. It returns
+	// *Status and its code is set to non-success if any of the plugins returns
+	// anything but Success. If the Status code is "Unschedulable", it is
+	// considered as a scheduling check failure, otherwise, it is considered as an
+	// internal error. In either case the description is not going to be bound.
+	RunPreBindPlugins(ctx context.Context, desc *appsapi.Description, rbs []*appsapi.ResourceBinding) *Status
+
+	// RunBindPlugins runs the set of configured Bind plugins. A Bind plugin may choose
+	// whether or not to handle the given description. If a Bind plugin chooses to skip the
+	// binding, it should return code=5("skip") status. Otherwise, it should return "Error"
+	// or "Success". If none of the plugins handled binding, RunBindPlugins returns
+	// code=5("skip") status.
+	RunBindPlugins(ctx context.Context, desc *appsapi.Description, rbs []*appsapi.ResourceBinding, clusters []*clusterapi.ManagedCluster) *Status
+
+	// RunPostBindPlugins runs the set of configured PostBind plugins.
+	RunPostBindPlugins(ctx context.Context, desc *appsapi.Description, rbs []*appsapi.ResourceBinding, clusters []*clusterapi.ManagedCluster)
 
 	// HasFilterPlugins returns true if at least one Filter plugin is defined.
 	HasFilterPlugins() bool
@@ -249,6 +356,16 @@ type Handle interface {
 
 	// Parallelizer returns a parallelizer holding parallelism for scheduler.
 	Parallelizer() parallelize.Parallelizer
+
+	// IterateOverWaitingDescriptions acquires a read lock and iterates over the WaitingDescriptions map.
+	IterateOverWaitingDescriptions(callback func(WaitingSubscription))
+
+	// GetWaitingDescription returns a waiting description given its UID.
+	GetWaitingDescription(uid types.UID) WaitingSubscription
+
+	// RejectWaitingDescription rejects a waiting description given its UID.
+	// The return value indicates if the description is waiting or not.
+	RejectWaitingDescription(uid types.UID) bool
 }
 
 // PostFilterResult wraps needed info for scheduler framework to act upon PostFilter phase.
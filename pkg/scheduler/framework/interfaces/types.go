@@ -1,6 +1,7 @@
 package interfaces
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -48,3 +49,20 @@ func (f *FitError) Error() string {
 	reasonMsg := fmt.Sprintf(NoClusterAvailableMsg+": %v.", f.NumAllClusters, strings.Join(sortReasonsHistogram(), ", "))
 	return reasonMsg
 }
+
+type descriptionKey struct{}
+
+// WithDescription returns a copy of ctx that carries desc, the description being scheduled. Filter and score
+// plugins only get a component or a resource binding, they look up the rules of the whole description in ctx.
+func WithDescription(ctx context.Context, desc *appsapi.Description) context.Context {
+	return context.WithValue(ctx, descriptionKey{}, desc)
+}
+
+// DescriptionFrom returns the description being scheduled from ctx, or nil if ctx doesn't carry one.
+func DescriptionFrom(ctx context.Context) *appsapi.Description {
+	if ctx == nil {
+		return nil
+	}
+	desc, _ := ctx.Value(descriptionKey{}).(*appsapi.Description)
+	return desc
+}
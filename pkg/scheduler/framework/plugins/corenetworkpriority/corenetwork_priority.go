@@ -2,10 +2,12 @@ package corenetworkpriority
 
 import (
 	"context"
+	"fmt"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
 	clusterapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+	schedulerapis "github.com/lmxia/gaia/pkg/scheduler/apis"
 	framework "github.com/lmxia/gaia/pkg/scheduler/framework/interfaces"
 	"github.com/lmxia/gaia/pkg/scheduler/framework/plugins/helper"
 	"github.com/lmxia/gaia/pkg/scheduler/framework/plugins/names"
@@ -13,7 +15,8 @@ import (
 
 // TaintToleration is a plugin that checks if a subscription tolerates a cluster's taints.
 type CoreNetworkPriority struct {
-	handle framework.Handle
+	handle         framework.Handle
+	netEnvironment string
 }
 
 var _ framework.ScorePlugin = &CoreNetworkPriority{}
@@ -34,7 +37,7 @@ func (pl *CoreNetworkPriority) Score(ctx context.Context, rb *v1alpha1.ResourceB
 		clusterMap[cluster.Name] = cluster
 	}
 
-	return calculateScore(0, rb.Spec.RbApps, clusterMap), nil
+	return calculateScore(0, pl.netEnvironment, rb.Spec.RbApps, clusterMap), nil
 }
 
 // ScoreExtensions of the Score plugin.
@@ -43,6 +46,13 @@ func (pl *CoreNetworkPriority) ScoreExtensions() framework.ScoreExtensions {
 }
 
 // New initializes a new plugin and returns it.
-func New(_ runtime.Object, h framework.Handle) (framework.Plugin, error) {
-	return &CoreNetworkPriority{handle: h}, nil
+func New(obj runtime.Object, h framework.Handle) (framework.Plugin, error) {
+	args, ok := obj.(*schedulerapis.CorePriorityArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type CorePriorityArgs, got %T", obj)
+	}
+	if err := schedulerapis.ValidateCorePriorityArgs(args); err != nil {
+		return nil, err
+	}
+	return &CoreNetworkPriority{handle: h, netEnvironment: args.NetEnvironment}, nil
 }
@@ -97,7 +97,7 @@ func (suite *ScoreSuite) SetupTest() {
 }
 
 func (suite *ScoreSuite) TestCalculateScore() {
-	score := calculateScore(0, suite.rbApps, suite.clusters)
+	score := calculateScore(0, common.NetworkLocationCore, suite.rbApps, suite.clusters)
 	suite.Equal(score, 44, "well...")
 }
 
@@ -0,0 +1,197 @@
+package defaultbinder
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"sort"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	clusterapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+	"github.com/lmxia/gaia/pkg/common"
+	framework "github.com/lmxia/gaia/pkg/scheduler/framework/interfaces"
+	"github.com/lmxia/gaia/pkg/scheduler/framework/plugins/names"
+	"github.com/lmxia/gaia/pkg/utils"
+)
+
+// DefaultBinder binds descriptions to child clusters using the gaia client.
+type DefaultBinder struct {
+	handle framework.Handle
+}
+
+var _ framework.BindPlugin = &DefaultBinder{}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (b *DefaultBinder) Name() string {
+	return names.DefaultBinder
+}
+
+// Bind publishes the ResourceBindings and a copy of the description into the namespace of every child cluster.
+// Publishing again, after a retry or a change of the spec, updates what an earlier attempt published and deletes
+// the ResourceBindings that are no longer part of the plan.
+func (b *DefaultBinder) Bind(ctx context.Context, desc *v1alpha1.Description, rbs []*v1alpha1.ResourceBinding,
+	clusters []*clusterapi.ManagedCluster) *framework.Status {
+	klog.V(3).InfoS("Attempting to bind description to clusters", "description", klog.KObj(desc), "clusters", len(clusters))
+	var errs []error
+	for _, cluster := range clusters {
+		if err := b.publish(ctx, desc, rbs, cluster.Namespace); err != nil {
+			klog.V(3).InfoS("failed to publish description in child cluster namespace", "description", klog.KObj(desc),
+				"namespace", cluster.Namespace, "err", err)
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return framework.AsStatus(utilerrors.NewAggregate(errs))
+	}
+	return nil
+}
+
+// publish reconciles the ResourceBindings of desc and its copy in namespace with rbs, stamped with the generation
+// of desc and the plan of rbs. Nothing is touched if the copy in namespace comes from a newer generation, which a
+// later attempt has published already, or if the child cluster has scheduled the up-to-date copy of the same plan,
+// consuming its ResourceBindings.
+func (b *DefaultBinder) publish(ctx context.Context, desc *v1alpha1.Description, rbs []*v1alpha1.ResourceBinding,
+	namespace string) error {
+	client := b.handle.ClientSet().AppsV1alpha1()
+	current, err := client.Descriptions(namespace).Get(ctx, desc.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		current = nil
+	} else if err != nil {
+		return err
+	} else if publishedGeneration(current) > desc.Generation {
+		klog.V(3).InfoS("Skipping outdated publication", "description", klog.KObj(desc), "namespace", namespace,
+			"generation", desc.Generation, "publishedGeneration", publishedGeneration(current))
+		return nil
+	}
+
+	generation := strconv.FormatInt(desc.Generation, 10)
+	newDesc := utils.ConstructDescriptionFromExistOne(desc)
+	newDesc.Namespace = namespace
+	plan := planHash(rbs)
+	metav1.SetMetaDataAnnotation(&newDesc.ObjectMeta, common.DescriptionGenerationAnnotation, generation)
+	metav1.SetMetaDataAnnotation(&newDesc.ObjectMeta, common.DescriptionPlanAnnotation, plan)
+	upToDate := current != nil && current.Annotations[common.DescriptionGenerationAnnotation] == generation &&
+		current.Annotations[common.DescriptionPlanAnnotation] == plan &&
+		equality.Semantic.DeepEqual(current.Labels, newDesc.Labels) && equality.Semantic.DeepEqual(current.Spec, newDesc.Spec)
+	pending := current == nil || len(current.Status.Phase) == 0 || current.Status.Phase == v1alpha1.DescriptionPhasePending
+	if upToDate && !pending {
+		return nil
+	}
+
+	var errs []error
+	// 1. reconcile rbs in sub children cluster namespace.
+	existing, err := client.ResourceBindings(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{common.GaiaDescriptionLabel: desc.Name}).String(),
+	})
+	if err != nil {
+		return err
+	}
+	desired := make(map[string]*v1alpha1.ResourceBinding, len(rbs))
+	for _, rb := range rbs {
+		desired[rb.Name] = rb
+	}
+	published := sets.NewString()
+	for i := range existing.Items {
+		item := &existing.Items[i]
+		rb, ok := desired[item.Name]
+		if !ok {
+			klog.V(4).InfoS("Deleting stale resource binding", "resourceBinding", klog.KObj(item))
+			if err = client.ResourceBindings(namespace).Delete(ctx, item.Name, metav1.DeleteOptions{}); err != nil &&
+				!apierrors.IsNotFound(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		published.Insert(item.Name)
+		if item.Annotations[common.DescriptionGenerationAnnotation] == generation &&
+			equality.Semantic.DeepEqual(item.Labels, rb.Labels) && equality.Semantic.DeepEqual(item.Spec, rb.Spec) {
+			continue
+		}
+		item.Labels = rb.Labels
+		item.Spec = rb.Spec
+		metav1.SetMetaDataAnnotation(&item.ObjectMeta, common.DescriptionGenerationAnnotation, generation)
+		if _, err = client.ResourceBindings(namespace).Update(ctx, item, metav1.UpdateOptions{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, rb := range rbs {
+		if published.Has(rb.Name) {
+			continue
+		}
+		itemRb := rb.DeepCopy()
+		itemRb.Namespace = namespace
+		metav1.SetMetaDataAnnotation(&itemRb.ObjectMeta, common.DescriptionGenerationAnnotation, generation)
+		if _, err = client.ResourceBindings(namespace).Create(ctx, itemRb, metav1.CreateOptions{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+
+	// 2. reconcile desc in to child cluster namespace, the child cluster schedules it again if it changed.
+	if current == nil {
+		_, err = client.Descriptions(namespace).Create(ctx, newDesc, metav1.CreateOptions{})
+		return err
+	}
+	if upToDate {
+		return nil
+	}
+	current = current.DeepCopy()
+	current.Labels = newDesc.Labels
+	current.Spec = newDesc.Spec
+	metav1.SetMetaDataAnnotation(&current.ObjectMeta, common.DescriptionGenerationAnnotation, generation)
+	metav1.SetMetaDataAnnotation(&current.ObjectMeta, common.DescriptionPlanAnnotation, plan)
+	if current, err = client.Descriptions(namespace).Update(ctx, current, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	if pending {
+		return nil
+	}
+	current.Status.Phase = v1alpha1.DescriptionPhasePending
+	_, err = client.Descriptions(namespace).UpdateStatus(ctx, current, metav1.UpdateOptions{})
+	return err
+}
+
+// publishedGeneration returns the generation of the description obj was published from, 0 if it is unknown.
+func publishedGeneration(obj metav1.Object) int64 {
+	generation, err := strconv.ParseInt(obj.GetAnnotations()[common.DescriptionGenerationAnnotation], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return generation
+}
+
+// planHash returns a hash of the names and specs of rbs, in whatever order they come.
+func planHash(rbs []*v1alpha1.ResourceBinding) string {
+	sorted := make([]*v1alpha1.ResourceBinding, len(rbs))
+	copy(sorted, rbs)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	h := fnv.New32a()
+	for _, rb := range sorted {
+		spec, err := json.Marshal(rb.Spec)
+		if err != nil {
+			klog.V(4).InfoS("Failed to hash resource binding", "resourceBinding", klog.KObj(rb), "err", err)
+		}
+		_, _ = h.Write([]byte(rb.Name))
+		_, _ = h.Write(spec)
+	}
+	return strconv.FormatUint(uint64(h.Sum32()), 16)
+}
+
+// New creates a DefaultBinder.
+func New(_ runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	return &DefaultBinder{handle: handle}, nil
+}
@@ -0,0 +1,152 @@
+package defaultbinder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	clusterapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+	"github.com/lmxia/gaia/pkg/common"
+	"github.com/lmxia/gaia/pkg/generated/clientset/versioned/fake"
+	schedulerapis "github.com/lmxia/gaia/pkg/scheduler/apis"
+	framework "github.com/lmxia/gaia/pkg/scheduler/framework/interfaces"
+	"github.com/lmxia/gaia/pkg/scheduler/framework/plugins/names"
+	frameworkruntime "github.com/lmxia/gaia/pkg/scheduler/framework/runtime"
+)
+
+const childNamespace = "gaia-cluster1"
+
+type BinderSuite struct {
+	client   *fake.Clientset
+	fwk      framework.Framework
+	clusters []*clusterapi.ManagedCluster
+	desc     *v1alpha1.Description
+	suite.Suite
+}
+
+func (suite *BinderSuite) SetupTest() {
+	suite.client = fake.NewSimpleClientset()
+	suite.clusters = []*clusterapi.ManagedCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Namespace: childNamespace}},
+	}
+	suite.desc = &v1alpha1.Description{
+		ObjectMeta: metav1.ObjectMeta{Name: "desc", Namespace: common.GaiaReservedNamespace, Generation: 1},
+		Spec:       v1alpha1.DescriptionSpec{AppID: "app"},
+	}
+
+	profile := &schedulerapis.SchedulerProfile{
+		SchedulerName: schedulerapis.DefaultSchedulerName,
+		Plugins: &schedulerapis.Plugins{
+			Bind: schedulerapis.PluginSet{Enabled: []schedulerapis.Plugin{{Name: names.DefaultBinder}}},
+		},
+	}
+	fwk, err := frameworkruntime.NewFramework(frameworkruntime.Registry{names.DefaultBinder: New}, profile,
+		frameworkruntime.WithClientSet(suite.client))
+	suite.NoError(err)
+	suite.fwk = fwk
+}
+
+func (suite *BinderSuite) newRB(index string, replicas int32) *v1alpha1.ResourceBinding {
+	return &v1alpha1.ResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   suite.desc.Name + "-rs-" + index,
+			Labels: map[string]string{common.GaiaDescriptionLabel: suite.desc.Name},
+		},
+		Spec: v1alpha1.ResourceBindingSpec{
+			AppID:  suite.desc.Name,
+			RbApps: []*v1alpha1.ResourceBindingApps{{ClusterName: "cluster1", Replicas: map[string]int32{"c": replicas}}},
+		},
+	}
+}
+
+func (suite *BinderSuite) bind(rbs ...*v1alpha1.ResourceBinding) {
+	sts := suite.fwk.RunBindPlugins(context.TODO(), suite.desc, rbs, suite.clusters)
+	suite.True(sts.IsSuccess(), sts.Message())
+}
+
+func (suite *BinderSuite) published() map[string]*v1alpha1.ResourceBinding {
+	list, err := suite.client.AppsV1alpha1().ResourceBindings(childNamespace).List(context.TODO(), metav1.ListOptions{})
+	suite.NoError(err)
+	rbs := make(map[string]*v1alpha1.ResourceBinding)
+	for i := range list.Items {
+		rbs[list.Items[i].Name] = &list.Items[i]
+	}
+	return rbs
+}
+
+func (suite *BinderSuite) copy() *v1alpha1.Description {
+	desc, err := suite.client.AppsV1alpha1().Descriptions(childNamespace).Get(context.TODO(), suite.desc.Name, metav1.GetOptions{})
+	suite.NoError(err)
+	return desc
+}
+
+func (suite *BinderSuite) TestRepublishSpecChange() {
+	suite.bind(suite.newRB("0", 1), suite.newRB("1", 2))
+	suite.bind(suite.newRB("0", 1), suite.newRB("1", 2))
+	suite.Len(suite.published(), 2, "a retry is idempotent")
+	suite.Equal("1", suite.copy().Annotations[common.DescriptionGenerationAnnotation])
+
+	suite.desc.Generation = 2
+	suite.desc.Spec.AppID = "app2"
+	suite.bind(suite.newRB("0", 3))
+	rbs := suite.published()
+	suite.Len(rbs, 1, "the resource bindings of the previous plan are deleted")
+	suite.Equal(int32(3), rbs["desc-rs-0"].Spec.RbApps[0].Replicas["c"])
+	suite.Equal("2", rbs["desc-rs-0"].Annotations[common.DescriptionGenerationAnnotation])
+	suite.Equal("app2", suite.copy().Spec.AppID)
+	suite.Equal("2", suite.copy().Annotations[common.DescriptionGenerationAnnotation])
+}
+
+func (suite *BinderSuite) TestRepublishScheduledCopy() {
+	suite.bind(suite.newRB("0", 1))
+	child := suite.copy()
+	child.Status.Phase = v1alpha1.DescriptionPhaseScheduled
+	_, err := suite.client.AppsV1alpha1().Descriptions(childNamespace).UpdateStatus(context.TODO(), child, metav1.UpdateOptions{})
+	suite.NoError(err)
+	// the child cluster consumes the resource bindings once it has scheduled the copy.
+	suite.NoError(suite.client.AppsV1alpha1().ResourceBindings(childNamespace).Delete(context.TODO(), "desc-rs-0", metav1.DeleteOptions{}))
+
+	suite.bind(suite.newRB("0", 1))
+	suite.Empty(suite.published(), "the scheduled copy is up to date")
+
+	suite.desc.Generation = 2
+	suite.bind(suite.newRB("0", 1))
+	suite.Len(suite.published(), 1)
+	suite.Equal(v1alpha1.DescriptionPhasePending, suite.copy().Status.Phase, "the child cluster schedules the new generation")
+}
+
+func (suite *BinderSuite) TestRepublishPlanChange() {
+	suite.bind(suite.newRB("0", 1))
+	child := suite.copy()
+	child.Status.Phase = v1alpha1.DescriptionPhaseScheduled
+	_, err := suite.client.AppsV1alpha1().Descriptions(childNamespace).UpdateStatus(context.TODO(), child, metav1.UpdateOptions{})
+	suite.NoError(err)
+	suite.NoError(suite.client.AppsV1alpha1().ResourceBindings(childNamespace).Delete(context.TODO(), "desc-rs-0", metav1.DeleteOptions{}))
+
+	// a re-plan, like a rebalance or a preemption, keeps the generation of the description.
+	suite.bind(suite.newRB("0", 2))
+	rbs := suite.published()
+	suite.Len(rbs, 1)
+	suite.Equal(int32(2), rbs["desc-rs-0"].Spec.RbApps[0].Replicas["c"])
+	suite.Equal("1", suite.copy().Annotations[common.DescriptionGenerationAnnotation])
+	suite.Equal(v1alpha1.DescriptionPhasePending, suite.copy().Status.Phase, "the child cluster schedules the new plan")
+}
+
+func (suite *BinderSuite) TestSkipOutdatedGeneration() {
+	suite.desc.Generation = 3
+	suite.bind(suite.newRB("0", 1))
+
+	suite.desc.Generation = 2
+	suite.bind(suite.newRB("0", 5), suite.newRB("1", 5))
+	rbs := suite.published()
+	suite.Len(rbs, 1)
+	suite.Equal(int32(1), rbs["desc-rs-0"].Spec.RbApps[0].Replicas["c"])
+	suite.Equal("3", suite.copy().Annotations[common.DescriptionGenerationAnnotation])
+}
+
+func TestBinderSuite(t *testing.T) {
+	suite.Run(t, new(BinderSuite))
+}
@@ -0,0 +1,288 @@
+package defaultpreemption
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	clusterapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+	"github.com/lmxia/gaia/pkg/common"
+	appslisters "github.com/lmxia/gaia/pkg/generated/listers/apps/v1alpha1"
+	platformlisters "github.com/lmxia/gaia/pkg/generated/listers/platform/v1alpha1"
+	"github.com/lmxia/gaia/pkg/scheduler/algorithm"
+	framework "github.com/lmxia/gaia/pkg/scheduler/framework/interfaces"
+	"github.com/lmxia/gaia/pkg/scheduler/framework/plugins/names"
+)
+
+// ReasonPreempted is the reason recorded on descriptions evicted by a higher priority one.
+const ReasonPreempted = "Preempted"
+
+// DefaultPreemption is a PostFilter plugin that evicts the replicas of scheduled descriptions of lower priority
+// from clusters, so that an unschedulable description of higher priority can win their capacity.
+type DefaultPreemption struct {
+	handle        framework.Handle
+	descLister    appslisters.DescriptionLister
+	rbLister      appslisters.ResourceBindingLister
+	clusterLister platformlisters.ManagedClusterLister
+}
+
+var _ framework.PostFilterPlugin = &DefaultPreemption{}
+
+// victim is a lower priority description together with its selected ResourceBinding, the replicas it holds on
+// every candidate cluster and the resources they request.
+type victim struct {
+	desc     *v1alpha1.Description
+	rb       *v1alpha1.ResourceBinding
+	replicas map[string]int64
+	total    int64
+	freed    corev1.ResourceList
+}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (pl *DefaultPreemption) Name() string {
+	return names.DefaultPreemption
+}
+
+// PostFilter selects the lowest priority descriptions placed on candidate clusters until they free the
+// resources the preemptor lacks, evicts their replicas from those clusters and nominates the clusters.
+func (pl *DefaultPreemption) PostFilter(ctx context.Context, desc *v1alpha1.Description,
+	m framework.ClusterToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	candidates, err := pl.candidateClusters(ctx, desc, m)
+	if err != nil {
+		return nil, framework.AsStatus(err)
+	}
+	if len(candidates) == 0 {
+		return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, "no cluster is a preemption candidate")
+	}
+
+	victims, err := pl.selectVictims(desc, candidates)
+	if err != nil {
+		return nil, framework.AsStatus(err)
+	}
+	if len(victims) == 0 {
+		return nil, framework.NewStatus(framework.Unschedulable, "no lower priority description to preempt on candidate clusters")
+	}
+
+	nominated := sets.NewString()
+	for _, v := range victims {
+		if err := pl.preempt(ctx, desc, v, candidates); err != nil {
+			return nil, framework.AsStatus(err)
+		}
+		for key := range v.replicas {
+			nominated.Insert(key)
+		}
+	}
+	return &framework.PostFilterResult{NominatedNamespacedClusters: nominated.List()}, framework.NewStatus(framework.Success)
+}
+
+// candidateClusters returns the clusters, indexed by namespaced key, that pass the filters of at least
+// one component and were not reported as unresolvable.
+func (pl *DefaultPreemption) candidateClusters(ctx context.Context, desc *v1alpha1.Description,
+	m framework.ClusterToStatusMap) (map[string]*clusterapi.ManagedCluster, error) {
+	clusters, err := pl.clusterLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	candidates := make(map[string]*clusterapi.ManagedCluster)
+	for _, cluster := range clusters {
+		key := klog.KObj(cluster).String()
+		if m[key].Code() == framework.UnschedulableAndUnresolvable {
+			continue
+		}
+		for i := range desc.Spec.Components {
+			if statuses := pl.handle.RunFilterPlugins(ctx, &desc.Spec.Components[i], cluster); statuses.Merge().IsSuccess() {
+				candidates[key] = cluster
+				break
+			}
+		}
+	}
+	return candidates, nil
+}
+
+// selectVictims picks scheduled descriptions with a lower priority, lowest priority and newest first,
+// until they free the resources the preemptor lacks on the candidate clusters.
+func (pl *DefaultPreemption) selectVictims(desc *v1alpha1.Description,
+	candidates map[string]*clusterapi.ManagedCluster) ([]*victim, error) {
+	descs, err := pl.descLister.Descriptions(common.GaiaReservedNamespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	potential := make([]*victim, 0)
+	for _, item := range descs {
+		if item.UID == desc.UID || item.DeletionTimestamp != nil ||
+			item.Status.Phase != v1alpha1.DescriptionPhaseScheduled || item.Spec.Priority >= desc.Spec.Priority {
+			continue
+		}
+		v, err := pl.placement(item, candidates)
+		if err != nil {
+			return nil, err
+		}
+		if v.total > 0 {
+			potential = append(potential, v)
+		}
+	}
+	sort.SliceStable(potential, func(i, j int) bool {
+		if potential[i].desc.Spec.Priority != potential[j].desc.Spec.Priority {
+			return potential[i].desc.Spec.Priority < potential[j].desc.Spec.Priority
+		}
+		return potential[j].desc.CreationTimestamp.Before(&potential[i].desc.CreationTimestamp)
+	})
+
+	needed := shortfall(desc, candidates)
+	victims := make([]*victim, 0)
+	freed := make(corev1.ResourceList)
+	for _, v := range potential {
+		// the preemptor is unschedulable, so at least one victim is preempted.
+		if len(victims) > 0 && covers(freed, needed) {
+			break
+		}
+		victims = append(victims, v)
+		addResources(freed, v.freed, 1)
+	}
+	return victims, nil
+}
+
+// placement sums the replicas the selected ResourceBinding of desc places on each candidate cluster, and the
+// resources they request.
+func (pl *DefaultPreemption) placement(desc *v1alpha1.Description,
+	candidates map[string]*clusterapi.ManagedCluster) (*victim, error) {
+	rbs, err := pl.rbLister.ResourceBindings(common.GaiaRBMergedReservedNamespace).List(
+		labels.SelectorFromSet(labels.Set{common.GaiaDescriptionLabel: desc.Name}))
+	if err != nil {
+		return nil, err
+	}
+	requestsOfComponents := algorithm.ComponentRequests(desc)
+	v := &victim{desc: desc, replicas: make(map[string]int64), freed: make(corev1.ResourceList)}
+	for _, rb := range rbs {
+		if rb.Spec.StatusScheduler != v1alpha1.ResourceBindingSelected {
+			continue
+		}
+		v.rb = rb
+		for _, app := range rb.Spec.RbApps {
+			for key, cluster := range candidates {
+				if cluster.Name != app.ClusterName {
+					continue
+				}
+				for name, replicas := range app.Replicas {
+					v.replicas[key] += int64(replicas)
+					v.total += int64(replicas)
+					addResources(v.freed, requestsOfComponents[name], int64(replicas))
+				}
+			}
+		}
+	}
+	for key, replicas := range v.replicas {
+		if replicas == 0 {
+			delete(v.replicas, key)
+		}
+	}
+	return v, nil
+}
+
+// preempt evicts the replicas of v from the candidate clusters in its selected ResourceBinding, the child
+// clusters scale them down and free their capacity for the preemptor. The victim keeps its other replicas.
+func (pl *DefaultPreemption) preempt(ctx context.Context, preemptor *v1alpha1.Description, v *victim,
+	candidates map[string]*clusterapi.ManagedCluster) error {
+	clusters := sets.NewString()
+	for key := range v.replicas {
+		clusters.Insert(candidates[key].Name)
+	}
+	rb := v.rb.DeepCopy()
+	for _, app := range rb.Spec.RbApps {
+		if app != nil && clusters.Has(app.ClusterName) {
+			evictReplicas(app)
+		}
+	}
+	if _, err := pl.handle.ClientSet().AppsV1alpha1().ResourceBindings(rb.Namespace).Update(ctx, rb, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("evicting preempted description %s from %v: %v", klog.KObj(v.desc), clusters.List(), err)
+	}
+	if pl.handle.EventRecorder() != nil {
+		pl.handle.EventRecorder().Eventf(v.desc, corev1.EventTypeNormal, ReasonPreempted,
+			"Preempted by %v on clusters %v", klog.KObj(preemptor), clusters.List())
+	}
+	klog.V(3).InfoS("Preempted description", "preemptor", klog.KObj(preemptor), "victim", klog.KObj(v.desc), "clusters", clusters.List())
+	return nil
+}
+
+// evictReplicas removes all replicas from app and its children.
+func evictReplicas(app *v1alpha1.ResourceBindingApps) {
+	for name := range app.Replicas {
+		app.Replicas[name] = 0
+	}
+	for _, child := range app.Children {
+		if child != nil {
+			evictReplicas(child)
+		}
+	}
+}
+
+// shortfall returns the resources desc requests beyond what the candidate cluster with the most of each
+// resource has available. A deployment component requests its replicas, any other component one replica.
+func shortfall(desc *v1alpha1.Description, candidates map[string]*clusterapi.ManagedCluster) corev1.ResourceList {
+	requestsOfComponents := algorithm.ComponentRequests(desc)
+	result := make(corev1.ResourceList)
+	for _, com := range desc.Spec.Components {
+		replicas := int64(1)
+		if com.Workload.TraitDeployment != nil && com.Workload.TraitDeployment.Replicas > 0 {
+			replicas = int64(com.Workload.TraitDeployment.Replicas)
+		}
+		addResources(result, requestsOfComponents[com.Name], replicas)
+	}
+	for name, quantity := range result {
+		most := resource.Quantity{}
+		for _, cluster := range candidates {
+			if available, ok := cluster.Status.Available[name]; ok && available.Cmp(most) > 0 {
+				most = available
+			}
+		}
+		quantity.Sub(most)
+		if quantity.Sign() <= 0 {
+			delete(result, name)
+		} else {
+			result[name] = quantity
+		}
+	}
+	return result
+}
+
+// addResources adds replicas times requests to list.
+func addResources(list, requests corev1.ResourceList, replicas int64) {
+	for name, quantity := range requests {
+		value := list[name]
+		value.Add(*resource.NewMilliQuantity(quantity.MilliValue()*replicas, quantity.Format))
+		list[name] = value
+	}
+}
+
+// covers returns true if freed holds at least every resource of needed.
+func covers(freed, needed corev1.ResourceList) bool {
+	for name, quantity := range needed {
+		if value, ok := freed[name]; !ok || value.Cmp(quantity) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// New initializes a new plugin and returns it.
+func New(_ runtime.Object, h framework.Handle) (framework.Plugin, error) {
+	if h.SharedInformerFactory() == nil {
+		return nil, fmt.Errorf("plugin %s requires a shared informer factory", names.DefaultPreemption)
+	}
+	return &DefaultPreemption{
+		handle:        h,
+		descLister:    h.SharedInformerFactory().Apps().V1alpha1().Descriptions().Lister(),
+		rbLister:      h.SharedInformerFactory().Apps().V1alpha1().ResourceBindings().Lister(),
+		clusterLister: h.SharedInformerFactory().Platform().V1alpha1().ManagedClusters().Lister(),
+	}, nil
+}
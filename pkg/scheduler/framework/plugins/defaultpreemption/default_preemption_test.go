@@ -0,0 +1,237 @@
+package defaultpreemption
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	clusterapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+	"github.com/lmxia/gaia/pkg/common"
+	"github.com/lmxia/gaia/pkg/generated/clientset/versioned/fake"
+	informers "github.com/lmxia/gaia/pkg/generated/informers/externalversions"
+	schedulerapis "github.com/lmxia/gaia/pkg/scheduler/apis"
+	framework "github.com/lmxia/gaia/pkg/scheduler/framework/interfaces"
+	"github.com/lmxia/gaia/pkg/scheduler/framework/plugins/names"
+	frameworkruntime "github.com/lmxia/gaia/pkg/scheduler/framework/runtime"
+)
+
+type PreemptionSuite struct {
+	client  *fake.Clientset
+	factory informers.SharedInformerFactory
+	fwk     framework.Framework
+	suite.Suite
+}
+
+func newDescription(name string, priority int32, phase v1alpha1.DescriptionPhase, replicas int32) *v1alpha1.Description {
+	return &v1alpha1.Description{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: common.GaiaReservedNamespace, UID: types.UID("uid-" + name)},
+		Spec: v1alpha1.DescriptionSpec{
+			Priority: priority,
+			Components: []v1alpha1.Component{
+				{
+					Name: name,
+					Workload: v1alpha1.Workload{
+						Workloadtype:    v1alpha1.WorkloadTypeDeployment,
+						TraitDeployment: &v1alpha1.TraitDeployment{Replicas: replicas},
+					},
+					// a replica without requests requests 100m cpu and 200Mi memory.
+					Module: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: name}}}},
+				},
+			},
+		},
+		Status: v1alpha1.DescriptionStatus{Phase: phase},
+	}
+}
+
+func newMergedRB(desc string, replicas map[string]int32) *v1alpha1.ResourceBinding {
+	rb := &v1alpha1.ResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      desc + "-rs-0",
+			Namespace: common.GaiaRBMergedReservedNamespace,
+			Labels:    map[string]string{common.GaiaDescriptionLabel: desc},
+		},
+		Spec: v1alpha1.ResourceBindingSpec{StatusScheduler: v1alpha1.ResourceBindingSelected},
+	}
+	for cluster, num := range replicas {
+		rb.Spec.RbApps = append(rb.Spec.RbApps, &v1alpha1.ResourceBindingApps{
+			ClusterName: cluster,
+			Replicas:    map[string]int32{desc: num},
+		})
+	}
+	return rb
+}
+
+func (suite *PreemptionSuite) SetupTest() {
+	clusters := []*clusterapi.ManagedCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Namespace: "gaia-cluster1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster2", Namespace: "gaia-cluster2"}},
+	}
+	descs := []*v1alpha1.Description{
+		newDescription("low", 1, v1alpha1.DescriptionPhaseScheduled, 2),
+		newDescription("mid", 5, v1alpha1.DescriptionPhaseScheduled, 2),
+		newDescription("high", 20, v1alpha1.DescriptionPhaseScheduled, 5),
+		newDescription("pending", 0, v1alpha1.DescriptionPhasePending, 5),
+	}
+	rbs := []*v1alpha1.ResourceBinding{
+		newMergedRB("low", map[string]int32{"cluster1": 2}),
+		newMergedRB("mid", map[string]int32{"cluster2": 2}),
+		newMergedRB("high", map[string]int32{"cluster1": 5}),
+	}
+
+	objects := make([]runtime.Object, 0)
+	for _, rb := range rbs {
+		objects = append(objects, rb)
+	}
+	suite.client = fake.NewSimpleClientset(objects...)
+	suite.factory = informers.NewSharedInformerFactory(suite.client, 0)
+	for _, cluster := range clusters {
+		suite.NoError(suite.factory.Platform().V1alpha1().ManagedClusters().Informer().GetIndexer().Add(cluster))
+	}
+	for _, desc := range descs {
+		suite.NoError(suite.factory.Apps().V1alpha1().Descriptions().Informer().GetIndexer().Add(desc))
+	}
+	for _, rb := range rbs {
+		suite.NoError(suite.factory.Apps().V1alpha1().ResourceBindings().Informer().GetIndexer().Add(rb))
+	}
+
+	profile := &schedulerapis.SchedulerProfile{
+		SchedulerName: schedulerapis.DefaultSchedulerName,
+		Plugins: &schedulerapis.Plugins{
+			PostFilter: schedulerapis.PluginSet{Enabled: []schedulerapis.Plugin{{Name: names.DefaultPreemption}}},
+		},
+	}
+	fwk, err := frameworkruntime.NewFramework(frameworkruntime.Registry{names.DefaultPreemption: New}, profile,
+		frameworkruntime.WithClientSet(suite.client), frameworkruntime.WithInformerFactory(suite.factory))
+	suite.NoError(err)
+	suite.fwk = fwk
+}
+
+// replicas returns the replicas the selected ResourceBinding of desc places on cluster.
+func (suite *PreemptionSuite) replicas(desc, cluster string) int32 {
+	rb, err := suite.client.AppsV1alpha1().ResourceBindings(common.GaiaRBMergedReservedNamespace).Get(context.TODO(),
+		desc+"-rs-0", metav1.GetOptions{})
+	suite.NoError(err)
+	for _, app := range rb.Spec.RbApps {
+		if app.ClusterName == cluster {
+			return app.Replicas[desc]
+		}
+	}
+	return 0
+}
+
+func (suite *PreemptionSuite) TestPreemptLowestPriorityFirst() {
+	preemptor := newDescription("preemptor", 10, v1alpha1.DescriptionPhasePending, 3)
+	result, sts := suite.fwk.RunPostFilterPlugins(context.TODO(), preemptor, framework.ClusterToStatusMap{})
+	suite.True(sts.IsSuccess(), sts.Message())
+	suite.Equal([]string{"gaia-cluster1/cluster1", "gaia-cluster2/cluster2"}, result.NominatedNamespacedClusters)
+
+	suite.Zero(suite.replicas("low", "cluster1"))
+	suite.Zero(suite.replicas("mid", "cluster2"))
+	suite.Equal(int32(5), suite.replicas("high", "cluster1"), "high has a higher priority")
+}
+
+func (suite *PreemptionSuite) TestPreemptOnlyWhatIsNeeded() {
+	preemptor := newDescription("preemptor", 10, v1alpha1.DescriptionPhasePending, 1)
+	result, sts := suite.fwk.RunPostFilterPlugins(context.TODO(), preemptor, framework.ClusterToStatusMap{})
+	suite.True(sts.IsSuccess(), sts.Message())
+	suite.Equal([]string{"gaia-cluster1/cluster1"}, result.NominatedNamespacedClusters)
+	suite.Zero(suite.replicas("low", "cluster1"))
+	suite.Equal(int32(2), suite.replicas("mid", "cluster2"))
+}
+
+func (suite *PreemptionSuite) TestNoLowerPriority() {
+	preemptor := newDescription("preemptor", 1, v1alpha1.DescriptionPhasePending, 1)
+	_, sts := suite.fwk.RunPostFilterPlugins(context.TODO(), preemptor, framework.ClusterToStatusMap{})
+	suite.Equal(framework.Unschedulable, sts.Code())
+}
+
+func (suite *PreemptionSuite) TestUnresolvableClusters() {
+	preemptor := newDescription("preemptor", 10, v1alpha1.DescriptionPhasePending, 1)
+	unresolvable := framework.NewStatus(framework.UnschedulableAndUnresolvable, "no geolocation")
+	result, sts := suite.fwk.RunPostFilterPlugins(context.TODO(), preemptor, framework.ClusterToStatusMap{
+		"gaia-cluster1/cluster1": unresolvable,
+	})
+	suite.True(sts.IsSuccess(), sts.Message())
+	suite.Equal([]string{"gaia-cluster2/cluster2"}, result.NominatedNamespacedClusters)
+	suite.Equal(int32(2), suite.replicas("low", "cluster1"))
+}
+
+func (suite *PreemptionSuite) TestOnlySelectedResourceBinding() {
+	// an alternative plan of mid that was not selected holds nothing on cluster1.
+	rb := newMergedRB("mid", map[string]int32{"cluster1": 10})
+	rb.Name = "mid-rs-1"
+	rb.Spec.StatusScheduler = ""
+	suite.NoError(suite.factory.Apps().V1alpha1().ResourceBindings().Informer().GetIndexer().Add(rb))
+
+	preemptor := newDescription("preemptor", 10, v1alpha1.DescriptionPhasePending, 1)
+	unresolvable := framework.NewStatus(framework.UnschedulableAndUnresolvable, "no geolocation")
+	result, sts := suite.fwk.RunPostFilterPlugins(context.TODO(), preemptor, framework.ClusterToStatusMap{
+		"gaia-cluster2/cluster2": unresolvable,
+	})
+	suite.True(sts.IsSuccess(), sts.Message())
+	suite.Equal([]string{"gaia-cluster1/cluster1"}, result.NominatedNamespacedClusters)
+	suite.Equal(int32(2), suite.replicas("mid", "cluster2"))
+}
+
+func (suite *PreemptionSuite) TestFreeRequestedResources() {
+	// one replica of the preemptor requests as much cpu as the four replicas of low and mid.
+	preemptor := newDescription("preemptor", 10, v1alpha1.DescriptionPhasePending, 1)
+	preemptor.Spec.Components[0].Module.Spec.Containers = []corev1.Container{{
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("400m")},
+		},
+	}}
+	result, sts := suite.fwk.RunPostFilterPlugins(context.TODO(), preemptor, framework.ClusterToStatusMap{})
+	suite.True(sts.IsSuccess(), sts.Message())
+	suite.Equal([]string{"gaia-cluster1/cluster1", "gaia-cluster2/cluster2"}, result.NominatedNamespacedClusters,
+		"low frees 200m only")
+	suite.Zero(suite.replicas("mid", "cluster2"))
+}
+
+func (suite *PreemptionSuite) TestFreeCapacity() {
+	// cluster1 runs the replicas of low in its own child clusters.
+	rb := newMergedRB("low", map[string]int32{"cluster1": 2, "cluster2": 1})
+	for _, app := range rb.Spec.RbApps {
+		if app.ClusterName == "cluster1" {
+			app.Children = []*v1alpha1.ResourceBindingApps{
+				{ClusterName: "leaf1", Replicas: map[string]int32{"low": 1}},
+				{ClusterName: "leaf2", Replicas: map[string]int32{"low": 1}},
+			}
+		}
+	}
+	_, err := suite.client.AppsV1alpha1().ResourceBindings(rb.Namespace).Update(context.TODO(), rb, metav1.UpdateOptions{})
+	suite.NoError(err)
+	suite.NoError(suite.factory.Apps().V1alpha1().ResourceBindings().Informer().GetIndexer().Update(rb))
+
+	preemptor := newDescription("preemptor", 10, v1alpha1.DescriptionPhasePending, 1)
+	unresolvable := framework.NewStatus(framework.UnschedulableAndUnresolvable, "no geolocation")
+	result, sts := suite.fwk.RunPostFilterPlugins(context.TODO(), preemptor, framework.ClusterToStatusMap{
+		"gaia-cluster2/cluster2": unresolvable,
+	})
+	suite.True(sts.IsSuccess(), sts.Message())
+	suite.Equal([]string{"gaia-cluster1/cluster1"}, result.NominatedNamespacedClusters)
+
+	evicted, err := suite.client.AppsV1alpha1().ResourceBindings(rb.Namespace).Get(context.TODO(), rb.Name, metav1.GetOptions{})
+	suite.NoError(err)
+	for _, app := range evicted.Spec.RbApps {
+		if app.ClusterName == "cluster2" {
+			suite.Equal(int32(1), app.Replicas["low"], "replicas on other clusters are kept")
+			continue
+		}
+		suite.Zero(app.Replicas["low"], "the capacity of cluster1 is freed")
+		for _, child := range app.Children {
+			suite.Zero(child.Replicas["low"], "down to the leaf clusters")
+		}
+	}
+}
+
+func TestPreemptionSuite(t *testing.T) {
+	suite.Run(t, new(PreemptionSuite))
+}
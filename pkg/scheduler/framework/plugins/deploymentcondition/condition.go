@@ -0,0 +1,143 @@
+package deploymentcondition
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	clusterapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+)
+
+// extentValues returns the values of the extent of a condition, trimmed and without empty ones.
+func extentValues(extent []string) []string {
+	values := make([]string, 0, len(extent))
+	for _, value := range extent {
+		if value = strings.TrimSpace(value); len(value) > 0 {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// subjectOf returns the name of the subject component of cond, or false if the subject is not a component.
+func subjectOf(cond v1alpha1.Condition) (string, bool) {
+	if cond.Subject.Type != "" && cond.Subject.Type != v1alpha1.XjectTypeComponent {
+		return "", false
+	}
+	return cond.Subject.Name, true
+}
+
+// clusterAttributes returns the values of the object on cluster.
+func clusterAttributes(object v1alpha1.Xject, cluster *clusterapi.ManagedCluster) (sets.String, error) {
+	netEnvironments, _, resForms, _, _, geoLocations, providers := cluster.GetHypernodeLabelsMapFromManagedCluster()
+	switch object.Type {
+	case v1alpha1.XjectTypeCluster:
+		return sets.NewString(cluster.Name), nil
+	case v1alpha1.XjectTypeGeoLocation:
+		return sets.StringKeySet(geoLocations), nil
+	case v1alpha1.XjectTypeNetEnvironment:
+		return sets.StringKeySet(netEnvironments), nil
+	case v1alpha1.XjectTypeProvider:
+		return sets.StringKeySet(providers), nil
+	case v1alpha1.XjectTypeResForm:
+		return sets.StringKeySet(resForms), nil
+	case v1alpha1.XjectTypeLabel:
+		if value, ok := cluster.GetLabels()[object.Name]; ok {
+			return sets.NewString(value), nil
+		}
+		return sets.NewString(), nil
+	}
+	return nil, fmt.Errorf("unknown object type %q", object.Type)
+}
+
+// matchCluster returns true if cluster satisfies cond, whose object is an attribute of clusters.
+func matchCluster(cond v1alpha1.Condition, cluster *clusterapi.ManagedCluster) (bool, error) {
+	attributes, err := clusterAttributes(cond.Object, cluster)
+	if err != nil {
+		return false, err
+	}
+	values := extentValues(cond.Extent)
+	switch cond.Relation {
+	case v1alpha1.RelationIn:
+		return attributes.HasAny(values...), nil
+	case v1alpha1.RelationNotIn:
+		return !attributes.HasAny(values...), nil
+	}
+	return false, fmt.Errorf("relation %q doesn't apply to object type %q", cond.Relation, cond.Object.Type)
+}
+
+// placement returns the clusters in clusters that apps place replicas of component on. Apps of other
+// clusters are looked into for their children, the apps of a child cluster are nested in its parent.
+func placement(apps []*v1alpha1.ResourceBindingApps, clusters map[string]*clusterapi.ManagedCluster,
+	component string) []*clusterapi.ManagedCluster {
+	result := make([]*clusterapi.ManagedCluster, 0)
+	for _, app := range apps {
+		if cluster, ok := clusters[app.ClusterName]; ok {
+			if app.Replicas[component] > 0 {
+				result = append(result, cluster)
+			}
+			continue
+		}
+		result = append(result, placement(app.Children, clusters, component)...)
+	}
+	return result
+}
+
+// colocated returns true if every cluster of subject shares a domain with a cluster of object.
+func colocated(subject, object []*clusterapi.ManagedCluster, domains func(*clusterapi.ManagedCluster) sets.String) bool {
+	objectDomains := sets.NewString()
+	for _, cluster := range object {
+		objectDomains = objectDomains.Union(domains(cluster))
+	}
+	for _, cluster := range subject {
+		if !domains(cluster).HasAny(objectDomains.UnsortedList()...) {
+			return false
+		}
+	}
+	return true
+}
+
+func clusterName(cluster *clusterapi.ManagedCluster) sets.String {
+	return sets.NewString(cluster.Name)
+}
+
+func geoLocation(cluster *clusterapi.ManagedCluster) sets.String {
+	_, _, _, _, _, geoLocations, _ := cluster.GetHypernodeLabelsMapFromManagedCluster()
+	return sets.StringKeySet(geoLocations)
+}
+
+// satisfied returns true if the resource binding apps satisfy cond.
+func satisfied(cond v1alpha1.Condition, apps []*v1alpha1.ResourceBindingApps,
+	clusters map[string]*clusterapi.ManagedCluster) (bool, error) {
+	name, ok := subjectOf(cond)
+	if !ok {
+		return false, fmt.Errorf("unknown subject type %q", cond.Subject.Type)
+	}
+	subject := placement(apps, clusters, name)
+	if cond.Object.Type != v1alpha1.XjectTypeComponent {
+		for _, cluster := range subject {
+			if ok, err := matchCluster(cond, cluster); err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+
+	object := placement(apps, clusters, cond.Object.Name)
+	switch cond.Relation {
+	case v1alpha1.RelationSameAs:
+		return colocated(subject, object, clusterName), nil
+	case v1alpha1.RelationNotSameAs:
+		for _, cluster := range subject {
+			if colocated([]*clusterapi.ManagedCluster{cluster}, object, clusterName) {
+				return false, nil
+			}
+		}
+		return true, nil
+	case v1alpha1.RelationNear:
+		return colocated(subject, object, geoLocation), nil
+	}
+	return false, fmt.Errorf("relation %q doesn't apply to object type %q", cond.Relation, cond.Object.Type)
+}
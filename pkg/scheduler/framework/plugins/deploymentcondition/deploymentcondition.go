@@ -0,0 +1,98 @@
+package deploymentcondition
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+
+	"github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	clusterapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+	framework "github.com/lmxia/gaia/pkg/scheduler/framework/interfaces"
+	"github.com/lmxia/gaia/pkg/scheduler/framework/plugins/helper"
+	"github.com/lmxia/gaia/pkg/scheduler/framework/plugins/names"
+)
+
+// DeploymentCondition is a plugin that checks the mandatory deployment conditions of a description on the
+// attributes of clusters, and prefers the resource bindings that satisfy more of its best effort conditions.
+// The mandatory conditions between components are checked when the plans of components are combined.
+type DeploymentCondition struct {
+	handle framework.Handle
+}
+
+var _ framework.FilterPlugin = &DeploymentCondition{}
+var _ framework.ScorePlugin = &DeploymentCondition{}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (pl *DeploymentCondition) Name() string {
+	return names.DeploymentCondition
+}
+
+// Filter invoked at the filter extension point.
+func (pl *DeploymentCondition) Filter(ctx context.Context, com *v1alpha1.Component, cluster *clusterapi.ManagedCluster) *framework.Status {
+	if cluster == nil {
+		return framework.AsStatus(fmt.Errorf("deployment condition invalid cluster "))
+	}
+	desc := framework.DescriptionFrom(ctx)
+	if desc == nil {
+		return nil
+	}
+
+	for _, cond := range desc.Spec.DeploymentCondition.Mandatory {
+		if name, ok := subjectOf(cond); !ok || name != com.Name || cond.Object.Type == v1alpha1.XjectTypeComponent {
+			continue
+		}
+		ok, err := matchCluster(cond, cluster)
+		if err != nil {
+			return framework.NewStatus(framework.UnschedulableAndUnresolvable,
+				fmt.Sprintf("invalid mandatory condition of component %v: %v", com.Name, err))
+		}
+		if !ok {
+			return framework.NewStatus(framework.UnschedulableAndUnresolvable,
+				fmt.Sprintf("cluster %v doesn't satisfy the mandatory condition %v %v %v of component %v",
+					cluster.Name, cond.Object.Type, cond.Relation, cond.Extent, com.Name))
+		}
+	}
+	return nil
+}
+
+// Score invoked at the score extension point, the score is the share of best effort conditions rb satisfies.
+func (pl *DeploymentCondition) Score(ctx context.Context, rb *v1alpha1.ResourceBinding, clusters []*clusterapi.ManagedCluster) (int64, *framework.Status) {
+	desc := framework.DescriptionFrom(ctx)
+	if desc == nil || len(desc.Spec.DeploymentCondition.BestEffort) == 0 {
+		return 0, nil
+	}
+	clusterMap := make(map[string]*clusterapi.ManagedCluster, 0)
+	for _, cluster := range clusters {
+		clusterMap[cluster.Name] = cluster
+	}
+
+	var count int64
+	for _, cond := range desc.Spec.DeploymentCondition.BestEffort {
+		ok, err := satisfied(cond, rb.Spec.RbApps, clusterMap)
+		if err != nil {
+			klog.V(4).InfoS("Skipping invalid best effort condition", "description", klog.KObj(desc), "err", err)
+			continue
+		}
+		if ok {
+			count++
+		}
+	}
+	return count * framework.MaxClusterScore / int64(len(desc.Spec.DeploymentCondition.BestEffort)), nil
+}
+
+// NormalizeScore invoked after scoring all clusters.
+func (pl *DeploymentCondition) NormalizeScore(ctx context.Context, scores framework.ResourceBindingScoreList) *framework.Status {
+	return helper.DefaultNormalizeScore(framework.MaxClusterScore, true, scores)
+}
+
+// ScoreExtensions of the Score plugin.
+func (pl *DeploymentCondition) ScoreExtensions() framework.ScoreExtensions {
+	return pl
+}
+
+// New initializes a new plugin and returns it.
+func New(_ runtime.Object, h framework.Handle) (framework.Plugin, error) {
+	return &DeploymentCondition{handle: h}, nil
+}
@@ -0,0 +1,126 @@
+package deploymentcondition
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	clusterapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+	framework "github.com/lmxia/gaia/pkg/scheduler/framework/interfaces"
+)
+
+type DeploymentConditionSuite struct {
+	pl       *DeploymentCondition
+	desc     *v1alpha1.Description
+	clusters []*clusterapi.ManagedCluster
+	suite.Suite
+}
+
+func (suite *DeploymentConditionSuite) SetupTest() {
+	geo := func(name, location string) *clusterapi.ManagedCluster {
+		return &clusterapi.ManagedCluster{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{clusterapi.ParsedGeoLocationKey: location, "tier": "edge"},
+		}}
+	}
+	suite.pl = &DeploymentCondition{}
+	suite.clusters = []*clusterapi.ManagedCluster{geo("c0", "beijing"), geo("c1", "beijing"), geo("c2", "shanghai")}
+	suite.desc = &v1alpha1.Description{
+		ObjectMeta: metav1.ObjectMeta{Name: "desc"},
+		Spec: v1alpha1.DescriptionSpec{
+			Components: []v1alpha1.Component{{Name: "web"}, {Name: "db"}},
+		},
+	}
+}
+
+func (suite *DeploymentConditionSuite) condition(subject string, object v1alpha1.Xject, relation string,
+	extent ...string) v1alpha1.Condition {
+	return v1alpha1.Condition{
+		Subject:  v1alpha1.Xject{Name: subject, Type: v1alpha1.XjectTypeComponent},
+		Object:   object,
+		Relation: relation,
+		Extent:   extent,
+	}
+}
+
+func (suite *DeploymentConditionSuite) TestExtentValues() {
+	suite.Equal([]string{"beijing", "shanghai"}, extentValues([]string{" beijing", "", "shanghai "}))
+	suite.Empty(extentValues(nil))
+}
+
+func (suite *DeploymentConditionSuite) TestDecodeCondition() {
+	// the shape of a condition in a description manifest, following the crd schema.
+	fixture := `{
+		"subject": {"name": "web", "type": "component"},
+		"object": {"type": "geolocation"},
+		"relation": "in",
+		"extent": ["beijing", "shanghai"]
+	}`
+	var cond v1alpha1.Condition
+	suite.NoError(json.Unmarshal([]byte(fixture), &cond))
+	suite.Equal([]string{"beijing", "shanghai"}, cond.Extent)
+
+	ok, err := matchCluster(cond, suite.clusters[2])
+	suite.NoError(err)
+	suite.True(ok)
+}
+
+func (suite *DeploymentConditionSuite) TestFilter() {
+	suite.desc.Spec.DeploymentCondition.Mandatory = []v1alpha1.Condition{
+		suite.condition("web", v1alpha1.Xject{Type: v1alpha1.XjectTypeGeoLocation}, v1alpha1.RelationIn, "beijing"),
+		suite.condition("web", v1alpha1.Xject{Type: v1alpha1.XjectTypeCluster}, v1alpha1.RelationNotIn, "c1"),
+		suite.condition("web", v1alpha1.Xject{Name: "db", Type: v1alpha1.XjectTypeComponent}, v1alpha1.RelationSameAs),
+	}
+	ctx := framework.WithDescription(context.TODO(), suite.desc)
+	web, db := &suite.desc.Spec.Components[0], &suite.desc.Spec.Components[1]
+
+	suite.Nil(suite.pl.Filter(ctx, web, suite.clusters[0]))
+	suite.Equal(framework.UnschedulableAndUnresolvable, suite.pl.Filter(ctx, web, suite.clusters[1]).Code())
+	suite.Equal(framework.UnschedulableAndUnresolvable, suite.pl.Filter(ctx, web, suite.clusters[2]).Code())
+	suite.Nil(suite.pl.Filter(ctx, db, suite.clusters[2]), "the conditions of web don't apply to db")
+	suite.Nil(suite.pl.Filter(context.TODO(), web, suite.clusters[2]), "no description is being scheduled")
+
+	suite.desc.Spec.DeploymentCondition.Mandatory = []v1alpha1.Condition{
+		suite.condition("web", v1alpha1.Xject{Type: v1alpha1.XjectTypeLabel}, v1alpha1.RelationNear, "edge"),
+	}
+	suite.Equal(framework.UnschedulableAndUnresolvable, suite.pl.Filter(ctx, web, suite.clusters[0]).Code(),
+		"near doesn't apply to labels")
+}
+
+func (suite *DeploymentConditionSuite) TestScore() {
+	suite.desc.Spec.DeploymentCondition.BestEffort = []v1alpha1.Condition{
+		suite.condition("web", v1alpha1.Xject{Name: "tier", Type: v1alpha1.XjectTypeLabel}, v1alpha1.RelationIn, "edge"),
+		suite.condition("web", v1alpha1.Xject{Name: "db", Type: v1alpha1.XjectTypeComponent}, v1alpha1.RelationNear),
+		suite.condition("web", v1alpha1.Xject{Name: "db", Type: v1alpha1.XjectTypeComponent}, v1alpha1.RelationNotSameAs),
+		suite.condition("db", v1alpha1.Xject{Type: v1alpha1.XjectTypeGeoLocation}, v1alpha1.RelationIn, "shanghai"),
+	}
+	ctx := framework.WithDescription(context.TODO(), suite.desc)
+	rb := func(web, db string) *v1alpha1.ResourceBinding {
+		return &v1alpha1.ResourceBinding{Spec: v1alpha1.ResourceBindingSpec{RbApps: []*v1alpha1.ResourceBindingApps{
+			{ClusterName: "parent", Children: []*v1alpha1.ResourceBindingApps{
+				{ClusterName: web, Replicas: map[string]int32{"web": 1}},
+				{ClusterName: db, Replicas: map[string]int32{"db": 1}},
+			}},
+		}}}
+	}
+
+	score, status := suite.pl.Score(ctx, rb("c0", "c1"), suite.clusters)
+	suite.Nil(status)
+	suite.Equal(int64(75), score, "all but db in shanghai")
+	score, _ = suite.pl.Score(ctx, rb("c0", "c0"), suite.clusters)
+	suite.Equal(int64(50), score, "web and db in one cluster")
+	score, _ = suite.pl.Score(ctx, rb("c0", "c2"), suite.clusters)
+	suite.Equal(int64(75), score, "web is far from db")
+
+	scores := framework.ResourceBindingScoreList{{Index: 0, Score: 75}, {Index: 1, Score: 50}}
+	suite.Nil(suite.pl.NormalizeScore(ctx, scores))
+	suite.Less(scores[0].Score, scores[1].Score, "the lower score is selected first")
+}
+
+func TestDeploymentCondition(t *testing.T) {
+	suite.Run(t, new(DeploymentConditionSuite))
+}
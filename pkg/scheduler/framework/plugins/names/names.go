@@ -1,16 +1,21 @@
 package names
 
 const (
-	TaintToleration  = "TaintToleration"
-	CorePriority     = "CorePriority"
-	AffinityDaemon   = "AffinityDaemon"
-	UserAPP          = "UserAPP"
-	SpecificResource = "SpecificResource"
-	NetEnviroment    = "NetEnviroment"
-	Geolocation      = "GeoLocation"
-	SupplierName     = "SupplierName"
-	ResForm          = "ResForm"
-	RuntimeType      = "RuntimeType"
-	NodeRole         = "NodeRole"
-	VirtualNode      = "VirtualNode"
+	TaintToleration   = "TaintToleration"
+	CorePriority      = "CorePriority"
+	AffinityDaemon    = "AffinityDaemon"
+	UserAPP           = "UserAPP"
+	SpecificResource  = "SpecificResource"
+	NetEnviroment     = "NetEnviroment"
+	Geolocation       = "GeoLocation"
+	SupplierName      = "SupplierName"
+	ResForm           = "ResForm"
+	RuntimeType       = "RuntimeType"
+	NodeRole          = "NodeRole"
+	VirtualNode       = "VirtualNode"
+	DefaultBinder     = "DefaultBinder"
+	DefaultPreemption = "DefaultPreemption"
+
+	DeploymentCondition = "DeploymentCondition"
+	Sandbox             = "Sandbox"
 )
@@ -0,0 +1,61 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	clusterapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+	framework "github.com/lmxia/gaia/pkg/scheduler/framework/interfaces"
+	"github.com/lmxia/gaia/pkg/scheduler/framework/plugins/names"
+)
+
+// Sandbox is a plugin that checks if a cluster runs the runtime sandbox of a component.
+type Sandbox struct {
+	handle framework.Handle
+}
+
+var _ framework.FilterPlugin = &Sandbox{}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (pl *Sandbox) Name() string {
+	return names.Sandbox
+}
+
+// Filter invoked at the filter extension point.
+func (pl *Sandbox) Filter(ctx context.Context, com *v1alpha1.Component, cluster *clusterapi.ManagedCluster) *framework.Status {
+	if cluster == nil {
+		return framework.AsStatus(fmt.Errorf("sandbox invalid cluster"))
+	}
+	desc := framework.DescriptionFrom(ctx)
+	if desc == nil {
+		return nil
+	}
+
+	sandbox := desc.SandboxOf(com.Name)
+	if !fit(sandbox, cluster) {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable,
+			fmt.Sprintf("cluster %v doesn't run sandbox %q of component %v", cluster.Name, sandbox, com.Name))
+	}
+	return nil
+}
+
+// fit returns true if cluster runs sandbox, every cluster runs runc.
+func fit(sandbox v1alpha1.SandboxType, cluster *clusterapi.ManagedCluster) bool {
+	if len(sandbox) == 0 || sandbox == v1alpha1.Runc {
+		return true
+	}
+	for _, item := range cluster.Status.Sandboxes {
+		if item == string(sandbox) {
+			return true
+		}
+	}
+	return false
+}
+
+// New initializes a new plugin and returns it.
+func New(_ runtime.Object, h framework.Handle) (framework.Plugin, error) {
+	return &Sandbox{handle: h}, nil
+}
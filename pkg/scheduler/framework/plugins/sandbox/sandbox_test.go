@@ -0,0 +1,54 @@
+package sandbox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	clusterapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+	framework "github.com/lmxia/gaia/pkg/scheduler/framework/interfaces"
+)
+
+type SandboxSuite struct {
+	pl   *Sandbox
+	desc *v1alpha1.Description
+	suite.Suite
+}
+
+func (suite *SandboxSuite) SetupTest() {
+	suite.pl = &Sandbox{}
+	suite.desc = &v1alpha1.Description{
+		ObjectMeta: metav1.ObjectMeta{Name: "desc"},
+		Spec: v1alpha1.DescriptionSpec{
+			Components: []v1alpha1.Component{{Name: "web"}, {Name: "fn"}, {Name: "db"}},
+			WorkloadComponents: []v1alpha1.WorkloadComponent{
+				{ComponentName: "web", Sandbox: v1alpha1.Runc},
+				{ComponentName: "fn", Sandbox: v1alpha1.Wasm},
+			},
+		},
+	}
+}
+
+func (suite *SandboxSuite) TestFilter() {
+	ctx := framework.WithDescription(context.TODO(), suite.desc)
+	edge := &clusterapi.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "edge"},
+		Status:     clusterapi.ManagedClusterStatus{Sandboxes: []string{"runc", "wasm"}},
+	}
+	cloud := &clusterapi.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cloud"}}
+
+	for i := range suite.desc.Spec.Components {
+		suite.Nil(suite.pl.Filter(ctx, &suite.desc.Spec.Components[i], edge))
+	}
+	suite.Nil(suite.pl.Filter(ctx, &suite.desc.Spec.Components[0], cloud), "every cluster runs runc")
+	suite.Nil(suite.pl.Filter(ctx, &suite.desc.Spec.Components[2], cloud), "so does a component without sandbox")
+	suite.Equal(framework.UnschedulableAndUnresolvable,
+		suite.pl.Filter(ctx, &suite.desc.Spec.Components[1], cloud).Code())
+}
+
+func TestSandbox(t *testing.T) {
+	suite.Run(t, new(SandboxSuite))
+}
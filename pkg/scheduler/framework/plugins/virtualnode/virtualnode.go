@@ -2,10 +2,12 @@ package virtualnode
 
 import (
 	"context"
+	"fmt"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
 	clusterapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+	schedulerapis "github.com/lmxia/gaia/pkg/scheduler/apis"
 	framework "github.com/lmxia/gaia/pkg/scheduler/framework/interfaces"
 	"github.com/lmxia/gaia/pkg/scheduler/framework/plugins/helper"
 	"github.com/lmxia/gaia/pkg/scheduler/framework/plugins/names"
@@ -13,7 +15,8 @@ import (
 
 // TaintToleration is a plugin that checks if a subscription tolerates a cluster's taints.
 type VirtualNode struct {
-	handle framework.Handle
+	handle       framework.Handle
+	resourceForm string
 }
 
 var _ framework.ScorePlugin = &VirtualNode{}
@@ -34,7 +37,7 @@ func (v *VirtualNode) Score(ctx context.Context, rb *v1alpha1.ResourceBinding, c
 		clusterMap[cluster.Name] = cluster
 	}
 
-	return calculateScore(0, rb.Spec.RbApps, clusterMap), nil
+	return calculateScore(0, v.resourceForm, rb.Spec.RbApps, clusterMap), nil
 }
 
 // ScoreExtensions of the Score plugin.
@@ -43,6 +46,13 @@ func (v *VirtualNode) ScoreExtensions() framework.ScoreExtensions {
 }
 
 // New initializes a new plugin and returns it.
-func New(_ runtime.Object, h framework.Handle) (framework.Plugin, error) {
-	return &VirtualNode{handle: h}, nil
+func New(obj runtime.Object, h framework.Handle) (framework.Plugin, error) {
+	args, ok := obj.(*schedulerapis.VirtualNodeArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type VirtualNodeArgs, got %T", obj)
+	}
+	if err := schedulerapis.ValidateVirtualNodeArgs(args); err != nil {
+		return nil, err
+	}
+	return &VirtualNode{handle: h, resourceForm: args.ResourceForm}, nil
 }
@@ -13,12 +13,17 @@ import (
 	gaiaClientSet "github.com/lmxia/gaia/pkg/generated/clientset/versioned"
 	informers "github.com/lmxia/gaia/pkg/generated/informers/externalversions"
 	schedulerapis "github.com/lmxia/gaia/pkg/scheduler/apis"
+	schedulerscheme "github.com/lmxia/gaia/pkg/scheduler/apis/scheme"
+	schedulerapisv1alpha1 "github.com/lmxia/gaia/pkg/scheduler/apis/v1alpha1"
 	framework "github.com/lmxia/gaia/pkg/scheduler/framework/interfaces"
 	"github.com/lmxia/gaia/pkg/scheduler/metrics"
 	"github.com/lmxia/gaia/pkg/scheduler/parallelize"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
 )
 
 const (
@@ -31,6 +36,12 @@ const (
 	preScore                = "PreScore"
 	score                   = "Score"
 	scoreExtensionNormalize = "ScoreExtensionNormalize"
+	reserve                 = "Reserve"
+	unreserve               = "Unreserve"
+	permit                  = "Permit"
+	preBind                 = "PreBind"
+	bind                    = "Bind"
+	postBind                = "PostBind"
 )
 
 // frameworkImpl is the component responsible for initializing and running scheduler
@@ -42,6 +53,13 @@ type frameworkImpl struct {
 	postFilterPlugins []framework.PostFilterPlugin
 	preScorePlugins   []framework.PreScorePlugin
 	scorePlugins      []framework.ScorePlugin
+	reservePlugins    []framework.ReservePlugin
+	permitPlugins     []framework.PermitPlugin
+	preBindPlugins    []framework.PreBindPlugin
+	bindPlugins       []framework.BindPlugin
+	postBindPlugins   []framework.PostBindPlugin
+
+	waitingDescriptions *waitingDescriptionsMap
 
 	clientSet       gaiaClientSet.Interface
 	kubeConfig      *restclient.Config
@@ -76,6 +94,11 @@ func (f *frameworkImpl) getExtensionPoints(plugins *schedulerapis.Plugins) []ext
 		{&plugins.PostFilter, &f.postFilterPlugins},
 		{&plugins.PreScore, &f.preScorePlugins},
 		{&plugins.Score, &f.scorePlugins},
+		{&plugins.Reserve, &f.reservePlugins},
+		{&plugins.Permit, &f.permitPlugins},
+		{&plugins.PreBind, &f.preBindPlugins},
+		{&plugins.Bind, &f.bindPlugins},
+		{&plugins.PostBind, &f.postBindPlugins},
 	}
 }
 
@@ -145,7 +168,7 @@ func defaultFrameworkOptions() frameworkOptions {
 var _ framework.Framework = &frameworkImpl{}
 
 // NewFramework initializes plugins given the configuration and the registry.
-func NewFramework(r Registry, plugins *schedulerapis.Plugins, opts ...Option) (framework.Framework, error) {
+func NewFramework(r Registry, profile *schedulerapis.SchedulerProfile, opts ...Option) (framework.Framework, error) {
 	options := defaultFrameworkOptions()
 	for _, opt := range opts {
 		opt(&options)
@@ -160,30 +183,57 @@ func NewFramework(r Registry, plugins *schedulerapis.Plugins, opts ...Option) (f
 		metricsRecorder:   options.metricsRecorder,
 		runAllFilters:     options.runAllFilters,
 		parallelizer:      options.parallelizer,
-		profileName:       "default",
+		profileName:       schedulerapis.DefaultSchedulerName,
+
+		waitingDescriptions: newWaitingDescriptionsMap(),
 	}
 
-	if r == nil {
+	if profile == nil {
+		return f, nil
+	}
+	f.profileName = profile.SchedulerName
+	if r == nil || profile.Plugins == nil {
 		return f, nil
 	}
 
+	pg := sets.NewString(profile.Plugins.Names()...)
+	pluginConfig := make(map[string]runtime.Object, len(profile.PluginConfig))
+	for i := range profile.PluginConfig {
+		name := profile.PluginConfig[i].Name
+		if _, ok := pluginConfig[name]; ok {
+			return nil, fmt.Errorf("repeated config for plugin %s", name)
+		}
+		if _, ok := r[name]; !ok {
+			return nil, fmt.Errorf("config for unknown plugin %s", name)
+		}
+		pluginConfig[name] = profile.PluginConfig[i].Args
+	}
+
 	// initialize plugins per individual extension points
 	pluginsMap := make(map[string]framework.Plugin)
 	for name, factory := range r {
-		p, err := factory(nil, f)
+		// initialize only needed plugins.
+		if !pg.Has(name) {
+			continue
+		}
+		args, err := decodePluginArgs(name, pluginConfig[name])
+		if err != nil {
+			return nil, fmt.Errorf("decoding args for plugin %q: %w", name, err)
+		}
+		p, err := factory(args, f)
 		if err != nil {
 			return nil, fmt.Errorf("initializing plugin %q: %w", name, err)
 		}
 		pluginsMap[name] = p
 	}
 
-	for _, e := range f.getExtensionPoints(plugins) {
+	for _, e := range f.getExtensionPoints(profile.Plugins) {
 		if err := updatePluginList(e.slicePtr, *e.plugins, pluginsMap); err != nil {
 			return nil, err
 		}
 	}
 
-	for _, scorePlugin := range plugins.Score.Enabled {
+	for _, scorePlugin := range profile.Plugins.Score.Enabled {
 		// a weight of zero is not permitted, plugins can be disabled explicitly
 		// when configured.
 		if scorePlugin.Weight == 0 {
@@ -195,6 +245,31 @@ func NewFramework(r Registry, plugins *schedulerapis.Plugins, opts ...Option) (f
 	return f, nil
 }
 
+// decodePluginArgs converts the args of a plugin into the typed, defaulted args registered for it
+// under the kind "<plugin name>Args". Plugins without registered args get the given args unchanged.
+func decodePluginArgs(name string, args runtime.Object) (runtime.Object, error) {
+	gvk := schedulerapisv1alpha1.SchemeGroupVersion.WithKind(name + "Args")
+	if !schedulerscheme.Scheme.Recognizes(gvk) {
+		return args, nil
+	}
+
+	var data []byte
+	switch t := args.(type) {
+	case nil:
+		data = []byte("{}")
+	case *runtime.Unknown:
+		data = t.Raw
+	default:
+		// already typed args.
+		return args, nil
+	}
+	obj, _, err := schedulerscheme.Codecs.UniversalDecoder().Decode(data, &gvk, nil)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
 // copied from k8s.io/kubernetes/pkg/scheduler/framework/runtime/framework.go
 func updatePluginList(pluginList interface{}, pluginSet schedulerapis.PluginSet, pluginsMap map[string]framework.Plugin) error {
 	plugins := reflect.ValueOf(pluginList).Elem()
@@ -288,7 +363,7 @@ func (f *frameworkImpl) runFilterPlugin(ctx context.Context, pl framework.Filter
 
 // RunPostFilterPlugins runs the set of configured PostFilter plugins until the first
 // Success or Error is met, otherwise continues to execute all plugins.
-func (f *frameworkImpl) RunPostFilterPlugins(ctx context.Context, sub *v1alpha1.Component, filteredClusterStatusMap framework.ClusterToStatusMap) (_ *framework.PostFilterResult, status *framework.Status) {
+func (f *frameworkImpl) RunPostFilterPlugins(ctx context.Context, desc *v1alpha1.Description, filteredClusterStatusMap framework.ClusterToStatusMap) (_ *framework.PostFilterResult, status *framework.Status) {
 	startTime := time.Now()
 	defer func() {
 		metrics.FrameworkExtensionPointDuration.WithLabelValues(postFilter, status.Code().String(), f.profileName).Observe(metrics.SinceInSeconds(startTime))
@@ -296,7 +371,7 @@ func (f *frameworkImpl) RunPostFilterPlugins(ctx context.Context, sub *v1alpha1.
 
 	statuses := make(framework.PluginToStatus)
 	for _, pl := range f.postFilterPlugins {
-		r, s := f.runPostFilterPlugin(ctx, pl, sub, filteredClusterStatusMap)
+		r, s := f.runPostFilterPlugin(ctx, pl, desc, filteredClusterStatusMap)
 		if s.IsSuccess() {
 			return r, s
 		} else if !s.IsUnschedulable() {
@@ -309,9 +384,9 @@ func (f *frameworkImpl) RunPostFilterPlugins(ctx context.Context, sub *v1alpha1.
 	return nil, statuses.Merge()
 }
 
-func (f *frameworkImpl) runPostFilterPlugin(ctx context.Context, pl framework.PostFilterPlugin, sub *v1alpha1.Component, filteredClusterStatusMap framework.ClusterToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+func (f *frameworkImpl) runPostFilterPlugin(ctx context.Context, pl framework.PostFilterPlugin, desc *v1alpha1.Description, filteredClusterStatusMap framework.ClusterToStatusMap) (*framework.PostFilterResult, *framework.Status) {
 	startTime := time.Now()
-	r, s := pl.PostFilter(ctx, sub, filteredClusterStatusMap)
+	r, s := pl.PostFilter(ctx, desc, filteredClusterStatusMap)
 	f.metricsRecorder.observePluginDurationAsync(postFilter, pl.Name(), s, metrics.SinceInSeconds(startTime))
 	return r, s
 }
@@ -431,6 +506,220 @@ func (f *frameworkImpl) runScoreExtension(ctx context.Context, pl framework.Scor
 	return status
 }
 
+// RunReservePluginsReserve runs the Reserve method in the set of configured
+// reserve plugins. If any of these plugins returns an error, it does not
+// continue running the remaining ones and returns the error. In such a case,
+// the description will not be scheduled and the caller will be expected to call
+// RunReservePluginsUnreserve.
+func (f *frameworkImpl) RunReservePluginsReserve(ctx context.Context, desc *v1alpha1.Description, rbs []*v1alpha1.ResourceBinding) (status *framework.Status) {
+	startTime := time.Now()
+	defer func() {
+		metrics.FrameworkExtensionPointDuration.WithLabelValues(reserve, status.Code().String(), f.profileName).Observe(metrics.SinceInSeconds(startTime))
+	}()
+	for _, pl := range f.reservePlugins {
+		status = f.runReservePluginReserve(ctx, pl, desc, rbs)
+		if !status.IsSuccess() {
+			err := status.AsError()
+			klog.ErrorS(err, "Failed running Reserve plugin", "plugin", pl.Name(), "description", klog.KObj(desc))
+			return framework.AsStatus(fmt.Errorf("running Reserve plugin %q: %w", pl.Name(), err))
+		}
+	}
+	return nil
+}
+
+func (f *frameworkImpl) runReservePluginReserve(ctx context.Context, pl framework.ReservePlugin, desc *v1alpha1.Description, rbs []*v1alpha1.ResourceBinding) *framework.Status {
+	startTime := time.Now()
+	status := pl.Reserve(ctx, desc, rbs)
+	f.metricsRecorder.observePluginDurationAsync(reserve, pl.Name(), status, metrics.SinceInSeconds(startTime))
+	return status
+}
+
+// RunReservePluginsUnreserve runs the Unreserve method in the set of
+// configured reserve plugins.
+func (f *frameworkImpl) RunReservePluginsUnreserve(ctx context.Context, desc *v1alpha1.Description, rbs []*v1alpha1.ResourceBinding) {
+	startTime := time.Now()
+	defer func() {
+		metrics.FrameworkExtensionPointDuration.WithLabelValues(unreserve, framework.Success.String(), f.profileName).Observe(metrics.SinceInSeconds(startTime))
+	}()
+	// Execute the Unreserve operation of each reserve plugin in the
+	// *reverse* order in which the Reserve operation was executed.
+	for i := len(f.reservePlugins) - 1; i >= 0; i-- {
+		f.runReservePluginUnreserve(ctx, f.reservePlugins[i], desc, rbs)
+	}
+}
+
+func (f *frameworkImpl) runReservePluginUnreserve(ctx context.Context, pl framework.ReservePlugin, desc *v1alpha1.Description, rbs []*v1alpha1.ResourceBinding) {
+	startTime := time.Now()
+	pl.Unreserve(ctx, desc, rbs)
+	f.metricsRecorder.observePluginDurationAsync(unreserve, pl.Name(), nil, metrics.SinceInSeconds(startTime))
+}
+
+// RunPermitPlugins runs the set of configured permit plugins. If any of these
+// plugins returns a status other than "Success" or "Wait", it does not continue
+// running the remaining plugins and returns an error. Otherwise, if any of the
+// plugins returns "Wait", then this function will create and add waiting description
+// to a map of currently waiting descriptions and return status with "Wait" code.
+// Description will remain waiting description for the minimum duration returned by the permit plugins.
+func (f *frameworkImpl) RunPermitPlugins(ctx context.Context, desc *v1alpha1.Description, rbs []*v1alpha1.ResourceBinding) (status *framework.Status) {
+	startTime := time.Now()
+	defer func() {
+		metrics.FrameworkExtensionPointDuration.WithLabelValues(permit, status.Code().String(), f.profileName).Observe(metrics.SinceInSeconds(startTime))
+	}()
+	pluginsWaitTime := make(map[string]time.Duration)
+	statusCode := framework.Success
+	for _, pl := range f.permitPlugins {
+		status, timeout := f.runPermitPlugin(ctx, pl, desc, rbs)
+		if !status.IsSuccess() {
+			if status.IsUnschedulable() {
+				klog.V(4).InfoS("Description rejected by permit plugin", "description", klog.KObj(desc), "plugin", pl.Name(), "status", status.Message())
+				status.SetFailedPlugin(pl.Name())
+				return status
+			}
+			if status.Code() == framework.Wait {
+				// Not allowed to be greater than maxTimeout.
+				if timeout > maxTimeout {
+					timeout = maxTimeout
+				}
+				pluginsWaitTime[pl.Name()] = timeout
+				statusCode = framework.Wait
+			} else {
+				err := status.AsError()
+				klog.ErrorS(err, "Failed running Permit plugin", "plugin", pl.Name(), "description", klog.KObj(desc))
+				return framework.AsStatus(fmt.Errorf("running Permit plugin %q: %w", pl.Name(), err)).WithFailedPlugin(pl.Name())
+			}
+		}
+	}
+	if statusCode == framework.Wait {
+		waitingDescription := newWaitingDescription(desc, pluginsWaitTime)
+		f.waitingDescriptions.add(waitingDescription)
+		msg := fmt.Sprintf("one or more plugins asked to wait and no plugin rejected description %q", desc.Name)
+		klog.V(4).InfoS("One or more plugins asked to wait and no plugin rejected description", "description", klog.KObj(desc))
+		return framework.NewStatus(framework.Wait, msg)
+	}
+	return nil
+}
+
+func (f *frameworkImpl) runPermitPlugin(ctx context.Context, pl framework.PermitPlugin, desc *v1alpha1.Description, rbs []*v1alpha1.ResourceBinding) (*framework.Status, time.Duration) {
+	startTime := time.Now()
+	status, timeout := pl.Permit(ctx, desc, rbs)
+	f.metricsRecorder.observePluginDurationAsync(permit, pl.Name(), status, metrics.SinceInSeconds(startTime))
+	return status, timeout
+}
+
+// WaitOnPermit will block, if the description is a waiting description, until the waiting description is rejected or allowed.
+func (f *frameworkImpl) WaitOnPermit(ctx context.Context, desc *v1alpha1.Description) *framework.Status {
+	waitingDescription := f.waitingDescriptions.get(desc.UID)
+	if waitingDescription == nil {
+		return nil
+	}
+	defer f.waitingDescriptions.remove(desc.UID)
+	klog.V(4).InfoS("Description waiting on permit", "description", klog.KObj(desc))
+
+	startTime := time.Now()
+	var s *framework.Status
+	select {
+	case s = <-waitingDescription.s:
+	case <-ctx.Done():
+		s = framework.AsStatus(fmt.Errorf("waiting on permit for description %q: %w", desc.Name, ctx.Err()))
+	}
+	metrics.PermitWaitDuration.WithLabelValues(s.Code().String()).Observe(metrics.SinceInSeconds(startTime))
+
+	if !s.IsSuccess() {
+		if s.IsUnschedulable() {
+			klog.V(4).InfoS("Description rejected while waiting on permit", "description", klog.KObj(desc), "status", s.Message())
+			return s
+		}
+		err := s.AsError()
+		klog.ErrorS(err, "Failed waiting on permit for description", "description", klog.KObj(desc))
+		return framework.AsStatus(fmt.Errorf("waiting on permit for description: %w", err)).WithFailedPlugin(s.FailedPlugin())
+	}
+	return nil
+}
+
+// RunPreBindPlugins runs the set of configured prebind plugins. It returns a
+// failure (bool) if any of the plugins returns an error. It also returns an
+// error containing the rejection message or the error occurred in the plugin.
+func (f *frameworkImpl) RunPreBindPlugins(ctx context.Context, desc *v1alpha1.Description, rbs []*v1alpha1.ResourceBinding) (status *framework.Status) {
+	startTime := time.Now()
+	defer func() {
+		metrics.FrameworkExtensionPointDuration.WithLabelValues(preBind, status.Code().String(), f.profileName).Observe(metrics.SinceInSeconds(startTime))
+	}()
+	for _, pl := range f.preBindPlugins {
+		status = f.runPreBindPlugin(ctx, pl, desc, rbs)
+		if !status.IsSuccess() {
+			if status.IsUnschedulable() {
+				klog.V(4).InfoS("Description rejected by PreBind plugin", "description", klog.KObj(desc), "plugin", pl.Name(), "status", status.Message())
+				status.SetFailedPlugin(pl.Name())
+				return status
+			}
+			err := status.AsError()
+			klog.ErrorS(err, "Failed running PreBind plugin", "plugin", pl.Name(), "description", klog.KObj(desc))
+			return framework.AsStatus(fmt.Errorf("running PreBind plugin %q: %w", pl.Name(), err))
+		}
+	}
+	return nil
+}
+
+func (f *frameworkImpl) runPreBindPlugin(ctx context.Context, pl framework.PreBindPlugin, desc *v1alpha1.Description, rbs []*v1alpha1.ResourceBinding) *framework.Status {
+	startTime := time.Now()
+	status := pl.PreBind(ctx, desc, rbs)
+	f.metricsRecorder.observePluginDurationAsync(preBind, pl.Name(), status, metrics.SinceInSeconds(startTime))
+	return status
+}
+
+// RunBindPlugins runs the set of configured bind plugins until one returns a non `Skip` status.
+func (f *frameworkImpl) RunBindPlugins(ctx context.Context, desc *v1alpha1.Description, rbs []*v1alpha1.ResourceBinding, clusters []*clusterapi.ManagedCluster) (status *framework.Status) {
+	startTime := time.Now()
+	defer func() {
+		metrics.FrameworkExtensionPointDuration.WithLabelValues(bind, status.Code().String(), f.profileName).Observe(metrics.SinceInSeconds(startTime))
+	}()
+	if len(f.bindPlugins) == 0 {
+		return framework.NewStatus(framework.Skip, "")
+	}
+	for _, bp := range f.bindPlugins {
+		status = f.runBindPlugin(ctx, bp, desc, rbs, clusters)
+		if status.Code() == framework.Skip {
+			continue
+		}
+		if !status.IsSuccess() {
+			if status.IsUnschedulable() {
+				klog.V(4).InfoS("Description rejected by Bind plugin", "description", klog.KObj(desc), "plugin", bp.Name(), "status", status.Message())
+				status.SetFailedPlugin(bp.Name())
+				return status
+			}
+			err := status.AsError()
+			klog.ErrorS(err, "Failed running Bind plugin", "plugin", bp.Name(), "description", klog.KObj(desc))
+			return framework.AsStatus(fmt.Errorf("running Bind plugin %q: %w", bp.Name(), err))
+		}
+		return status
+	}
+	return status
+}
+
+func (f *frameworkImpl) runBindPlugin(ctx context.Context, bp framework.BindPlugin, desc *v1alpha1.Description, rbs []*v1alpha1.ResourceBinding, clusters []*clusterapi.ManagedCluster) *framework.Status {
+	startTime := time.Now()
+	status := bp.Bind(ctx, desc, rbs, clusters)
+	f.metricsRecorder.observePluginDurationAsync(bind, bp.Name(), status, metrics.SinceInSeconds(startTime))
+	return status
+}
+
+// RunPostBindPlugins runs the set of configured postbind plugins.
+func (f *frameworkImpl) RunPostBindPlugins(ctx context.Context, desc *v1alpha1.Description, rbs []*v1alpha1.ResourceBinding, clusters []*clusterapi.ManagedCluster) {
+	startTime := time.Now()
+	defer func() {
+		metrics.FrameworkExtensionPointDuration.WithLabelValues(postBind, framework.Success.String(), f.profileName).Observe(metrics.SinceInSeconds(startTime))
+	}()
+	for _, pl := range f.postBindPlugins {
+		f.runPostBindPlugin(ctx, pl, desc, rbs, clusters)
+	}
+}
+
+func (f *frameworkImpl) runPostBindPlugin(ctx context.Context, pl framework.PostBindPlugin, desc *v1alpha1.Description, rbs []*v1alpha1.ResourceBinding, clusters []*clusterapi.ManagedCluster) {
+	startTime := time.Now()
+	pl.PostBind(ctx, desc, rbs, clusters)
+	f.metricsRecorder.observePluginDurationAsync(postBind, pl.Name(), nil, metrics.SinceInSeconds(startTime))
+}
+
 // HasFilterPlugins returns true if at least one filter plugin is defined.
 func (f *frameworkImpl) HasFilterPlugins() bool {
 	return len(f.filterPlugins) > 0
@@ -500,3 +789,26 @@ func (f *frameworkImpl) ProfileName() string {
 func (f *frameworkImpl) Parallelizer() parallelize.Parallelizer {
 	return f.parallelizer
 }
+
+// IterateOverWaitingDescriptions acquires a read lock and iterates over the WaitingDescriptions map.
+func (f *frameworkImpl) IterateOverWaitingDescriptions(callback func(framework.WaitingSubscription)) {
+	f.waitingDescriptions.iterate(callback)
+}
+
+// GetWaitingDescription returns a reference to a WaitingSubscription given its UID.
+func (f *frameworkImpl) GetWaitingDescription(uid types.UID) framework.WaitingSubscription {
+	if wd := f.waitingDescriptions.get(uid); wd != nil {
+		return wd
+	}
+	return nil // Returning nil instead of *waitingDescription(nil).
+}
+
+// RejectWaitingDescription rejects a WaitingSubscription given its UID.
+// The returned value indicates if the given description is waiting or not.
+func (f *frameworkImpl) RejectWaitingDescription(uid types.UID) bool {
+	if waitingDescription := f.waitingDescriptions.get(uid); waitingDescription != nil {
+		waitingDescription.Reject("", "removed")
+		return true
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	clusterapi "github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
+	"github.com/lmxia/gaia/pkg/common"
+	schedulerapis "github.com/lmxia/gaia/pkg/scheduler/apis"
+	framework "github.com/lmxia/gaia/pkg/scheduler/framework/interfaces"
+	"github.com/lmxia/gaia/pkg/scheduler/framework/plugins/names"
+)
+
+type PluginArgsSuite struct {
+	suite.Suite
+}
+
+func (suite *PluginArgsSuite) TestDecodeDefaultArgs() {
+	args, err := decodePluginArgs(names.VirtualNode, nil)
+	suite.NoError(err)
+	suite.Equal(&schedulerapis.VirtualNodeArgs{ResourceForm: common.NodeResourceForm}, args)
+
+	args, err = decodePluginArgs(names.CorePriority, nil)
+	suite.NoError(err)
+	suite.Equal(&schedulerapis.CorePriorityArgs{NetEnvironment: common.NetworkLocationCore}, args)
+}
+
+func (suite *PluginArgsSuite) TestDecodeRawArgs() {
+	args, err := decodePluginArgs(names.VirtualNode, &runtime.Unknown{
+		Raw:         []byte(`{"resourceForm":"serverless"}`),
+		ContentType: runtime.ContentTypeJSON,
+	})
+	suite.NoError(err)
+	suite.Equal(&schedulerapis.VirtualNodeArgs{ResourceForm: "serverless"}, args)
+
+	_, err = decodePluginArgs(names.CorePriority, &runtime.Unknown{
+		Raw:         []byte(`{"netEnv":"edge"}`),
+		ContentType: runtime.ContentTypeJSON,
+	})
+	suite.Error(err)
+}
+
+func (suite *PluginArgsSuite) TestDecodeUnregisteredArgs() {
+	args, err := decodePluginArgs(names.TaintToleration, nil)
+	suite.NoError(err)
+	suite.Nil(args)
+}
+
+func TestPluginArgsSuite(t *testing.T) {
+	suite.Run(t, new(PluginArgsSuite))
+}
+
+const (
+	waitPermitPlugin = "WaitPermit"
+	skipBindPlugin   = "SkipBind"
+	countBindPlugin  = "CountBind"
+)
+
+type waitPermit struct{}
+
+func (p *waitPermit) Name() string { return waitPermitPlugin }
+
+func (p *waitPermit) Permit(_ context.Context, _ *v1alpha1.Description, _ []*v1alpha1.ResourceBinding) (*framework.Status, time.Duration) {
+	return framework.NewStatus(framework.Wait), time.Minute
+}
+
+type fakeBind struct {
+	name  string
+	code  framework.Code
+	binds int
+}
+
+func (p *fakeBind) Name() string { return p.name }
+
+func (p *fakeBind) Bind(_ context.Context, _ *v1alpha1.Description, _ []*v1alpha1.ResourceBinding, _ []*clusterapi.ManagedCluster) *framework.Status {
+	p.binds++
+	return framework.NewStatus(p.code)
+}
+
+type BindingSuite struct {
+	skip  *fakeBind
+	count *fakeBind
+	fwk   framework.Framework
+	desc  *v1alpha1.Description
+	suite.Suite
+}
+
+func (suite *BindingSuite) SetupTest() {
+	suite.skip = &fakeBind{name: skipBindPlugin, code: framework.Skip}
+	suite.count = &fakeBind{name: countBindPlugin, code: framework.Success}
+	registry := Registry{
+		waitPermitPlugin: func(_ runtime.Object, _ framework.Handle) (framework.Plugin, error) { return &waitPermit{}, nil },
+		skipBindPlugin:   func(_ runtime.Object, _ framework.Handle) (framework.Plugin, error) { return suite.skip, nil },
+		countBindPlugin:  func(_ runtime.Object, _ framework.Handle) (framework.Plugin, error) { return suite.count, nil },
+	}
+	profile := &schedulerapis.SchedulerProfile{
+		SchedulerName: schedulerapis.DefaultSchedulerName,
+		Plugins: &schedulerapis.Plugins{
+			Permit: schedulerapis.PluginSet{Enabled: []schedulerapis.Plugin{{Name: waitPermitPlugin}}},
+			Bind:   schedulerapis.PluginSet{Enabled: []schedulerapis.Plugin{{Name: skipBindPlugin}, {Name: countBindPlugin}}},
+		},
+	}
+	fwk, err := NewFramework(registry, profile)
+	suite.NoError(err)
+	suite.fwk = fwk
+	suite.desc = &v1alpha1.Description{ObjectMeta: metav1.ObjectMeta{Name: "desc", UID: "desc-uid"}}
+}
+
+func (suite *BindingSuite) TestWaitOnPermitAllowed() {
+	sts := suite.fwk.RunPermitPlugins(context.TODO(), suite.desc, nil)
+	suite.Equal(framework.Wait, sts.Code())
+
+	wd := suite.fwk.GetWaitingDescription(suite.desc.UID)
+	suite.NotNil(wd)
+	suite.Equal([]string{waitPermitPlugin}, wd.GetPendingPlugins())
+	wd.Allow(waitPermitPlugin)
+	suite.True(suite.fwk.WaitOnPermit(context.TODO(), suite.desc).IsSuccess())
+	suite.Nil(suite.fwk.GetWaitingDescription(suite.desc.UID))
+}
+
+func (suite *BindingSuite) TestWaitOnPermitRejected() {
+	suite.fwk.RunPermitPlugins(context.TODO(), suite.desc, nil)
+	suite.True(suite.fwk.RejectWaitingDescription(suite.desc.UID))
+	sts := suite.fwk.WaitOnPermit(context.TODO(), suite.desc)
+	suite.Equal(framework.Unschedulable, sts.Code())
+	suite.False(suite.fwk.RejectWaitingDescription(suite.desc.UID))
+}
+
+func (suite *BindingSuite) TestRunBindPluginsSkips() {
+	sts := suite.fwk.RunBindPlugins(context.TODO(), suite.desc, nil, nil)
+	suite.True(sts.IsSuccess())
+	suite.Equal(1, suite.skip.binds)
+	suite.Equal(1, suite.count.binds)
+
+	suite.count.code = framework.Skip
+	sts = suite.fwk.RunBindPlugins(context.TODO(), suite.desc, nil, nil)
+	suite.Equal(framework.Skip, sts.Code())
+}
+
+func TestBindingSuite(t *testing.T) {
+	suite.Run(t, new(BindingSuite))
+}
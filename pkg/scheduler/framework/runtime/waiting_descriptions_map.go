@@ -0,0 +1,152 @@
+// This file was copied from k8s.io/kubernetes/pkg/scheduler/framework/runtime/waiting_pods_map.go and modified
+
+package runtime
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	appsapi "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	framework "github.com/lmxia/gaia/pkg/scheduler/framework/interfaces"
+)
+
+// waitingDescriptionsMap a thread-safe map used to maintain descriptions waiting in the permit phase.
+type waitingDescriptionsMap struct {
+	descs map[types.UID]*waitingDescription
+	mu    sync.RWMutex
+}
+
+// newWaitingDescriptionsMap returns a new waitingDescriptionsMap.
+func newWaitingDescriptionsMap() *waitingDescriptionsMap {
+	return &waitingDescriptionsMap{
+		descs: make(map[types.UID]*waitingDescription),
+	}
+}
+
+// add a new WaitingDescription to the map.
+func (m *waitingDescriptionsMap) add(wd *waitingDescription) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.descs[wd.GetDescription().UID] = wd
+}
+
+// remove a WaitingDescription from the map.
+func (m *waitingDescriptionsMap) remove(uid types.UID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.descs, uid)
+}
+
+// get a WaitingDescription from the map.
+func (m *waitingDescriptionsMap) get(uid types.UID) *waitingDescription {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.descs[uid]
+}
+
+// iterate acquires a read lock and iterates over the WaitingDescriptions map.
+func (m *waitingDescriptionsMap) iterate(callback func(framework.WaitingSubscription)) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, v := range m.descs {
+		callback(v)
+	}
+}
+
+// waitingDescription represents a description waiting in the permit phase.
+type waitingDescription struct {
+	desc           *appsapi.Description
+	pendingPlugins map[string]*time.Timer
+	s              chan *framework.Status
+	mu             sync.RWMutex
+}
+
+var _ framework.WaitingSubscription = &waitingDescription{}
+
+// newWaitingDescription returns a new waitingDescription instance.
+func newWaitingDescription(desc *appsapi.Description, pluginsMaxWait map[string]time.Duration) *waitingDescription {
+	wd := &waitingDescription{
+		desc: desc,
+		// Allow() and Reject() calls are non-blocking. This property is guaranteed
+		// by using non-blocking send to this channel. This channel has a buffer of size 1
+		// to ensure that non-blocking send will not be ignored - possible situation when
+		// receiving from this channel happens after non-blocking send.
+		s: make(chan *framework.Status, 1),
+	}
+
+	wd.pendingPlugins = make(map[string]*time.Timer, len(pluginsMaxWait))
+	// The time.AfterFunc calls wd.Reject which iterates through pendingPlugins map. Acquire the
+	// lock here so that time.AfterFunc can only execute after newWaitingDescription finishes.
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	for k, v := range pluginsMaxWait {
+		plugin, waitTime := k, v
+		wd.pendingPlugins[plugin] = time.AfterFunc(waitTime, func() {
+			msg := fmt.Sprintf("rejected due to timeout after waiting %v at plugin %v",
+				waitTime, plugin)
+			wd.Reject(plugin, msg)
+		})
+	}
+
+	return wd
+}
+
+// GetDescription returns a reference to the waiting description.
+func (w *waitingDescription) GetDescription() *appsapi.Description {
+	return w.desc
+}
+
+// GetPendingPlugins returns a list of pending permit plugin's name.
+func (w *waitingDescription) GetPendingPlugins() []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	plugins := make([]string, 0, len(w.pendingPlugins))
+	for p := range w.pendingPlugins {
+		plugins = append(plugins, p)
+	}
+
+	return plugins
+}
+
+// Allow declares the waiting description is allowed to be scheduled by plugin pluginName.
+// If this is the last remaining plugin to allow, then a success signal is delivered
+// to unblock the description.
+func (w *waitingDescription) Allow(pluginName string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if timer, exist := w.pendingPlugins[pluginName]; exist {
+		timer.Stop()
+		delete(w.pendingPlugins, pluginName)
+	}
+
+	// Only signal success status after all plugins have allowed
+	if len(w.pendingPlugins) != 0 {
+		return
+	}
+
+	// The select clause works as a non-blocking send.
+	// If there is no receiver, it's a no-op (default case).
+	select {
+	case w.s <- framework.NewStatus(framework.Success, ""):
+	default:
+	}
+}
+
+// Reject declares the waiting description unschedulable.
+func (w *waitingDescription) Reject(pluginName, msg string) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	for _, timer := range w.pendingPlugins {
+		timer.Stop()
+	}
+
+	// The select clause works as a non-blocking send.
+	// If there is no receiver, it's a no-op (default case).
+	select {
+	case w.s <- framework.NewStatus(framework.Unschedulable, msg).WithFailedPlugin(pluginName):
+	default:
+	}
+}
@@ -1,6 +1,10 @@
 package framework
 
 import (
+	"math"
+
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/lmxia/gaia/pkg/apis/platform/v1alpha1"
 )
 
@@ -11,13 +15,43 @@ type ClusterInfo struct {
 	Total   int64
 }
 
-// pod is used to check affinity, TODO.
-func (c *ClusterInfo) CalculateCapacity(cpuRequest int64, memRequest int64) int64 {
-	freeCpuLeft := c.Cluster.Status.Available.Cpu().MilliValue()
-	freeMemLeft := c.Cluster.Status.Available.Memory().Value()
-	cpuCapacity := freeCpuLeft / cpuRequest
-	memCapacity := freeMemLeft / memRequest
-	return minInt64(cpuCapacity, memCapacity)
+// CalculateCapacity returns how many replicas with requests fit in the available resources of the cluster,
+// the scarcest requested resource decides. A replica requesting more than the largest free node reported
+// by the cluster fits nowhere. A non-leaf cluster holds no more than its leaf clusters hold one by one,
+// however big the aggregated resources are. pod is used to check affinity, TODO.
+func (c *ClusterInfo) CalculateCapacity(requests corev1.ResourceList) int64 {
+	status := c.Cluster.Status
+	capacity := calculateCapacity(requests, status.Available, status.MaxNodeAvailable)
+	if len(status.ClusterResources) == 0 {
+		return capacity
+	}
+
+	leafCapacity := int64(0)
+	for _, item := range status.ClusterResources {
+		leafCapacity += calculateCapacity(requests, item.Available, item.MaxNodeAvailable)
+	}
+	return minInt64(capacity, leafCapacity)
+}
+
+// calculateCapacity returns how many replicas with requests fit in available.
+func calculateCapacity(requests, available, maxNodeAvailable corev1.ResourceList) int64 {
+	capacity := int64(math.MaxInt32)
+	for name, request := range requests {
+		if request.IsZero() {
+			continue
+		}
+		if nodeAvailable, ok := maxNodeAvailable[name]; ok && request.Cmp(nodeAvailable) > 0 {
+			return 0
+		}
+		// a cluster that doesn't report a resource has none of it.
+		free := available[name]
+		if name == corev1.ResourceCPU {
+			capacity = minInt64(capacity, free.MilliValue()/request.MilliValue())
+		} else {
+			capacity = minInt64(capacity, free.Value()/request.Value())
+		}
+	}
+	return capacity
 }
 
 func minInt64(a int64, b int64) int64 {
@@ -0,0 +1,186 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	appsapi "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	"github.com/lmxia/gaia/pkg/common"
+	gaiaClientSet "github.com/lmxia/gaia/pkg/generated/clientset/versioned"
+	"github.com/lmxia/gaia/pkg/scheduler/algorithm"
+	internalqueue "github.com/lmxia/gaia/pkg/scheduler/queue"
+)
+
+// unschedulableReason returns the reason recorded in the DescriptionScheduled condition for a schedule error.
+func unschedulableReason(err error) string {
+	if errors.Is(err, algorithm.ErrGangUnschedulable) {
+		return ReasonGangUnschedulable
+	}
+	return ReasonUnschedulable
+}
+
+// gangFailed returns true if desc is the copy of a gang description in the namespace of a child cluster,
+// and the child cluster can't place its share.
+func gangFailed(desc *appsapi.Description) bool {
+	return desc.Namespace != common.GaiaReservedNamespace && desc.DeletionTimestamp == nil &&
+		desc.Spec.GangScheduling != nil && desc.Status.Phase == appsapi.DescriptionPhaseFailure
+}
+
+// gangFailureCondition returns the DescriptionScheduled condition of a gang description whose share can't be
+// placed by clusterName, the scheduling message of the child cluster is kept.
+func gangFailureCondition(desc, failed *appsapi.Description, clusterName string) metav1.Condition {
+	message := fmt.Sprintf("cluster %q can't place its share of the gang", clusterName)
+	if cond := apimeta.FindStatusCondition(failed.Status.Conditions, appsapi.DescriptionConditionScheduled); cond != nil && len(cond.Message) > 0 {
+		message = fmt.Sprintf("%s: %s", message, cond.Message)
+	}
+	return metav1.Condition{
+		Type:               appsapi.DescriptionConditionScheduled,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: desc.Generation,
+		Reason:             ReasonGangUnschedulable,
+		Message:            truncateMessage(message),
+	}
+}
+
+// RunGangWithdrawal withdraws the next gang description in the queue whose share a child cluster can't place.
+func (sched *Scheduler) RunGangWithdrawal(ctx context.Context) {
+	key, shutdown := sched.gangQueue.Get()
+	if shutdown {
+		klog.Error("failed to get next failed gang description from closed queue")
+		return
+	}
+	defer sched.gangQueue.Done(key)
+
+	ns, name, err := cache.SplitMetaNamespaceKey(key.(string))
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		sched.gangQueue.Forget(key)
+		return
+	}
+	failed, err := sched.localDescLister.Descriptions(ns).Get(name)
+	if err != nil || !gangFailed(failed) {
+		// the gang has been withdrawn already.
+		sched.gangQueue.Forget(key)
+		return
+	}
+
+	if err = sched.withdrawGang(ctx, failed); err != nil {
+		klog.ErrorS(err, "Failed to withdraw gang description", "description", klog.KObj(failed))
+		sched.gangQueue.AddRateLimited(key)
+		return
+	}
+	sched.gangQueue.Forget(key)
+}
+
+// withdrawGang deletes the copies and the resource bindings of a gang description from the namespaces of all
+// child clusters, because the child cluster of failed can't place its share. Then the gang description is failed
+// and the child cluster is recorded, so the next attempts leave it out. A description of this cluster is scheduled
+// again after a backoff, a description of the parent cluster is left for the parent.
+func (sched *Scheduler) withdrawGang(ctx context.Context, failed *appsapi.Description) error {
+	desc, client, err := sched.gangOwner(failed.Name)
+	if apierrors.IsNotFound(err) {
+		klog.V(4).InfoS("Gang description is gone, nothing to withdraw", "failed", klog.KObj(failed))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	klog.InfoS("Withdrawing gang description from all child clusters", "description", klog.KObj(desc), "failed", klog.KObj(failed))
+
+	mcls, err := sched.localGaiaClient.PlatformV1alpha1().ManagedClusters(corev1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	clusterName := failed.Namespace
+	for _, mcl := range mcls.Items {
+		if mcl.Namespace == failed.Namespace {
+			clusterName = mcl.Name
+		}
+		err = sched.localGaiaClient.AppsV1alpha1().ResourceBindings(mcl.Namespace).
+			DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: labels.SelectorFromSet(labels.Set{
+				common.GaiaDescriptionLabel: desc.Name,
+			}).String()})
+		if err != nil {
+			return err
+		}
+		err = sched.localGaiaClient.AppsV1alpha1().Descriptions(mcl.Namespace).Delete(ctx, desc.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	descKey := klog.KObj(desc).String()
+	if err = sched.schedulerCache.ForgetDescription(descKey); err != nil {
+		klog.V(4).InfoS("No assumption to forget for gang description", "description", descKey, "err", err)
+	}
+
+	desc = desc.DeepCopy()
+	apimeta.SetStatusCondition(&desc.Status.Conditions, gangFailureCondition(desc, failed, clusterName))
+	if !sets.NewString(desc.Status.GangFailedClusters...).Has(clusterName) {
+		desc.Status.GangFailedClusters = append(desc.Status.GangFailedClusters, clusterName)
+	}
+	desc.Status.Phase = appsapi.DescriptionPhaseFailure
+	if _, err = client.AppsV1alpha1().Descriptions(desc.Namespace).UpdateStatus(ctx, desc, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	sched.eventRecorder.Eventf(desc, corev1.EventTypeWarning, ReasonGangUnschedulable,
+		"Withdrawn from all clusters because cluster %q can't place its share", clusterName)
+
+	if desc.Namespace == common.GaiaReservedNamespace {
+		sched.lockLocal.Lock()
+		defer sched.lockLocal.Unlock()
+		// the backoff grows with every cluster that failed the gang.
+		sched.localSchedulingQueue.AddBackoff(&internalqueue.QueuedDescriptionInfo{
+			Key:               descKey,
+			Priority:          desc.Spec.Priority,
+			CreationTimestamp: desc.CreationTimestamp.Time,
+			Attempts:          len(desc.Status.GangFailedClusters),
+		})
+	}
+	return nil
+}
+
+// gangOwner returns the description named name this cluster has scheduled, from the reserved namespace or
+// from the dedicated namespace in the parent cluster, with the client to update it.
+func (sched *Scheduler) gangOwner(name string) (*appsapi.Description, gaiaClientSet.Interface, error) {
+	desc, err := sched.localDescLister.Descriptions(common.GaiaReservedNamespace).Get(name)
+	if err == nil {
+		return desc, sched.localGaiaClient, nil
+	}
+	if !apierrors.IsNotFound(err) || sched.parentDescriptionLister == nil {
+		return nil, nil, err
+	}
+	desc, err = sched.parentDescriptionLister.Descriptions(sched.dedicatedNamespace).Get(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return desc, sched.parentGaiaClient, nil
+}
+
+// addGangEventHandlers enqueues the copies of gang descriptions that child clusters fail to schedule.
+func (sched *Scheduler) addGangEventHandlers() {
+	sched.localGaiaAllFactory.Apps().V1alpha1().Descriptions().Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			desc, ok := obj.(*appsapi.Description)
+			return ok && gangFailed(desc)
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			// a copy turning failed is added to the filtered handler, a failed copy that is still there
+			// is retried by the queue.
+			AddFunc: func(obj interface{}) {
+				sched.gangQueue.Add(klog.KObj(obj.(*appsapi.Description)).String())
+			},
+		},
+	})
+}
@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsapi "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	"github.com/lmxia/gaia/pkg/scheduler/algorithm"
+)
+
+type GangSuite struct {
+	failed *appsapi.Description
+	suite.Suite
+}
+
+func (suite *GangSuite) SetupTest() {
+	suite.failed = &appsapi.Description{
+		ObjectMeta: metav1.ObjectMeta{Name: "desc", Namespace: "gaia-c1"},
+		Spec:       appsapi.DescriptionSpec{GangScheduling: &appsapi.GangScheduling{}},
+		Status: appsapi.DescriptionStatus{
+			Phase: appsapi.DescriptionPhaseFailure,
+			Conditions: []metav1.Condition{{
+				Type:    appsapi.DescriptionConditionScheduled,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonGangUnschedulable,
+				Message: "no gpu",
+			}},
+		},
+	}
+}
+
+func (suite *GangSuite) TestGangFailed() {
+	suite.True(gangFailed(suite.failed))
+
+	reserved := suite.failed.DeepCopy()
+	reserved.Namespace = "gaia-reserved"
+	suite.False(gangFailed(reserved), "the description of this cluster is not a copy")
+
+	scheduled := suite.failed.DeepCopy()
+	scheduled.Status.Phase = appsapi.DescriptionPhaseScheduled
+	suite.False(gangFailed(scheduled))
+
+	noGang := suite.failed.DeepCopy()
+	noGang.Spec.GangScheduling = nil
+	suite.False(gangFailed(noGang), "a description without gang is left to its child cluster")
+}
+
+func (suite *GangSuite) TestGangFailureCondition() {
+	desc := &appsapi.Description{ObjectMeta: metav1.ObjectMeta{Name: "desc", Namespace: "gaia-reserved", Generation: 4}}
+	condition := gangFailureCondition(desc, suite.failed, "c1")
+	suite.Equal(metav1.ConditionFalse, condition.Status)
+	suite.Equal(ReasonGangUnschedulable, condition.Reason)
+	suite.Equal(int64(4), condition.ObservedGeneration)
+	suite.Equal(`cluster "c1" can't place its share of the gang: no gpu`, condition.Message)
+}
+
+func (suite *GangSuite) TestUnschedulableReason() {
+	suite.Equal(ReasonGangUnschedulable, unschedulableReason(fmt.Errorf("retry: %w", algorithm.ErrGangUnschedulable)))
+	suite.Equal(ReasonUnschedulable, unschedulableReason(algorithm.ErrInfeasiblePlan))
+	suite.Equal(ReasonUnschedulable, unschedulableReason(errors.New("boom")))
+}
+
+func TestGang(t *testing.T) {
+	suite.Run(t, new(GangSuite))
+}
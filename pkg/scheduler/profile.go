@@ -0,0 +1,59 @@
+// This file was copied from k8s.io/kubernetes/pkg/scheduler/profile/profile.go and modified
+
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+
+	appsapi "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	schedulerapis "github.com/lmxia/gaia/pkg/scheduler/apis"
+	framework "github.com/lmxia/gaia/pkg/scheduler/framework/interfaces"
+	frameworkruntime "github.com/lmxia/gaia/pkg/scheduler/framework/runtime"
+)
+
+// profileMap holds frameworks indexed by scheduler name.
+type profileMap map[string]framework.Framework
+
+// newProfileMap builds a profile map given configuration profiles.
+func newProfileMap(cfgs []schedulerapis.SchedulerProfile, r frameworkruntime.Registry,
+	opts ...frameworkruntime.Option) (profileMap, error) {
+	m := make(profileMap)
+	for i := range cfgs {
+		if _, ok := m[cfgs[i].SchedulerName]; ok {
+			return nil, fmt.Errorf("duplicate profile with scheduler name %q", cfgs[i].SchedulerName)
+		}
+		fwk, err := frameworkruntime.NewFramework(r, &cfgs[i], opts...)
+		if err != nil {
+			return nil, fmt.Errorf("creating profile for scheduler name %s: %v", cfgs[i].SchedulerName, err)
+		}
+		m[cfgs[i].SchedulerName] = fwk
+	}
+	if len(m) == 0 {
+		return nil, errors.New("at least one profile is required")
+	}
+	return m, nil
+}
+
+// schedulerName returns the profile name a description asks for.
+func schedulerName(desc *appsapi.Description) string {
+	if len(desc.Spec.SchedulerName) == 0 {
+		return schedulerapis.DefaultSchedulerName
+	}
+	return desc.Spec.SchedulerName
+}
+
+// HandlesDescription returns true if one of the profiles can schedule the description.
+func (m profileMap) HandlesDescription(desc *appsapi.Description) bool {
+	_, ok := m[schedulerName(desc)]
+	return ok
+}
+
+// frameworkForDescription returns the framework of the profile the description asks for.
+func (m profileMap) frameworkForDescription(desc *appsapi.Description) (framework.Framework, error) {
+	fwk, ok := m[schedulerName(desc)]
+	if !ok {
+		return nil, fmt.Errorf("profile not found for scheduler name %q", schedulerName(desc))
+	}
+	return fwk, nil
+}
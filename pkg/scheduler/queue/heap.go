@@ -0,0 +1,89 @@
+package queue
+
+import (
+	"container/heap"
+)
+
+// lessFunc reports whether info1 goes before info2.
+type lessFunc func(info1, info2 *QueuedDescriptionInfo) bool
+
+// descHeap is a heap of queued descriptions indexed by their keys, so an entry can be updated or deleted
+// in place.
+type descHeap struct {
+	items []*QueuedDescriptionInfo
+	index map[string]int
+	less  lessFunc
+}
+
+func newDescHeap(less lessFunc) *descHeap {
+	return &descHeap{index: make(map[string]int), less: less}
+}
+
+// Len, Less, Swap, Push and Pop implement heap.Interface, use the exported helpers below instead.
+func (h *descHeap) Len() int { return len(h.items) }
+
+func (h *descHeap) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+
+func (h *descHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.index[h.items[i].Key] = i
+	h.index[h.items[j].Key] = j
+}
+
+func (h *descHeap) Push(x interface{}) {
+	info := x.(*QueuedDescriptionInfo)
+	h.index[info.Key] = len(h.items)
+	h.items = append(h.items, info)
+}
+
+func (h *descHeap) Pop() interface{} {
+	n := len(h.items)
+	info := h.items[n-1]
+	h.items[n-1] = nil
+	h.items = h.items[:n-1]
+	delete(h.index, info.Key)
+	return info
+}
+
+// AddOrUpdate adds info, or replaces the entry with the same key.
+func (h *descHeap) AddOrUpdate(info *QueuedDescriptionInfo) {
+	if i, ok := h.index[info.Key]; ok {
+		h.items[i] = info
+		heap.Fix(h, i)
+		return
+	}
+	heap.Push(h, info)
+}
+
+// Get returns the entry of key, or nil if there is none.
+func (h *descHeap) Get(key string) *QueuedDescriptionInfo {
+	if i, ok := h.index[key]; ok {
+		return h.items[i]
+	}
+	return nil
+}
+
+// Delete removes the entry of key and returns it, or nil if there is none.
+func (h *descHeap) Delete(key string) *QueuedDescriptionInfo {
+	i, ok := h.index[key]
+	if !ok {
+		return nil
+	}
+	return heap.Remove(h, i).(*QueuedDescriptionInfo)
+}
+
+// Peek returns the first entry without removing it, or nil if the heap is empty.
+func (h *descHeap) Peek() *QueuedDescriptionInfo {
+	if len(h.items) == 0 {
+		return nil
+	}
+	return h.items[0]
+}
+
+// PopFirst removes the first entry and returns it, or nil if the heap is empty.
+func (h *descHeap) PopFirst() *QueuedDescriptionInfo {
+	if len(h.items) == 0 {
+		return nil
+	}
+	return heap.Pop(h).(*QueuedDescriptionInfo)
+}
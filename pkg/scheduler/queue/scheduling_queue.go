@@ -0,0 +1,316 @@
+package queue
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	appsapi "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+)
+
+const (
+	// DefaultDescriptionInitialBackoffDuration is the backoff of a description after its first failed attempt.
+	DefaultDescriptionInitialBackoffDuration = 1 * time.Second
+	// DefaultDescriptionMaxBackoffDuration is the longest backoff of a description, however often it fails.
+	DefaultDescriptionMaxBackoffDuration = 10 * time.Second
+	// DefaultDescriptionMaxInUnschedulableDuration is the longest a description stays in the unschedulable pool
+	// without a cluster event, in case it becomes schedulable by a change no event is watched for.
+	DefaultDescriptionMaxInUnschedulableDuration = 5 * time.Minute
+
+	backoffFlushPeriod       = 1 * time.Second
+	unschedulableFlushPeriod = 30 * time.Second
+)
+
+// SchedulingQueue holds the keys of the descriptions waiting to be scheduled. Descriptions are scheduled in the
+// order of their priority and age. A description that fails to be scheduled waits in the backoff queue for
+// a time that doubles with every attempt, or in the unschedulable pool until a cluster event might make it
+// schedulable.
+type SchedulingQueue interface {
+	// Add adds desc to the active queue. As desc is added when it is created or its spec changes, it is moved
+	// out of the backoff queue and the unschedulable pool. A description being scheduled is added again once
+	// it is done.
+	Add(desc *appsapi.Description)
+	// Pop blocks until a description is active and returns it, the second value is true if the queue is
+	// closed. Done must be called with its key once the attempt is over.
+	Pop() (*QueuedDescriptionInfo, bool)
+	// Done marks the attempt to schedule the description of key as over.
+	Done(key string)
+	// AddUnschedulable puts info, which the scheduling algorithm can't place, into the unschedulable pool and
+	// ends its attempt. It goes to the backoff queue instead if a cluster event has happened since it was popped.
+	AddUnschedulable(info *QueuedDescriptionInfo)
+	// AddBackoff puts info, which fails to be scheduled for other reasons than lack of clusters, into the
+	// backoff queue and ends its attempt. Info may also be made up for a description that failed after it was
+	// scheduled, which is added again once it is done if it is being scheduled.
+	AddBackoff(info *QueuedDescriptionInfo)
+	// MoveAllToActiveOrBackoffQueue moves all descriptions in the unschedulable pool to the active queue, or
+	// to the backoff queue if they are still backing off, on a cluster event that might make them schedulable.
+	MoveAllToActiveOrBackoffQueue(event string)
+	// Run starts the goroutines that flush the backoff queue and the unschedulable pool until stopCh is closed.
+	Run(stopCh <-chan struct{})
+	// Close closes the queue, Pop returns at once from then on.
+	Close()
+}
+
+// QueuedDescriptionInfo is a description in the scheduling queue.
+type QueuedDescriptionInfo struct {
+	// Key is the namespace/name key of the description.
+	Key string
+	// Priority is the scheduling priority of the description.
+	Priority int32
+	// CreationTimestamp is the creation time of the description, the older one of the same priority goes first.
+	CreationTimestamp time.Time
+	// Timestamp is the time the description was added to its current sub-queue.
+	Timestamp time.Time
+	// Attempts is the number of times the description has been popped.
+	Attempts int
+
+	// cycle is the scheduling cycle the description was popped in.
+	cycle int64
+}
+
+type priorityQueue struct {
+	clock clock.Clock
+
+	lock sync.Mutex
+	cond sync.Cond
+
+	initialBackoff     time.Duration
+	maxBackoff         time.Duration
+	maxInUnschedulable time.Duration
+
+	// activeQ holds the descriptions to be scheduled, ordered by priority and age.
+	activeQ *descHeap
+	// backoffQ holds the descriptions backing off, ordered by the end of their backoff.
+	backoffQ *descHeap
+	// unschedulable holds the descriptions waiting for a cluster event.
+	unschedulable map[string]*QueuedDescriptionInfo
+	// inFlight holds the descriptions being scheduled, dirty the ones of them added again meanwhile.
+	inFlight map[string]*QueuedDescriptionInfo
+	dirty    sets.String
+
+	// schedulingCycle counts the descriptions popped, moveRequestCycle is the cycle of the last cluster event.
+	schedulingCycle  int64
+	moveRequestCycle int64
+
+	closed bool
+}
+
+var _ SchedulingQueue = &priorityQueue{}
+
+// New returns an empty scheduling queue with the default backoff.
+func New() SchedulingQueue {
+	q := &priorityQueue{
+		clock:              clock.RealClock{},
+		initialBackoff:     DefaultDescriptionInitialBackoffDuration,
+		maxBackoff:         DefaultDescriptionMaxBackoffDuration,
+		maxInUnschedulable: DefaultDescriptionMaxInUnschedulableDuration,
+		activeQ:            newDescHeap(activeLess),
+		unschedulable:      make(map[string]*QueuedDescriptionInfo),
+		inFlight:           make(map[string]*QueuedDescriptionInfo),
+		dirty:              sets.NewString(),
+		moveRequestCycle:   -1,
+	}
+	q.backoffQ = newDescHeap(func(info1, info2 *QueuedDescriptionInfo) bool {
+		return q.backoffTime(info1).Before(q.backoffTime(info2))
+	})
+	q.cond.L = &q.lock
+	return q
+}
+
+// activeLess orders descriptions by priority, then by age.
+func activeLess(info1, info2 *QueuedDescriptionInfo) bool {
+	if info1.Priority != info2.Priority {
+		return info1.Priority > info2.Priority
+	}
+	return info1.CreationTimestamp.Before(info2.CreationTimestamp)
+}
+
+func (q *priorityQueue) Add(desc *appsapi.Description) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	key := klog.KObj(desc).String()
+	info := q.inFlight[key]
+	if info != nil {
+		info.Priority = desc.Spec.Priority
+		q.dirty.Insert(key)
+		return
+	}
+
+	if info = q.activeQ.Get(key); info == nil {
+		if info = q.backoffQ.Delete(key); info == nil {
+			if info = q.unschedulable[key]; info != nil {
+				delete(q.unschedulable, key)
+			} else {
+				info = &QueuedDescriptionInfo{Key: key, CreationTimestamp: desc.CreationTimestamp.Time}
+			}
+		}
+		info.Timestamp = q.clock.Now()
+	}
+	info.Priority = desc.Spec.Priority
+	q.activeQ.AddOrUpdate(info)
+	q.cond.Broadcast()
+}
+
+func (q *priorityQueue) Pop() (*QueuedDescriptionInfo, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for q.activeQ.Len() == 0 && !q.closed {
+		q.cond.Wait()
+	}
+	if q.closed {
+		return nil, true
+	}
+	info := q.activeQ.PopFirst()
+	info.Attempts++
+	q.schedulingCycle++
+	info.cycle = q.schedulingCycle
+	q.inFlight[info.Key] = info
+	return info, false
+}
+
+func (q *priorityQueue) Done(key string) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	info, ok := q.inFlight[key]
+	if !ok {
+		return
+	}
+	delete(q.inFlight, key)
+	if q.dirty.Has(key) {
+		q.dirty.Delete(key)
+		q.activate(info)
+	}
+}
+
+func (q *priorityQueue) AddUnschedulable(info *QueuedDescriptionInfo) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	delete(q.inFlight, info.Key)
+	if q.requeueDirty(info) {
+		return
+	}
+	info.Timestamp = q.clock.Now()
+	if q.moveRequestCycle >= info.cycle {
+		q.backoffQ.AddOrUpdate(info)
+		return
+	}
+	q.unschedulable[info.Key] = info
+}
+
+func (q *priorityQueue) AddBackoff(info *QueuedDescriptionInfo) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if current, ok := q.inFlight[info.Key]; ok && current != info {
+		q.dirty.Insert(info.Key)
+		return
+	}
+	if q.activeQ.Get(info.Key) != nil {
+		return
+	}
+	delete(q.inFlight, info.Key)
+	delete(q.unschedulable, info.Key)
+	if q.requeueDirty(info) {
+		return
+	}
+	info.Timestamp = q.clock.Now()
+	q.backoffQ.AddOrUpdate(info)
+}
+
+// requeueDirty activates info at once if its description has been added again while it was scheduled.
+func (q *priorityQueue) requeueDirty(info *QueuedDescriptionInfo) bool {
+	if !q.dirty.Has(info.Key) {
+		return false
+	}
+	q.dirty.Delete(info.Key)
+	q.activate(info)
+	return true
+}
+
+func (q *priorityQueue) MoveAllToActiveOrBackoffQueue(event string) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if len(q.unschedulable) > 0 {
+		klog.V(4).InfoS("Moving unschedulable descriptions on cluster event", "event", event, "descriptions", len(q.unschedulable))
+	}
+	for key, info := range q.unschedulable {
+		delete(q.unschedulable, key)
+		q.moveOut(info)
+	}
+	q.moveRequestCycle = q.schedulingCycle
+}
+
+func (q *priorityQueue) Run(stopCh <-chan struct{}) {
+	go wait.Until(q.flushBackoffQCompleted, backoffFlushPeriod, stopCh)
+	go wait.Until(q.flushUnschedulableLeftover, unschedulableFlushPeriod, stopCh)
+}
+
+func (q *priorityQueue) Close() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.closed = true
+	q.cond.Broadcast()
+}
+
+// flushBackoffQCompleted moves the descriptions whose backoff is over to the active queue.
+func (q *priorityQueue) flushBackoffQCompleted() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	now := q.clock.Now()
+	for info := q.backoffQ.Peek(); info != nil && !q.backoffTime(info).After(now); info = q.backoffQ.Peek() {
+		q.activate(q.backoffQ.PopFirst())
+	}
+}
+
+// flushUnschedulableLeftover moves the descriptions that have been unschedulable for too long out of the pool.
+func (q *priorityQueue) flushUnschedulableLeftover() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	now := q.clock.Now()
+	for key, info := range q.unschedulable {
+		if now.Sub(info.Timestamp) > q.maxInUnschedulable {
+			delete(q.unschedulable, key)
+			q.moveOut(info)
+		}
+	}
+}
+
+// moveOut moves info out of the unschedulable pool, into the backoff queue if it is still backing off.
+func (q *priorityQueue) moveOut(info *QueuedDescriptionInfo) {
+	if q.backoffTime(info).After(q.clock.Now()) {
+		q.backoffQ.AddOrUpdate(info)
+		return
+	}
+	q.activate(info)
+}
+
+func (q *priorityQueue) activate(info *QueuedDescriptionInfo) {
+	info.Timestamp = q.clock.Now()
+	q.activeQ.AddOrUpdate(info)
+	q.cond.Broadcast()
+}
+
+// backoffTime returns the end of the backoff of info, which doubles with every attempt up to the max backoff.
+func (q *priorityQueue) backoffTime(info *QueuedDescriptionInfo) time.Time {
+	duration := q.initialBackoff
+	for i := 1; i < info.Attempts; i++ {
+		duration *= 2
+		if duration >= q.maxBackoff {
+			duration = q.maxBackoff
+			break
+		}
+	}
+	return info.Timestamp.Add(duration)
+}
@@ -0,0 +1,157 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	appsapi "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+)
+
+type QueueSuite struct {
+	clock *clocktesting.FakeClock
+	queue *priorityQueue
+	suite.Suite
+}
+
+func (suite *QueueSuite) SetupTest() {
+	suite.clock = clocktesting.NewFakeClock(time.Now())
+	suite.queue = New().(*priorityQueue)
+	suite.queue.clock = suite.clock
+}
+
+func (suite *QueueSuite) newDescription(name string, priority int32, age time.Duration) *appsapi.Description {
+	return &appsapi.Description{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "gaia-reserved",
+			CreationTimestamp: metav1.NewTime(suite.clock.Now().Add(-age)),
+		},
+		Spec: appsapi.DescriptionSpec{Priority: priority},
+	}
+}
+
+func (suite *QueueSuite) pop() *QueuedDescriptionInfo {
+	info, closed := suite.queue.Pop()
+	suite.False(closed)
+	return info
+}
+
+func (suite *QueueSuite) TestPriorityAndAge() {
+	suite.queue.Add(suite.newDescription("young", 0, time.Minute))
+	suite.queue.Add(suite.newDescription("old", 0, time.Hour))
+	suite.queue.Add(suite.newDescription("urgent", 10, 0))
+
+	suite.Equal("gaia-reserved/urgent", suite.pop().Key)
+	suite.Equal("gaia-reserved/old", suite.pop().Key)
+	suite.Equal("gaia-reserved/young", suite.pop().Key)
+}
+
+func (suite *QueueSuite) TestBackoff() {
+	suite.queue.Add(suite.newDescription("desc", 0, 0))
+	for _, backoff := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		info := suite.pop()
+		suite.queue.AddBackoff(info)
+		suite.queue.Done(info.Key)
+
+		suite.clock.Step(backoff - time.Millisecond)
+		suite.queue.flushBackoffQCompleted()
+		suite.Zero(suite.queue.activeQ.Len(), "backing off for %v", backoff)
+		suite.clock.Step(time.Millisecond)
+		suite.queue.flushBackoffQCompleted()
+		suite.Equal(1, suite.queue.activeQ.Len())
+	}
+
+	info := suite.pop()
+	info.Attempts = 100
+	suite.Equal(info.Timestamp.Add(DefaultDescriptionMaxBackoffDuration), suite.queue.backoffTime(info))
+}
+
+func (suite *QueueSuite) TestUnschedulable() {
+	suite.queue.Add(suite.newDescription("desc", 0, 0))
+	info := suite.pop()
+	suite.queue.AddUnschedulable(info)
+	suite.queue.Done(info.Key)
+	suite.Len(suite.queue.unschedulable, 1)
+
+	suite.clock.Step(time.Minute)
+	suite.queue.flushBackoffQCompleted()
+	suite.queue.flushUnschedulableLeftover()
+	suite.Zero(suite.queue.activeQ.Len(), "only cluster events move unschedulable descriptions")
+
+	suite.queue.MoveAllToActiveOrBackoffQueue("ManagedClusterAdd")
+	suite.Empty(suite.queue.unschedulable)
+	suite.Equal(1, suite.queue.activeQ.Len())
+
+	info = suite.pop()
+	suite.queue.MoveAllToActiveOrBackoffQueue("ManagedClusterUpdate")
+	suite.queue.AddUnschedulable(info)
+	suite.Empty(suite.queue.unschedulable, "a cluster event during the attempt sends it to backoff")
+	suite.Equal(1, suite.queue.backoffQ.Len())
+
+	suite.clock.Step(DefaultDescriptionMaxBackoffDuration)
+	suite.queue.flushBackoffQCompleted()
+	info = suite.pop()
+	suite.queue.AddUnschedulable(info)
+	suite.Len(suite.queue.unschedulable, 1)
+	suite.clock.Step(DefaultDescriptionMaxInUnschedulableDuration + time.Second)
+	suite.queue.flushUnschedulableLeftover()
+	suite.Equal(1, suite.queue.activeQ.Len(), "unless it has waited too long")
+}
+
+func (suite *QueueSuite) TestAddWhileScheduling() {
+	desc := suite.newDescription("desc", 0, 0)
+	suite.queue.Add(desc)
+	info := suite.pop()
+
+	suite.queue.Add(desc)
+	suite.Zero(suite.queue.activeQ.Len(), "a description is never scheduled twice at the same time")
+	suite.queue.AddUnschedulable(info)
+	suite.queue.Done(info.Key)
+	suite.Empty(suite.queue.unschedulable, "the new spec is tried at once")
+	suite.Equal(1, suite.queue.activeQ.Len())
+
+	info = suite.pop()
+	suite.queue.Add(desc)
+	suite.queue.Done(info.Key)
+	suite.Equal(1, suite.queue.activeQ.Len())
+}
+
+func (suite *QueueSuite) TestBackoffAfterScheduled() {
+	desc := suite.newDescription("desc", 0, 0)
+	info := &QueuedDescriptionInfo{Key: "gaia-reserved/desc", Attempts: 2}
+	suite.queue.AddBackoff(info)
+	suite.Zero(suite.queue.activeQ.Len())
+	suite.clock.Step(2 * time.Second)
+	suite.queue.flushBackoffQCompleted()
+	suite.Equal(1, suite.queue.activeQ.Len(), "the backoff follows the attempts")
+
+	popped := suite.pop()
+	suite.queue.AddBackoff(&QueuedDescriptionInfo{Key: popped.Key})
+	suite.Zero(suite.queue.backoffQ.Len(), "a description is never scheduled twice at the same time")
+	suite.queue.Done(popped.Key)
+	suite.Equal(1, suite.queue.activeQ.Len())
+
+	suite.queue.AddBackoff(&QueuedDescriptionInfo{Key: popped.Key})
+	suite.Zero(suite.queue.backoffQ.Len(), "an active description isn't backed off")
+	suite.queue.Add(desc)
+	suite.Equal(1, suite.queue.activeQ.Len())
+}
+
+func (suite *QueueSuite) TestClose() {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, closed := suite.queue.Pop()
+		suite.True(closed)
+	}()
+	suite.queue.Close()
+	<-done
+}
+
+func TestQueueSuite(t *testing.T) {
+	suite.Run(t, new(QueueSuite))
+}
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/lmxia/gaia/cmd/gaia-scheduler/app/option"
 	"github.com/lmxia/gaia/pkg/generated/listers/apps/v1alpha1"
@@ -32,6 +33,7 @@ import (
 	"github.com/lmxia/gaia/pkg/scheduler/framework/plugins"
 	frameworkruntime "github.com/lmxia/gaia/pkg/scheduler/framework/runtime"
 	"github.com/lmxia/gaia/pkg/scheduler/parallelize"
+	internalqueue "github.com/lmxia/gaia/pkg/scheduler/queue"
 	"github.com/lmxia/gaia/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -62,6 +64,14 @@ const (
 	// can't schedule the subscription right now, for example due to insufficient resources in the clusters.
 	ReasonUnschedulable = "Unschedulable"
 
+	// ReasonScheduled reason in DescriptionScheduled condition means that the description has been scheduled
+	// and its resource bindings have been published.
+	ReasonScheduled = "Scheduled"
+
+	// ReasonGangUnschedulable reason in DescriptionScheduled condition means that the components of a gang
+	// description can't be placed at the same time, by this cluster or by one of its child clusters.
+	ReasonGangUnschedulable = "GangUnschedulable"
+
 	// SchedulerError is the reason recorded for events when an error occurs during scheduling a subscription.
 	SchedulerError = "SchedulerError"
 )
@@ -92,17 +102,32 @@ type Scheduler struct {
 	registry frameworkruntime.Registry
 
 	scheduleAlgorithm algorithm.ScheduleAlgorithm
+	// schedulerCache is the cache the scheduleAlgorithm schedules against.
+	schedulerCache schedulercache.Cache
 
 	// localSchedulingQueue holds description in local namespace to be scheduled
-	localSchedulingQueue workqueue.RateLimitingInterface
+	localSchedulingQueue internalqueue.SchedulingQueue
 
 	// parentSchedulingQueue holds description in parent cluster namespace to be scheduled
-	parentSchedulingQueue workqueue.RateLimitingInterface
+	parentSchedulingQueue internalqueue.SchedulingQueue
 
 	// parentSchedulingRetryQueue holds description in parent cluster namespace to be re scheduled
-	parentSchedulingRetryQueue workqueue.RateLimitingInterface
+	parentSchedulingRetryQueue internalqueue.SchedulingQueue
+
+	// simulationQueue holds scheduling simulations whose status is outdated.
+	simulationQueue  workqueue.RateLimitingInterface
+	simulationLister listner.SchedulingSimulationLister
+
+	// gangQueue holds the copies of gang descriptions in child cluster namespaces that fail to be scheduled.
+	gangQueue workqueue.RateLimitingInterface
 
-	framework framework.Framework
+	// profiles are the scheduling frameworks indexed by scheduler name.
+	profiles profileMap
+
+	// workers is the number of descriptions each scheduling loop schedules at the same time.
+	workers int32
+
+	eventRecorder record.EventRecorder
 
 	lockLocal      sync.RWMutex
 	lockParent     sync.RWMutex
@@ -160,17 +185,23 @@ func NewSchedule(ctx context.Context, childKubeConfigFile string, opts *option.O
 		localGaiaClient:     childGaiaClientSet,
 		localGaiaAllFactory: localAllGaiaInformerFactory,
 		localDescLister:     localAllGaiaInformerFactory.Apps().V1alpha1().Descriptions().Lister(),
+		simulationLister:    localAllGaiaInformerFactory.Apps().V1alpha1().SchedulingSimulations().Lister(),
 		childKubeClientSet:  childKubeClientSet,
 
 		dynamicClient:              dynamicClient,
 		registry:                   plugins.NewInTreeRegistry(),
 		scheduleAlgorithm:          algorithm.NewGenericScheduler(schedulerCache),
-		localSchedulingQueue:       workqueue.NewRateLimitingQueue(workqueue.DefaultItemBasedRateLimiter()),
-		parentSchedulingQueue:      workqueue.NewRateLimitingQueue(workqueue.DefaultItemBasedRateLimiter()),
-		parentSchedulingRetryQueue: workqueue.NewRateLimitingQueue(workqueue.DefaultItemBasedRateLimiter()),
+		schedulerCache:             schedulerCache,
+		localSchedulingQueue:       internalqueue.New(),
+		parentSchedulingQueue:      internalqueue.New(),
+		parentSchedulingRetryQueue: internalqueue.New(),
+		simulationQueue:            workqueue.NewRateLimitingQueue(workqueue.DefaultItemBasedRateLimiter()),
+		gangQueue:                  workqueue.NewRateLimitingQueue(workqueue.DefaultItemBasedRateLimiter()),
+		eventRecorder:              recorder,
+		workers:                    cc.ComponentConfig.Workers,
 	}
 
-	framework, err := frameworkruntime.NewFramework(sched.registry, getDefaultPlugins(),
+	profiles, err := newProfileMap(cc.ComponentConfig.Profiles, sched.registry,
 		frameworkruntime.WithEventRecorder(recorder),
 		frameworkruntime.WithInformerFactory(localAllGaiaInformerFactory),
 		frameworkruntime.WithClientSet(childGaiaClientSet),
@@ -181,12 +212,15 @@ func NewSchedule(ctx context.Context, childKubeConfigFile string, opts *option.O
 	if err != nil {
 		return nil, nil, err
 	}
-	sched.framework = framework
+	sched.profiles = profiles
 	// local scheduler always exsit
 	sched.localNamespacedInformerFactory = gaiainformers.NewSharedInformerFactoryWithOptions(childGaiaClientSet, known.DefaultResync,
 		gaiainformers.WithNamespace(known.GaiaReservedNamespace))
 	// local event handler
 	sched.addLocalAllEventHandlers()
+	sched.addSimulationEventHandlers()
+	sched.addGangEventHandlers()
+	sched.addClusterEventHandlers()
 
 	metrics.Register()
 
@@ -195,7 +229,11 @@ func NewSchedule(ctx context.Context, childKubeConfigFile string, opts *option.O
 
 func (scheduler *Scheduler) Run(cxt context.Context, cc *schedulerserverconfig.CompletedConfig) {
 	klog.Info("starting gaia schedule scheduler ...")
-	defer scheduler.localSchedulingQueue.ShutDown()
+	defer scheduler.localSchedulingQueue.Close()
+	defer scheduler.parentSchedulingQueue.Close()
+	defer scheduler.parentSchedulingRetryQueue.Close()
+	defer scheduler.simulationQueue.ShutDown()
+	defer scheduler.gangQueue.ShutDown()
 
 	// start the leader election code loop
 	leaderelection.RunOrDie(context.TODO(), *newLeaderElectionConfigWithDefaultValue(scheduler.Identity, scheduler.childKubeClientSet, leaderelection.LeaderCallbacks{
@@ -206,14 +244,21 @@ func (scheduler *Scheduler) Run(cxt context.Context, cc *schedulerserverconfig.C
 
 			scheduler.localNamespacedInformerFactory.Start(ctx.Done())
 			scheduler.localNamespacedInformerFactory.WaitForCacheSync(ctx.Done())
+			scheduler.localSchedulingQueue.Run(ctx.Done())
+			scheduler.parentSchedulingQueue.Run(ctx.Done())
+			scheduler.parentSchedulingRetryQueue.Run(ctx.Done())
 			// 2. start local scheduler.
+			runWorkers(ctx, scheduler.workers, scheduler.RunLocalScheduler)
 			go func() {
-				wait.UntilWithContext(ctx, scheduler.RunLocalScheduler, 0)
+				wait.UntilWithContext(ctx, scheduler.RunSimulation, 0)
+			}()
+			go func() {
+				wait.UntilWithContext(ctx, scheduler.RunGangWithdrawal, 0)
 			}()
 
-			// metrics
+			// metrics and simulation
 			if cc.SecureServing != nil {
-				handler := buildHandlerChain(newMetricsHandler(), cc.Authentication.Authenticator, cc.Authorization.Authorizer)
+				handler := buildHandlerChain(newMetricsHandler(scheduler), cc.Authentication.Authenticator, cc.Authorization.Authorizer)
 				klog.Info("Starting gaia-scheduler metrics server...")
 				if _, err := cc.SecureServing.Serve(handler, 0, ctx.Done()); err != nil {
 					klog.Infof("failed to start metrics server: %v", err)
@@ -224,10 +269,9 @@ func (scheduler *Scheduler) Run(cxt context.Context, cc *schedulerserverconfig.C
 			scheduler.SetparentDedicatedConfig(ctx)
 			scheduler.parentInformerFactory.Start(ctx.Done())
 			scheduler.parentInformerFactory.WaitForCacheSync(ctx.Done())
-			go func() {
-				wait.UntilWithContext(ctx, scheduler.RunParentReScheduler, 0)
-			}()
-			wait.UntilWithContext(ctx, scheduler.RunParentScheduler, 0)
+			runWorkers(ctx, scheduler.workers, scheduler.RunParentReScheduler)
+			runWorkers(ctx, scheduler.workers, scheduler.RunParentScheduler)
+			<-ctx.Done()
 		},
 		OnStoppedLeading: func() {
 			klog.Error("leader election got lost")
@@ -244,6 +288,14 @@ func (scheduler *Scheduler) Run(cxt context.Context, cc *schedulerserverconfig.C
 	))
 }
 
+// runWorkers runs workers goroutines of the scheduling loop f until ctx is done. The scheduling queue never
+// hands the same description to two of them.
+func runWorkers(ctx context.Context, workers int32, f func(context.Context)) {
+	for i := int32(0); i < workers; i++ {
+		go wait.UntilWithContext(ctx, f, 0)
+	}
+}
+
 // buildHandlerChain wraps the given handler with the standard filters.
 func buildHandlerChain(handler http.Handler, authn authenticator.Request, authz authorizer.Authorizer) http.Handler {
 	requestInfoResolver := &apirequest.RequestInfoFactory{}
@@ -265,10 +317,11 @@ func installMetricHandler(pathRecorderMux *mux.PathRecorderMux) {
 
 }
 
-// newMetricsHandler builds a metrics server from the config.
-func newMetricsHandler() http.Handler {
+// newMetricsHandler builds a metrics server from the config, which serves scheduling simulations of sched as well.
+func newMetricsHandler(sched *Scheduler) http.Handler {
 	pathRecorderMux := mux.NewPathRecorderMux("gaia-scheduler")
 	installMetricHandler(pathRecorderMux)
+	installSimulationHandler(pathRecorderMux, sched)
 	return pathRecorderMux
 }
 
@@ -329,18 +382,18 @@ func NewLocalSuperKubeConfig(ctx context.Context, apiserverURL string, kubeClien
 }
 
 func (sched *Scheduler) RunLocalScheduler(ctx context.Context) {
-	key, shutdown := sched.localSchedulingQueue.Get()
+	info, shutdown := sched.localSchedulingQueue.Pop()
 	if shutdown {
 		klog.Error("failed to get next unscheduled description from closed queue")
 		return
 	}
-	defer sched.localSchedulingQueue.Done(key)
+	defer sched.localSchedulingQueue.Done(info.Key)
 
 	// TODO: scheduling
 	// Convert the namespace/name string into a distinct namespace and name
-	ns, name, err := cache.SplitMetaNamespaceKey(key.(string))
+	ns, name, err := cache.SplitMetaNamespaceKey(info.Key)
 	if err != nil {
-		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", info.Key))
 		return
 	}
 
@@ -349,7 +402,18 @@ func (sched *Scheduler) RunLocalScheduler(ctx context.Context) {
 		utilruntime.HandleError(err)
 		return
 	}
+	if desc.DeletionTimestamp != nil {
+		klog.V(4).InfoS("Skipping description being deleted", "description", klog.KObj(desc))
+		return
+	}
+	// the description of the lister is shared with the informer cache and the other workers.
+	desc = desc.DeepCopy()
 	klog.InfoS("Attempting to schedule description", "description", klog.KObj(desc))
+	fwk, err := sched.profiles.frameworkForDescription(desc)
+	if err != nil {
+		klog.ErrorS(err, "Error occurred", "description", klog.KObj(desc))
+		return
+	}
 
 	// Synchronously attempt to find a fit for the description.
 	start := time.Now()
@@ -357,9 +421,12 @@ func (sched *Scheduler) RunLocalScheduler(ctx context.Context) {
 	schedulingCycleCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	scheduleResult, err := sched.scheduleAlgorithm.Schedule(schedulingCycleCtx, sched.framework, nil, desc)
+	generation := sched.schedulerCache.Generation()
+	scheduleResult, err := sched.scheduleAlgorithm.Schedule(schedulingCycleCtx, fwk, nil, desc)
 	if err != nil {
-		sched.recordSchedulingFailure(desc, err, ReasonUnschedulable)
+		sched.preempt(schedulingCycleCtx, fwk, desc, err)
+		sched.recordSchedulingFailure(info, desc, err, ReasonUnschedulable)
+		setSchedulingStatus(desc, scheduleResult, err, unschedulableReason(err))
 		desc.Status.Phase = appsapi.DescriptionPhaseFailure
 		sched.localGaiaClient.AppsV1alpha1().Descriptions(known.GaiaReservedNamespace).UpdateStatus(ctx, desc, metav1.UpdateOptions{})
 		klog.Warningf("scheduler failed %v", err)
@@ -370,31 +437,29 @@ func (sched *Scheduler) RunLocalScheduler(ctx context.Context) {
 	if len(mcls.Items) == 0 {
 		klog.Warningf("scheduler success but do nothing because there is no child clusters.")
 	} else {
-		// 1. create rbs in sub children cluster namespace.
-		for _, itemCluster := range mcls.Items {
-			for rbIndex, itemRb := range scheduleResult.ResourceBindings {
-				itemRb.Name = fmt.Sprintf("%s-rs-%d", desc.Name, rbIndex)
-				itemRb.Namespace = itemCluster.Namespace
-				itemRb.Spec.TotalPeer = getTotal(itemRb.Spec.TotalPeer, len(scheduleResult.ResourceBindings))
-				_, err := sched.localGaiaClient.AppsV1alpha1().ResourceBindings(itemCluster.Namespace).
-					Create(ctx, itemRb, metav1.CreateOptions{})
-				if err != nil {
-					klog.Infof("scheduler success, but some rb not created success %v", err)
-				}
-			}
-			// 2. create desc in to child cluster namespace
-			newDesc := utils.ConstructDescriptionFromExistOne(desc)
-			newDesc.Namespace = itemCluster.Namespace
-			_, err := sched.localGaiaClient.AppsV1alpha1().Descriptions(itemCluster.Namespace).Create(ctx, newDesc, metav1.CreateOptions{})
-			if err != nil {
-				klog.InfoS("scheduler success, but desc not created success in sub child cluster.", err)
-			}
+		for rbIndex, itemRb := range scheduleResult.ResourceBindings {
+			itemRb.Name = fmt.Sprintf("%s-rs-%d", desc.Name, rbIndex)
+			itemRb.Spec.TotalPeer = getTotal(itemRb.Spec.TotalPeer, len(scheduleResult.ResourceBindings))
+		}
+		err = sched.bind(schedulingCycleCtx, fwk, desc, scheduleResult.ResourceBindings, mcls.Items, generation)
+		if conflicted(sched.localSchedulingQueue, desc, err) {
+			return
 		}
+		if err != nil {
+			sched.recordSchedulingFailure(info, desc, err, SchedulerError)
+			setSchedulingStatus(desc, scheduleResult, err, SchedulerError)
+			desc.Status.Phase = appsapi.DescriptionPhaseFailure
+			sched.localGaiaClient.AppsV1alpha1().Descriptions(known.GaiaReservedNamespace).UpdateStatus(ctx, desc, metav1.UpdateOptions{})
+			klog.Warningf("scheduler failed to bind %v", err)
+			return
+		}
+		setSchedulingStatus(desc, scheduleResult, nil, ReasonScheduled)
 		desc.Status.Phase = appsapi.DescriptionPhaseScheduled
+		desc.Status.NominatedClusters = nil
 		// TODO check if failed
 		sched.localGaiaClient.AppsV1alpha1().Descriptions(known.GaiaReservedNamespace).UpdateStatus(ctx, desc, metav1.UpdateOptions{})
 		metrics.SchedulingAlgorithmLatency.Observe(metrics.SinceInSeconds(start))
-		metrics.DescriptionScheduled(sched.framework.ProfileName(), metrics.SinceInSeconds(start))
+		metrics.DescriptionScheduled(fwk.ProfileName(), metrics.SinceInSeconds(start))
 		klog.Infof("scheduler success %v", scheduleResult)
 	}
 }
@@ -402,18 +467,18 @@ func (sched *Scheduler) RunLocalScheduler(ctx context.Context) {
 func (sched *Scheduler) RunParentScheduler(ctx context.Context) {
 	klog.Info("start to schedule one description...")
 	defer klog.Info("finish schedule a description")
-	key, shutdown := sched.parentSchedulingQueue.Get()
+	info, shutdown := sched.parentSchedulingQueue.Pop()
 	if shutdown {
 		klog.Error("failed to get next unscheduled description from closed queue")
 		return
 	}
-	defer sched.parentSchedulingQueue.Done(key)
+	defer sched.parentSchedulingQueue.Done(info.Key)
 
 	// TODO: scheduling
 	// Convert the namespace/name string into a distinct namespace and name
-	ns, name, err := cache.SplitMetaNamespaceKey(key.(string))
+	ns, name, err := cache.SplitMetaNamespaceKey(info.Key)
 	if err != nil {
-		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", info.Key))
 		return
 	}
 
@@ -422,7 +487,18 @@ func (sched *Scheduler) RunParentScheduler(ctx context.Context) {
 		utilruntime.HandleError(err)
 		return
 	}
+	if desc.DeletionTimestamp != nil {
+		klog.V(4).InfoS("Skipping description being deleted", "description", klog.KObj(desc))
+		return
+	}
+	// the description of the lister is shared with the informer cache and the other workers.
+	desc = desc.DeepCopy()
 	klog.InfoS("Attempting to schedule description", "description", klog.KObj(desc))
+	fwk, err := sched.profiles.frameworkForDescription(desc)
+	if err != nil {
+		klog.ErrorS(err, "Error occurred", "description", klog.KObj(desc))
+		return
+	}
 
 	// Synchronously attempt to find a fit for the description.
 	start := time.Now()
@@ -466,34 +542,33 @@ func (sched *Scheduler) RunParentScheduler(ctx context.Context) {
 			klog.Infof("faild to delete rbs in parent cluster", err)
 		}
 	} else {
-		scheduleResult, err := sched.scheduleAlgorithm.Schedule(schedulingCycleCtx, sched.framework, rbs, desc)
+		generation := sched.schedulerCache.Generation()
+		scheduleResult, err := sched.scheduleAlgorithm.Schedule(schedulingCycleCtx, fwk, rbs, desc)
 		if err != nil {
-			sched.recordParentSchedulingFailure(desc, err, ReasonUnschedulable)
+			sched.recordParentSchedulingFailure(info, desc, err, ReasonUnschedulable)
+			setSchedulingStatus(desc, scheduleResult, err, unschedulableReason(err))
 			desc.Status.Phase = appsapi.DescriptionPhaseFailure
 			sched.parentGaiaClient.AppsV1alpha1().Descriptions(sched.dedicatedNamespace).UpdateStatus(ctx, desc, metav1.UpdateOptions{})
 			klog.Warningf("scheduler failed %v", err)
 			return
 		}
 
-		for _, itemCluster := range mcls.Items {
-			for rbIndex, itemRb := range scheduleResult.ResourceBindings {
-				itemRb.Namespace = itemCluster.Namespace
-				itemRb.Name = fmt.Sprintf("%s-rs-%d", desc.Name, rbIndex)
-				// itemRb.Spec.TotalPeer = len(scheduleResult.ResourceBindings)
-				rb, err := sched.localGaiaClient.AppsV1alpha1().ResourceBindings(itemCluster.Namespace).
-					Create(ctx, itemRb, metav1.CreateOptions{})
-				if err != nil {
-					klog.V(3).InfoS("scheduler success, but some rb not created success", rb)
-				}
-			}
-			// 2. create desc in to child cluster namespace
-			newDesc := utils.ConstructDescriptionFromExistOne(desc)
-			newDesc.Namespace = itemCluster.Namespace
-			_, err := sched.localGaiaClient.AppsV1alpha1().Descriptions(itemCluster.Namespace).Create(ctx, newDesc, metav1.CreateOptions{})
-			if err != nil {
-				klog.V(3).InfoS("scheduler success, but desc not created success in sub child cluster.", err)
-			}
+		for rbIndex, itemRb := range scheduleResult.ResourceBindings {
+			itemRb.Name = fmt.Sprintf("%s-rs-%d", desc.Name, rbIndex)
+		}
+		err = sched.bind(schedulingCycleCtx, fwk, desc, scheduleResult.ResourceBindings, mcls.Items, generation)
+		if conflicted(sched.parentSchedulingQueue, desc, err) {
+			return
 		}
+		if err != nil {
+			sched.recordParentSchedulingFailure(info, desc, err, SchedulerError)
+			setSchedulingStatus(desc, scheduleResult, err, SchedulerError)
+			desc.Status.Phase = appsapi.DescriptionPhaseFailure
+			sched.parentGaiaClient.AppsV1alpha1().Descriptions(sched.dedicatedNamespace).UpdateStatus(ctx, desc, metav1.UpdateOptions{})
+			klog.Warningf("scheduler failed to bind %v", err)
+			return
+		}
+		setSchedulingStatus(desc, scheduleResult, nil, ReasonScheduled)
 	}
 
 	sched.parentGaiaClient.AppsV1alpha1().ResourceBindings(sched.dedicatedNamespace).
@@ -504,25 +579,126 @@ func (sched *Scheduler) RunParentScheduler(ctx context.Context) {
 	// TODO check if failed
 	sched.parentGaiaClient.AppsV1alpha1().Descriptions(sched.dedicatedNamespace).UpdateStatus(ctx, desc, metav1.UpdateOptions{})
 	metrics.SchedulingAlgorithmLatency.Observe(metrics.SinceInSeconds(start))
-	metrics.DescriptionScheduled(sched.framework.ProfileName(), metrics.SinceInSeconds(start))
+	metrics.DescriptionScheduled(fwk.ProfileName(), metrics.SinceInSeconds(start))
 	klog.Info("scheduler success")
 }
 
+// preempt runs the PostFilter plugins of fwk for a description that failed to schedule,
+// and records the clusters nominated for it once lower priority descriptions were preempted.
+func (sched *Scheduler) preempt(ctx context.Context, fwk framework.Framework, desc *appsapi.Description, scheduleErr error) {
+	if !fwk.HasPostFilterPlugins() {
+		return
+	}
+	statusMap := framework.ClusterToStatusMap{}
+	var fitErr *framework.FitError
+	if errors.As(scheduleErr, &fitErr) {
+		statusMap = fitErr.Diagnosis.ClusterToStatusMap
+	} else if !errors.Is(scheduleErr, algorithm.ErrInfeasiblePlan) {
+		return
+	}
+
+	result, sts := fwk.RunPostFilterPlugins(ctx, desc, statusMap)
+	if !sts.IsSuccess() {
+		klog.V(3).InfoS("Preemption didn't make description schedulable", "description", klog.KObj(desc), "status", sts.Message())
+		return
+	}
+	if result != nil && len(result.NominatedNamespacedClusters) > 0 {
+		desc.Status.NominatedClusters = result.NominatedNamespacedClusters
+		sched.eventRecorder.Eventf(desc, corev1.EventTypeNormal, "Nominated",
+			"Preempted lower priority descriptions on clusters %v", result.NominatedNamespacedClusters)
+	}
+}
+
+// bind assumes the resources of rbs are taken in the scheduler cache, then runs the reserve, permit and binding
+// extension points of fwk, which write the scheduled ResourceBindings and the description into the namespaces
+// of the child clusters. The assumption is forgotten if binding fails, the resources of the preoccupying
+// components stay reserved until their preoccupy expires if it succeeds. generation is the generation of the
+// cache desc was scheduled against, if another worker has taken the resources since then nothing is bound.
+func (sched *Scheduler) bind(ctx context.Context, fwk framework.Framework, desc *appsapi.Description,
+	rbs []*appsapi.ResourceBinding, mcls []platformapi.ManagedCluster, generation int64) error {
+	clusters := make([]*platformapi.ManagedCluster, 0, len(mcls))
+	for i := range mcls {
+		clusters = append(clusters, &mcls[i])
+	}
+
+	key := klog.KObj(desc).String()
+	if err := sched.schedulerCache.AssumeDescription(key, algorithm.AssumedRequests(desc, rbs,
+		sched.schedulerCache.GetSelfClusterName()), generation); err != nil {
+		return err
+	}
+	if err := sched.runBindingCycle(ctx, fwk, desc, rbs, clusters); err != nil {
+		if forgetErr := sched.schedulerCache.ForgetDescription(key); forgetErr != nil {
+			klog.ErrorS(forgetErr, "Scheduler cache ForgetDescription failed")
+		}
+		return err
+	}
+	if err := sched.schedulerCache.FinishBinding(key); err != nil {
+		klog.ErrorS(err, "Scheduler cache FinishBinding failed")
+	}
+	if requests, duration := algorithm.PreoccupiedRequests(desc, rbs, sched.schedulerCache.GetSelfClusterName()); duration > 0 {
+		if err := sched.schedulerCache.PreoccupyDescription(key, requests, time.Now().Add(duration)); err != nil {
+			klog.ErrorS(err, "Scheduler cache PreoccupyDescription failed")
+		}
+	}
+	return nil
+}
+
+// runBindingCycle runs the reserve, permit and binding extension points of fwk.
+func (sched *Scheduler) runBindingCycle(ctx context.Context, fwk framework.Framework, desc *appsapi.Description,
+	rbs []*appsapi.ResourceBinding, clusters []*platformapi.ManagedCluster) error {
+	// Run the Reserve method of reserve plugins.
+	if sts := fwk.RunReservePluginsReserve(ctx, desc, rbs); !sts.IsSuccess() {
+		// trigger un-reserve to clean up state associated with the reserved description
+		fwk.RunReservePluginsUnreserve(ctx, desc, rbs)
+		return sts.AsError()
+	}
+
+	// Run "permit" plugins.
+	if sts := fwk.RunPermitPlugins(ctx, desc, rbs); !sts.IsSuccess() && sts.Code() != framework.Wait {
+		fwk.RunReservePluginsUnreserve(ctx, desc, rbs)
+		return sts.AsError()
+	}
+	if sts := fwk.WaitOnPermit(ctx, desc); !sts.IsSuccess() {
+		fwk.RunReservePluginsUnreserve(ctx, desc, rbs)
+		return sts.AsError()
+	}
+
+	// Run "prebind" plugins.
+	if sts := fwk.RunPreBindPlugins(ctx, desc, rbs); !sts.IsSuccess() {
+		fwk.RunReservePluginsUnreserve(ctx, desc, rbs)
+		return sts.AsError()
+	}
+
+	sts := fwk.RunBindPlugins(ctx, desc, rbs, clusters)
+	if sts.Code() == framework.Skip {
+		fwk.RunReservePluginsUnreserve(ctx, desc, rbs)
+		return fmt.Errorf("bind plugins skipped description %q", klog.KObj(desc))
+	}
+	if !sts.IsSuccess() {
+		fwk.RunReservePluginsUnreserve(ctx, desc, rbs)
+		return sts.AsError()
+	}
+
+	// Run "postbind" plugins.
+	fwk.RunPostBindPlugins(ctx, desc, rbs, clusters)
+	return nil
+}
+
 // RunParentReScheduler run reschedule in agent cluster.
 func (sched *Scheduler) RunParentReScheduler(ctx context.Context) {
 	klog.Info("start to re schedule one description...")
 	defer klog.Info("finish re schedule a description")
-	key, shutdown := sched.parentSchedulingRetryQueue.Get()
+	info, shutdown := sched.parentSchedulingRetryQueue.Pop()
 	if shutdown {
 		klog.Error("failed to get next unscheduled description from closed retry queue")
 		return
 	}
-	defer sched.parentSchedulingRetryQueue.Done(key)
+	defer sched.parentSchedulingRetryQueue.Done(info.Key)
 
 	// Convert the namespace/name string into a distinct namespace and name
-	ns, name, err := cache.SplitMetaNamespaceKey(key.(string))
+	ns, name, err := cache.SplitMetaNamespaceKey(info.Key)
 	if err != nil {
-		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", info.Key))
 		return
 	}
 
@@ -531,7 +707,18 @@ func (sched *Scheduler) RunParentReScheduler(ctx context.Context) {
 		utilruntime.HandleError(err)
 		return
 	}
+	if desc.DeletionTimestamp != nil {
+		klog.V(4).InfoS("Skipping description being deleted", "description", klog.KObj(desc))
+		return
+	}
+	// the description of the lister is shared with the informer cache and the other workers.
+	desc = desc.DeepCopy()
 	klog.InfoS("Attempting to re schedule description", "description", klog.KObj(desc))
+	fwk, err := sched.profiles.frameworkForDescription(desc)
+	if err != nil {
+		klog.ErrorS(err, "Error occurred", "description", klog.KObj(desc))
+		return
+	}
 
 	// Synchronously attempt to find a fit for the description.
 	start := time.Now()
@@ -543,47 +730,73 @@ func (sched *Scheduler) RunParentReScheduler(ctx context.Context) {
 	requirement, _ := labels.NewRequirement(common.GaiaDescriptionLabel, selection.Equals, []string{desc.Name})
 	labelSelector = labelSelector.Add(*requirement)
 	// get rbs we need only one that is the selected one.
-	rbList, _ := sched.parentGaiaClient.AppsV1alpha1().ResourceBindings(known.GaiaRBMergedReservedNamespace).List(ctx, metav1.ListOptions{
+	rbList, err := sched.parentGaiaClient.AppsV1alpha1().ResourceBindings(known.GaiaRBMergedReservedNamespace).List(ctx, metav1.ListOptions{
 		LabelSelector: labelSelector.String(),
 	})
-	rbs := make([]*appsapi.ResourceBinding, 0)
-	rbs = append(rbs, &rbList.Items[0])
+	if err != nil {
+		sched.recordParentReSchedulingFailure(info, desc, err, SchedulerError)
+		return
+	}
+	selected := selectedResourceBinding(rbList.Items)
+	if selected == nil {
+		sched.recordParentReSchedulingFailure(info, desc,
+			fmt.Errorf("no selected resource binding of description %s", klog.KObj(desc)), SchedulerError)
+		return
+	}
+	rbs := []*appsapi.ResourceBinding{selected}
 
 	mcls, _ := sched.localGaiaClient.PlatformV1alpha1().ManagedClusters(corev1.NamespaceAll).List(ctx, metav1.ListOptions{})
 	if len(mcls.Items) > 0 {
-		scheduleResult, err := sched.scheduleAlgorithm.Schedule(schedulingCycleCtx, sched.framework, rbs, desc)
+		scheduleResult, err := sched.scheduleAlgorithm.Schedule(schedulingCycleCtx, fwk, rbs, desc)
 		if err != nil {
-			sched.recordParentReSchedulingFailure(desc, err, ReasonUnschedulable)
+			sched.recordParentReSchedulingFailure(info, desc, err, ReasonUnschedulable)
 			return
 		}
 		localRB, err := sched.localGaiaClient.AppsV1alpha1().ResourceBindings(known.GaiaRBMergedReservedNamespace).List(ctx, metav1.ListOptions{
 			LabelSelector: labelSelector.String(),
 		})
 		if err != nil {
-			sched.recordParentReSchedulingFailure(desc, err, ReasonUnschedulable)
+			sched.recordParentReSchedulingFailure(info, desc, err, SchedulerError)
+			return
+		}
+		localSelected := selectedResourceBinding(localRB.Items)
+		if localSelected == nil {
+			sched.recordParentReSchedulingFailure(info, desc,
+				fmt.Errorf("no selected merged resource binding of description %s", klog.KObj(desc)), SchedulerError)
 			return
 		}
 		for _, rbapp := range scheduleResult.ResourceBindings[0].Spec.RbApps {
 			rbItemApp := rbapp.DeepCopy()
 			if rbItemApp.ClusterName == sched.selfClusterName {
-				localRB.Items[0].Spec.RbApps = rbItemApp.Children
+				localSelected.Spec.RbApps = rbItemApp.Children
 				rb, err := sched.localGaiaClient.AppsV1alpha1().ResourceBindings(known.GaiaRBMergedReservedNamespace).
-					Update(ctx, &localRB.Items[0], metav1.UpdateOptions{})
+					Update(ctx, localSelected, metav1.UpdateOptions{})
 				if err != nil {
 					klog.InfoS("scheduler success, but rb not update success", rb)
 				}
 				break
 			}
 		}
+		setSchedulingStatus(desc, scheduleResult, nil, ReasonScheduled)
 	}
 
 	desc.Status.Phase = appsapi.DescriptionPhaseScheduled
 	sched.parentGaiaClient.AppsV1alpha1().Descriptions(sched.dedicatedNamespace).UpdateStatus(ctx, desc, metav1.UpdateOptions{})
 	metrics.SchedulingAlgorithmLatency.Observe(metrics.SinceInSeconds(start))
-	metrics.DescriptionScheduled(sched.framework.ProfileName(), metrics.SinceInSeconds(start))
+	metrics.DescriptionScheduled(fwk.ProfileName(), metrics.SinceInSeconds(start))
 	klog.Info("scheduler success")
 }
 
+// selectedResourceBinding returns the resource binding of rbs chosen for deployment, or nil if there is none.
+func selectedResourceBinding(rbs []appsapi.ResourceBinding) *appsapi.ResourceBinding {
+	for i := range rbs {
+		if rbs[i].Spec.StatusScheduler == appsapi.ResourceBindingSelected {
+			return &rbs[i]
+		}
+	}
+	return nil
+}
+
 func (sched *Scheduler) SetparentDedicatedConfig(ctx context.Context) {
 	// complete your controller loop here
 	klog.Info("start set parent DedicatedKubeConfig current cluster as a child cluster...")
@@ -641,36 +854,57 @@ func (sched *Scheduler) GetDedicatedNamespace() string {
 
 // recordSchedulingFailure records an event for the subscription that indicates the
 // subscription has failed to schedule. Also, update the subscription condition.
-func (sched *Scheduler) recordSchedulingFailure(sub *appsapi.Description, err error, _ string) {
+func (sched *Scheduler) recordSchedulingFailure(info *internalqueue.QueuedDescriptionInfo, sub *appsapi.Description, err error, reason string) {
 	klog.V(2).InfoS("Unable to schedule subscription; waiting", "subscription", klog.KObj(sub), "err", err)
 
 	msg := truncateMessage(err.Error())
-	sched.framework.EventRecorder().Event(sub, corev1.EventTypeWarning, "FailedScheduling", msg)
+	sched.eventRecorder.Event(sub, corev1.EventTypeWarning, "FailedScheduling", msg)
 
 	// re-added to the queue for re-processing
-	sched.localSchedulingQueue.AddRateLimited(klog.KObj(sub).String())
+	requeue(sched.localSchedulingQueue, info, reason)
 }
 
 // recordSchedulingFailure records an event for the subscription that indicates the
 // Description has failed to schedule. Also, update the subscription condition.
-func (sched *Scheduler) recordParentSchedulingFailure(sub *appsapi.Description, err error, _ string) {
+func (sched *Scheduler) recordParentSchedulingFailure(info *internalqueue.QueuedDescriptionInfo, sub *appsapi.Description, err error, reason string) {
 	klog.V(2).InfoS("Unable to schedule Description; waiting", "Description", klog.KObj(sub), "err", err)
 
 	msg := truncateMessage(err.Error())
-	sched.framework.EventRecorder().Event(sub, corev1.EventTypeWarning, "FailedScheduling", msg)
+	sched.eventRecorder.Event(sub, corev1.EventTypeWarning, "FailedScheduling", msg)
 	// re-added to the queue for re-processing
-	sched.parentSchedulingQueue.AddRateLimited(klog.KObj(sub).String())
+	requeue(sched.parentSchedulingQueue, info, reason)
 }
 
 // recordParentReSchedulingFailure records an event for the description that indicates the
 // Description has failed to re schedule. Also, update the description condition.
-func (sched *Scheduler) recordParentReSchedulingFailure(sub *appsapi.Description, err error, reason string) {
+func (sched *Scheduler) recordParentReSchedulingFailure(info *internalqueue.QueuedDescriptionInfo, sub *appsapi.Description, err error, reason string) {
 	klog.V(2).InfoS("Unable to re schedule Description; waiting", "Description", klog.KObj(sub), "err", err)
 
 	msg := truncateMessage(err.Error())
-	sched.framework.EventRecorder().Event(sub, corev1.EventTypeWarning, reason, msg)
+	sched.eventRecorder.Event(sub, corev1.EventTypeWarning, reason, msg)
 	// re-added to the queue for re-processing
-	sched.parentSchedulingRetryQueue.AddRateLimited(klog.KObj(sub).String())
+	requeue(sched.parentSchedulingRetryQueue, info, reason)
+}
+
+// conflicted returns true if err is a scheduling conflict. desc is then added to q again, so it is scheduled
+// against the updated cache as soon as the attempt is done, and no failure is recorded.
+func conflicted(q internalqueue.SchedulingQueue, desc *appsapi.Description, err error) bool {
+	if !errors.Is(err, schedulercache.ErrSchedulingConflict) {
+		return false
+	}
+	klog.V(3).InfoS("Scheduling description again", "description", klog.KObj(desc), "reason", err)
+	q.Add(desc)
+	return true
+}
+
+// requeue puts a description that failed to schedule back into q. A description the clusters can't hold waits
+// for a cluster event in the unschedulable pool, one that failed for an internal error backs off.
+func requeue(q internalqueue.SchedulingQueue, info *internalqueue.QueuedDescriptionInfo, reason string) {
+	if reason == SchedulerError {
+		q.AddBackoff(info)
+		return
+	}
+	q.AddUnschedulable(info)
 }
 
 // addLocalAllEventHandlers is a helper function used in Scheduler
@@ -687,8 +921,7 @@ func (sched *Scheduler) addLocalAllEventHandlers() {
 					return false
 				}
 				if len(desc.Status.Phase) == 0 || desc.Status.Phase == appsapi.DescriptionPhasePending || desc.Status.Phase == appsapi.DescriptionPhaseFailure {
-					// TODO: filter scheduler name
-					return true
+					return sched.profiles.HandlesDescription(desc)
 				} else {
 					sched.lockParent.Lock()
 					defer sched.lockParent.Unlock()
@@ -710,7 +943,7 @@ func (sched *Scheduler) addLocalAllEventHandlers() {
 				sub := obj.(*appsapi.Description)
 				sched.lockLocal.Lock()
 				defer sched.lockLocal.Unlock()
-				sched.localSchedulingQueue.AddRateLimited(klog.KObj(sub).String())
+				sched.localSchedulingQueue.Add(sub)
 			},
 			UpdateFunc: func(oldObj, newObj interface{}) {
 				oldSub := oldObj.(*appsapi.Description)
@@ -724,7 +957,7 @@ func (sched *Scheduler) addLocalAllEventHandlers() {
 
 				sched.lockLocal.Lock()
 				defer sched.lockLocal.Unlock()
-				sched.localSchedulingQueue.AddRateLimited(klog.KObj(newSub).String())
+				sched.localSchedulingQueue.Add(newSub)
 			},
 		},
 	})
@@ -745,8 +978,7 @@ func (sched *Scheduler) addParentAllEventHandlers() {
 					return false
 				}
 				if len(desc.Status.Phase) == 0 || desc.Status.Phase == appsapi.DescriptionPhasePending {
-					// TODO: filter scheduler name
-					return true
+					return sched.profiles.HandlesDescription(desc)
 				} else {
 					sched.lockParent.Lock()
 					defer sched.lockParent.Unlock()
@@ -769,7 +1001,7 @@ func (sched *Scheduler) addParentAllEventHandlers() {
 				sub := obj.(*appsapi.Description)
 				sched.lockParent.Lock()
 				defer sched.lockParent.Unlock()
-				sched.parentSchedulingQueue.AddRateLimited(klog.KObj(sub).String())
+				sched.parentSchedulingQueue.Add(sub)
 			},
 			UpdateFunc: func(oldObj, newObj interface{}) {
 				oldDesc := oldObj.(*appsapi.Description)
@@ -782,7 +1014,7 @@ func (sched *Scheduler) addParentAllEventHandlers() {
 				}
 				sched.lockParent.Lock()
 				defer sched.lockParent.Unlock()
-				sched.parentSchedulingQueue.AddRateLimited(klog.KObj(newDesc).String())
+				sched.parentSchedulingQueue.Add(newDesc)
 			},
 		},
 	})
@@ -799,7 +1031,7 @@ func (sched *Scheduler) addParentAllEventHandlers() {
 					return false
 				}
 				if desc.Status.Phase == appsapi.DescriptionPhaseReSchedule {
-					return true
+					return sched.profiles.HandlesDescription(desc)
 				} else {
 					sched.lockReschedule.Lock()
 					defer sched.lockReschedule.Unlock()
@@ -822,7 +1054,7 @@ func (sched *Scheduler) addParentAllEventHandlers() {
 				sub := obj.(*appsapi.Description)
 				sched.lockReschedule.Lock()
 				defer sched.lockReschedule.Unlock()
-				sched.parentSchedulingRetryQueue.AddRateLimited(klog.KObj(sub).String())
+				sched.parentSchedulingRetryQueue.Add(sub)
 			},
 			UpdateFunc: func(oldObj, newObj interface{}) {
 				oldDesc := oldObj.(*appsapi.Description)
@@ -835,7 +1067,7 @@ func (sched *Scheduler) addParentAllEventHandlers() {
 				}
 				sched.lockReschedule.Lock()
 				defer sched.lockReschedule.Unlock()
-				sched.parentSchedulingRetryQueue.AddRateLimited(klog.KObj(newDesc).String())
+				sched.parentSchedulingRetryQueue.Add(newDesc)
 			},
 		},
 	})
@@ -0,0 +1,186 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apiserver/pkg/server/mux"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	appsapi "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	"github.com/lmxia/gaia/pkg/scheduler/algorithm"
+)
+
+const (
+	// SimulationPath is the path of the dry-run scheduling endpoint on the secure serving mux.
+	SimulationPath = "/simulate"
+
+	// maxSimulationBodyBytes limits the size of a simulation request.
+	maxSimulationBodyBytes = 3 * 1024 * 1024
+)
+
+// simulate runs a dry-run scheduling of the description in sim against the current scheduler cache,
+// and returns a copy of sim with the candidate resource bindings in its status.
+func (sched *Scheduler) simulate(ctx context.Context, sim *appsapi.SchedulingSimulation) *appsapi.SchedulingSimulation {
+	sim = sim.DeepCopy()
+	sim.Status = appsapi.SchedulingSimulationStatus{ObservedGeneration: sim.Generation}
+
+	desc := &appsapi.Description{
+		ObjectMeta: metav1.ObjectMeta{Name: sim.Name, Namespace: sim.Namespace, UID: sim.UID},
+		Spec:       sim.Spec.Description,
+	}
+	var nwr *appsapi.NetworkRequirement
+	if sim.Spec.NetworkRequirement != nil {
+		nwr = &appsapi.NetworkRequirement{
+			ObjectMeta: metav1.ObjectMeta{Name: sim.Name, Namespace: sim.Namespace},
+			Spec:       *sim.Spec.NetworkRequirement,
+		}
+	}
+
+	fwk, err := sched.profiles.frameworkForDescription(desc)
+	if err != nil {
+		sim.Status.Phase = appsapi.SimulationPhaseFailed
+		sim.Status.Reason = err.Error()
+		return sim
+	}
+
+	result, err := sched.scheduleAlgorithm.Simulate(ctx, fwk, desc, nwr)
+	sim.Status.ComponentDiagnoses = componentDiagnoses(desc, result)
+	sim.Status.Candidates = simulatedResourceBindings(result)
+	if err != nil {
+		sim.Status.Phase = appsapi.SimulationPhaseFailed
+		sim.Status.Reason = truncateMessage(err.Error())
+		return sim
+	}
+	sim.Status.Phase = appsapi.SimulationPhaseSucceeded
+	return sim
+}
+
+// simulatedResourceBindings converts the candidates of result along with their scores, the candidates
+// are ordered by score from high to low.
+func simulatedResourceBindings(result algorithm.ScheduleResult) []appsapi.SimulatedResourceBinding {
+	selected := make(map[*appsapi.ResourceBinding]bool, len(result.ResourceBindings))
+	for _, rb := range result.ResourceBindings {
+		selected[rb] = true
+	}
+
+	candidates := make([]appsapi.SimulatedResourceBinding, 0, len(result.Candidates))
+	for i, rb := range result.Candidates {
+		item := rb.DeepCopy()
+		candidate := appsapi.SimulatedResourceBinding{
+			RbApps:      item.Spec.RbApps,
+			NetworkPath: item.Spec.NetworkPath,
+			Selected:    selected[rb],
+		}
+		for plugin, scores := range result.PluginScores {
+			if i >= len(scores) {
+				continue
+			}
+			if candidate.PluginScores == nil {
+				candidate.PluginScores = make(map[string]int64, len(result.PluginScores))
+			}
+			candidate.PluginScores[plugin] = scores[i].Score
+			candidate.Score += scores[i].Score
+		}
+		candidates = append(candidates, candidate)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	return candidates
+}
+
+// installSimulationHandler serves SimulationPath, which takes a SchedulingSimulation in the request body
+// and responds with it, its status filled by a dry-run scheduling.
+func installSimulationHandler(pathRecorderMux *mux.PathRecorderMux, sched *Scheduler) {
+	pathRecorderMux.HandleFunc(SimulationPath, func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, fmt.Sprintf("method %s is not allowed", req.Method), http.StatusMethodNotAllowed)
+			return
+		}
+		sim := &appsapi.SchedulingSimulation{}
+		decoder := json.NewDecoder(http.MaxBytesReader(w, req.Body, maxSimulationBodyBytes))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(sim); err != nil {
+			http.Error(w, fmt.Sprintf("failed to decode SchedulingSimulation: %v", err), http.StatusBadRequest)
+			return
+		}
+		if len(sim.Name) == 0 {
+			sim.Name = "simulation"
+		}
+
+		sim = sched.simulate(req.Context(), sim)
+		sim.APIVersion = appsapi.SchemeGroupVersion.String()
+		sim.Kind = "SchedulingSimulation"
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(sim); err != nil {
+			klog.ErrorS(err, "Failed to write simulation response")
+		}
+	})
+}
+
+// RunSimulation simulates the next SchedulingSimulation in the queue and writes the result into its status.
+func (sched *Scheduler) RunSimulation(ctx context.Context) {
+	key, shutdown := sched.simulationQueue.Get()
+	if shutdown {
+		klog.Error("failed to get next scheduling simulation from closed queue")
+		return
+	}
+	defer sched.simulationQueue.Done(key)
+
+	ns, name, err := cache.SplitMetaNamespaceKey(key.(string))
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		sched.simulationQueue.Forget(key)
+		return
+	}
+	sim, err := sched.simulationLister.SchedulingSimulations(ns).Get(name)
+	if err != nil {
+		// the simulation has been deleted.
+		sched.simulationQueue.Forget(key)
+		return
+	}
+	if sim.DeletionTimestamp != nil || !simulationOutdated(sim) {
+		sched.simulationQueue.Forget(key)
+		return
+	}
+
+	klog.V(4).InfoS("Attempting to simulate scheduling", "simulation", klog.KObj(sim))
+	result := sched.simulate(ctx, sim)
+	if _, err = sched.localGaiaClient.AppsV1alpha1().SchedulingSimulations(ns).UpdateStatus(ctx, result, metav1.UpdateOptions{}); err != nil {
+		klog.ErrorS(err, "Failed to update scheduling simulation status", "simulation", klog.KObj(sim))
+		sched.simulationQueue.AddRateLimited(key)
+		return
+	}
+	sched.simulationQueue.Forget(key)
+}
+
+// simulationOutdated returns true if the status of sim is not computed for its latest spec.
+func simulationOutdated(sim *appsapi.SchedulingSimulation) bool {
+	return len(sim.Status.Phase) == 0 || sim.Status.ObservedGeneration != sim.Generation
+}
+
+// addSimulationEventHandlers enqueues the SchedulingSimulations whose status is outdated.
+func (sched *Scheduler) addSimulationEventHandlers() {
+	sched.localGaiaAllFactory.Apps().V1alpha1().SchedulingSimulations().Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			sim, ok := obj.(*appsapi.SchedulingSimulation)
+			return ok && sim.DeletionTimestamp == nil && simulationOutdated(sim)
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				sched.simulationQueue.Add(klog.KObj(obj.(*appsapi.SchedulingSimulation)).String())
+			},
+			UpdateFunc: func(_, newObj interface{}) {
+				sched.simulationQueue.Add(klog.KObj(newObj.(*appsapi.SchedulingSimulation)).String())
+			},
+		},
+	})
+}
@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/server/mux"
+
+	appsapi "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	"github.com/lmxia/gaia/pkg/scheduler/algorithm"
+	schedulerapis "github.com/lmxia/gaia/pkg/scheduler/apis"
+	framework "github.com/lmxia/gaia/pkg/scheduler/framework/interfaces"
+	frameworkruntime "github.com/lmxia/gaia/pkg/scheduler/framework/runtime"
+)
+
+// fakeAlgorithm returns a fixed result on simulation, and records what it is asked to simulate.
+type fakeAlgorithm struct {
+	result algorithm.ScheduleResult
+	err    error
+	desc   *appsapi.Description
+	nwr    *appsapi.NetworkRequirement
+}
+
+func (f *fakeAlgorithm) Schedule(context.Context, framework.Framework, []*appsapi.ResourceBinding, *appsapi.Description) (algorithm.ScheduleResult, error) {
+	return algorithm.ScheduleResult{}, errors.New("unexpected schedule")
+}
+
+func (f *fakeAlgorithm) Simulate(_ context.Context, _ framework.Framework, desc *appsapi.Description, nwr *appsapi.NetworkRequirement) (algorithm.ScheduleResult, error) {
+	f.desc, f.nwr = desc, nwr
+	return f.result, f.err
+}
+
+func (f *fakeAlgorithm) SetSelfClusterName(string) {}
+
+type SimulationSuite struct {
+	algorithm *fakeAlgorithm
+	sched     *Scheduler
+	suite.Suite
+}
+
+func (suite *SimulationSuite) SetupTest() {
+	rb1 := &appsapi.ResourceBinding{Spec: appsapi.ResourceBindingSpec{
+		RbApps:      []*appsapi.ResourceBindingApps{{ClusterName: "c1", Replicas: map[string]int32{"web": 2}}},
+		NetworkPath: [][]byte{[]byte("path1")},
+	}}
+	rb2 := &appsapi.ResourceBinding{Spec: appsapi.ResourceBindingSpec{
+		RbApps: []*appsapi.ResourceBindingApps{{ClusterName: "c2", Replicas: map[string]int32{"web": 2}}},
+	}}
+	suite.algorithm = &fakeAlgorithm{result: algorithm.ScheduleResult{
+		ResourceBindings: []*appsapi.ResourceBinding{rb2},
+		Candidates:       []*appsapi.ResourceBinding{rb1, rb2},
+		PluginScores: framework.PluginToRBScores{
+			"CorePriority": {{Index: 0, Score: 10}, {Index: 1, Score: 30}},
+			"VirtualNode":  {{Index: 0, Score: 5}, {Index: 1, Score: 0}},
+		},
+	}}
+
+	fwk, err := frameworkruntime.NewFramework(frameworkruntime.Registry{}, &schedulerapis.SchedulerProfile{
+		SchedulerName: schedulerapis.DefaultSchedulerName,
+		Plugins:       &schedulerapis.Plugins{},
+	})
+	suite.NoError(err)
+	suite.sched = &Scheduler{
+		scheduleAlgorithm: suite.algorithm,
+		profiles:          profileMap{schedulerapis.DefaultSchedulerName: fwk},
+	}
+}
+
+func (suite *SimulationSuite) newSimulation() *appsapi.SchedulingSimulation {
+	return &appsapi.SchedulingSimulation{
+		ObjectMeta: metav1.ObjectMeta{Name: "what-if", Namespace: "default", Generation: 2},
+		Spec: appsapi.SchedulingSimulationSpec{
+			Description:        appsapi.DescriptionSpec{AppID: "app", Components: []appsapi.Component{{Name: "web"}}},
+			NetworkRequirement: &appsapi.NetworkRequirementSpec{},
+		},
+	}
+}
+
+func (suite *SimulationSuite) TestSimulate() {
+	sim := suite.sched.simulate(context.TODO(), suite.newSimulation())
+
+	suite.Equal(appsapi.SimulationPhaseSucceeded, sim.Status.Phase)
+	suite.Equal(int64(2), sim.Status.ObservedGeneration)
+	suite.Equal("what-if", suite.algorithm.desc.Name)
+	suite.Equal("app", suite.algorithm.desc.Spec.AppID)
+	suite.NotNil(suite.algorithm.nwr)
+
+	suite.Len(sim.Status.Candidates, 2)
+	suite.Equal("c2", sim.Status.Candidates[0].RbApps[0].ClusterName)
+	suite.Equal(int64(30), sim.Status.Candidates[0].Score)
+	suite.True(sim.Status.Candidates[0].Selected)
+	suite.Equal(map[string]int64{"CorePriority": 10, "VirtualNode": 5}, sim.Status.Candidates[1].PluginScores)
+	suite.Equal([][]byte{[]byte("path1")}, sim.Status.Candidates[1].NetworkPath)
+	suite.False(sim.Status.Candidates[1].Selected)
+}
+
+func (suite *SimulationSuite) TestSimulateUnknownProfile() {
+	sim := suite.newSimulation()
+	sim.Spec.Description.SchedulerName = "edge-scheduler"
+	sim = suite.sched.simulate(context.TODO(), sim)
+	suite.Equal(appsapi.SimulationPhaseFailed, sim.Status.Phase)
+	suite.Nil(suite.algorithm.desc)
+}
+
+func (suite *SimulationSuite) TestHandler() {
+	pathRecorderMux := mux.NewPathRecorderMux("test")
+	installSimulationHandler(pathRecorderMux, suite.sched)
+
+	body, err := json.Marshal(suite.newSimulation())
+	suite.NoError(err)
+	recorder := httptest.NewRecorder()
+	pathRecorderMux.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, SimulationPath, bytes.NewReader(body)))
+	suite.Equal(http.StatusOK, recorder.Code)
+	sim := &appsapi.SchedulingSimulation{}
+	suite.NoError(json.Unmarshal(recorder.Body.Bytes(), sim))
+	suite.Equal(appsapi.SimulationPhaseSucceeded, sim.Status.Phase)
+	suite.Len(sim.Status.Candidates, 2)
+
+	recorder = httptest.NewRecorder()
+	pathRecorderMux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, SimulationPath, nil))
+	suite.Equal(http.StatusMethodNotAllowed, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	pathRecorderMux.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, SimulationPath, bytes.NewReader([]byte(`{"spec":{"unknown":1}}`))))
+	suite.Equal(http.StatusBadRequest, recorder.Code)
+}
+
+func TestSimulationSuite(t *testing.T) {
+	suite.Run(t, new(SimulationSuite))
+}
@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsapi "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	"github.com/lmxia/gaia/pkg/scheduler/algorithm"
+)
+
+// maxRecordedFilteredResourceBindings limits how many candidates dropped by the network filter
+// are kept in the status of a description.
+const maxRecordedFilteredResourceBindings = 10
+
+// setSchedulingStatus records the outcome of the latest scheduling attempt of desc into its status:
+// the DescriptionScheduled condition, the clusters rejected for each component, the candidate
+// resource bindings dropped by the network filter and the states of the boundaries.
+// A nil err means desc has been scheduled.
+func setSchedulingStatus(desc *appsapi.Description, result algorithm.ScheduleResult, err error, reason string) {
+	if desc.Status.ObservedGeneration != desc.Generation {
+		// the failures of the gang are forgotten once its spec changes.
+		desc.Status.GangFailedClusters = nil
+	}
+	desc.Status.ObservedGeneration = desc.Generation
+	desc.Status.ComponentDiagnoses = componentDiagnoses(desc, result)
+	desc.Status.NetworkFilteredResourceBindings = filteredResourceBindings(result)
+	desc.Status.Boundaries = boundaryStatuses(result, err)
+
+	condition := metav1.Condition{
+		Type:               appsapi.DescriptionConditionScheduled,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: desc.Generation,
+		Reason:             ReasonScheduled,
+		Message:            fmt.Sprintf("%d resource bindings are published", len(result.ResourceBindings)),
+	}
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reason
+		condition.Message = truncateMessage(err.Error())
+	}
+	apimeta.SetStatusCondition(&desc.Status.Conditions, condition)
+}
+
+// componentDiagnoses converts the per component diagnoses of result, in the order of the components of desc.
+// Components without any rejected cluster are omitted.
+func componentDiagnoses(desc *appsapi.Description, result algorithm.ScheduleResult) []appsapi.ComponentDiagnosis {
+	var diagnoses []appsapi.ComponentDiagnosis
+	for _, comp := range desc.Spec.Components {
+		diagnosis, ok := result.Diagnoses[comp.Name]
+		if !ok || len(diagnosis.ClusterToStatusMap) == 0 {
+			continue
+		}
+		rejected := make([]appsapi.ClusterRejection, 0, len(diagnosis.ClusterToStatusMap))
+		for cluster, status := range diagnosis.ClusterToStatusMap {
+			rejected = append(rejected, appsapi.ClusterRejection{
+				Cluster: cluster,
+				Plugin:  status.FailedPlugin(),
+				Reasons: status.Reasons(),
+			})
+		}
+		sort.Slice(rejected, func(i, j int) bool {
+			return rejected[i].Cluster < rejected[j].Cluster
+		})
+		diagnoses = append(diagnoses, appsapi.ComponentDiagnosis{
+			Name:             comp.Name,
+			RejectedClusters: rejected,
+		})
+	}
+	return diagnoses
+}
+
+// filteredResourceBindings converts the candidates dropped by the network filter, ordered by their index.
+func filteredResourceBindings(result algorithm.ScheduleResult) []appsapi.FilteredResourceBinding {
+	indexes := make([]int, 0, len(result.NetworkFilteredResourceBindings))
+	for index := range result.NetworkFilteredResourceBindings {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	if len(indexes) > maxRecordedFilteredResourceBindings {
+		indexes = indexes[:maxRecordedFilteredResourceBindings]
+	}
+
+	var filtered []appsapi.FilteredResourceBinding
+	for _, index := range indexes {
+		rb := result.NetworkFilteredResourceBindings[index]
+		filtered = append(filtered, appsapi.FilteredResourceBinding{
+			Index:  int32(index),
+			RbApps: rb.DeepCopy().Spec.RbApps,
+		})
+	}
+	return filtered
+}
+
+// boundaryStatuses returns the states of the boundaries in result, the enforced boundaries are unmet
+// if desc is not placed.
+func boundaryStatuses(result algorithm.ScheduleResult, err error) []appsapi.BoundaryStatus {
+	var boundaries []appsapi.BoundaryStatus
+	for _, boundary := range result.Boundaries {
+		if err != nil && boundary.State == appsapi.BoundaryMet {
+			boundary.State = appsapi.BoundaryUnmet
+			boundary.Message = "the description is not placed"
+		}
+		boundaries = append(boundaries, boundary)
+	}
+	return boundaries
+}
@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	appsapi "github.com/lmxia/gaia/pkg/apis/apps/v1alpha1"
+	"github.com/lmxia/gaia/pkg/scheduler/algorithm"
+	framework "github.com/lmxia/gaia/pkg/scheduler/framework/interfaces"
+)
+
+type StatusSuite struct {
+	desc *appsapi.Description
+	suite.Suite
+}
+
+func (suite *StatusSuite) SetupTest() {
+	suite.desc = &appsapi.Description{
+		ObjectMeta: metav1.ObjectMeta{Name: "desc", Namespace: "gaia-reserved", Generation: 3},
+		Spec: appsapi.DescriptionSpec{
+			Components: []appsapi.Component{{Name: "web"}, {Name: "db"}, {Name: "cache"}},
+		},
+	}
+}
+
+func (suite *StatusSuite) TestFailure() {
+	result := algorithm.ScheduleResult{
+		Diagnoses: map[string]framework.Diagnosis{
+			"db": {
+				ClusterToStatusMap: framework.ClusterToStatusMap{
+					"gaia-c2/c2": framework.NewStatus(framework.UnschedulableAndUnresolvable, "taint").WithFailedPlugin("TaintToleration"),
+					"gaia-c1/c1": framework.NewStatus(framework.UnschedulableAndUnresolvable, "geo").WithFailedPlugin("GeoLocation"),
+				},
+				UnschedulablePlugins: sets.NewString("TaintToleration", "GeoLocation"),
+			},
+			"web": {ClusterToStatusMap: framework.ClusterToStatusMap{}},
+		},
+		NetworkFilteredResourceBindings: map[int]*appsapi.ResourceBinding{
+			2: {Spec: appsapi.ResourceBindingSpec{RbApps: []*appsapi.ResourceBindingApps{{ClusterName: "c2"}}}},
+			0: {Spec: appsapi.ResourceBindingSpec{RbApps: []*appsapi.ResourceBindingApps{{ClusterName: "c1"}}}},
+		},
+	}
+	setSchedulingStatus(suite.desc, result, errors.New("0/2 clusters are available"), ReasonUnschedulable)
+
+	suite.Equal(int64(3), suite.desc.Status.ObservedGeneration)
+	cond := apimeta.FindStatusCondition(suite.desc.Status.Conditions, appsapi.DescriptionConditionScheduled)
+	suite.NotNil(cond)
+	suite.Equal(metav1.ConditionFalse, cond.Status)
+	suite.Equal(ReasonUnschedulable, cond.Reason)
+	suite.Equal(int64(3), cond.ObservedGeneration)
+
+	suite.Equal([]appsapi.ComponentDiagnosis{
+		{
+			Name: "db",
+			RejectedClusters: []appsapi.ClusterRejection{
+				{Cluster: "gaia-c1/c1", Plugin: "GeoLocation", Reasons: []string{"geo"}},
+				{Cluster: "gaia-c2/c2", Plugin: "TaintToleration", Reasons: []string{"taint"}},
+			},
+		},
+	}, suite.desc.Status.ComponentDiagnoses)
+
+	suite.Len(suite.desc.Status.NetworkFilteredResourceBindings, 2)
+	suite.Equal(int32(0), suite.desc.Status.NetworkFilteredResourceBindings[0].Index)
+	suite.Equal("c1", suite.desc.Status.NetworkFilteredResourceBindings[0].RbApps[0].ClusterName)
+	suite.Equal(int32(2), suite.desc.Status.NetworkFilteredResourceBindings[1].Index)
+}
+
+func (suite *StatusSuite) TestSuccessClearsDiagnoses() {
+	setSchedulingStatus(suite.desc, algorithm.ScheduleResult{
+		Diagnoses: map[string]framework.Diagnosis{
+			"db": {ClusterToStatusMap: framework.ClusterToStatusMap{
+				"gaia-c1/c1": framework.NewStatus(framework.UnschedulableAndUnresolvable, "geo"),
+			}},
+		},
+	}, errors.New("failed"), ReasonUnschedulable)
+	suite.NotEmpty(suite.desc.Status.ComponentDiagnoses)
+
+	setSchedulingStatus(suite.desc, algorithm.ScheduleResult{
+		ResourceBindings: []*appsapi.ResourceBinding{{}, {}},
+	}, nil, ReasonScheduled)
+	suite.Empty(suite.desc.Status.ComponentDiagnoses)
+	suite.Empty(suite.desc.Status.NetworkFilteredResourceBindings)
+	suite.Len(suite.desc.Status.Conditions, 1)
+	suite.True(apimeta.IsStatusConditionTrue(suite.desc.Status.Conditions, appsapi.DescriptionConditionScheduled))
+	suite.Equal(ReasonScheduled, suite.desc.Status.Conditions[0].Reason)
+}
+
+func (suite *StatusSuite) TestBoundaries() {
+	result := algorithm.ScheduleResult{Boundaries: []appsapi.BoundaryStatus{
+		{Name: "delay", Subject: "web", Type: appsapi.BoundaryTypeDelay, State: appsapi.BoundaryMet},
+		{Name: "uptime", Subject: "web", Type: "availability", State: appsapi.BoundaryUnknown, Message: "not enforced"},
+	}}
+	setSchedulingStatus(suite.desc, result, nil, ReasonScheduled)
+	suite.Equal(result.Boundaries, suite.desc.Status.Boundaries)
+
+	setSchedulingStatus(suite.desc, result, errors.New("no network path"), ReasonUnschedulable)
+	suite.Equal(appsapi.BoundaryUnmet, suite.desc.Status.Boundaries[0].State)
+	suite.Equal(appsapi.BoundaryUnknown, suite.desc.Status.Boundaries[1].State)
+	suite.Equal(appsapi.BoundaryMet, result.Boundaries[0].State, "the result is not changed")
+}
+
+func TestStatusSuite(t *testing.T) {
+	suite.Run(t, new(StatusSuite))
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"time"
 
 	"encoding/json"
 	lmmserverless "github.com/SUMMERLm/serverless/api/v1"
@@ -323,12 +324,25 @@ func ApplyRBWorkloads(ctx context.Context, desc *appsv1alpha1.Description, paren
 	comToBeApply := desc.Spec.Components
 	errCh := make(chan error, len(comToBeApply))
 	for _, com := range comToBeApply {
+		// every sandbox but runc runs on the RuntimeClass named after it.
+		if sandbox := desc.SandboxOf(com.Name); len(sandbox) > 0 && sandbox != appsv1alpha1.Runc {
+			runtimeClassName := string(sandbox)
+			com.Module.Spec.RuntimeClassName = &runtimeClassName
+		}
 		switch com.Workload.Workloadtype {
 		case appsv1alpha1.WorkloadTypeDeployment:
 			unStructure, errDep := AssembledDeploymentStructure(&com, rb.Spec.RbApps, clusterName, desc.Name, false)
 			if errDep != nil || unStructure == nil || unStructure.Object == nil || len(unStructure.GetName()) == 0 {
 				continue
 			}
+			if preoccupy := desc.PreoccupyDuration(com.Name); preoccupy > 0 {
+				annotations := unStructure.GetAnnotations()
+				if annotations == nil {
+					annotations = make(map[string]string)
+				}
+				annotations[known.PreoccupyUntilAnnotation] = rb.CreationTimestamp.Add(preoccupy).UTC().Format(time.RFC3339)
+				unStructure.SetAnnotations(annotations)
+			}
 			wg.Add(1)
 			go func(unstructure *unstructured.Unstructured) {
 				defer wg.Done()
@@ -421,8 +435,12 @@ func ApplyRBWorkloads(ctx context.Context, desc *appsv1alpha1.Description, paren
 	return err
 }
 
+// ApplyResourceBinding applies the part of rb in cluster clusterName as a ResourceBinding of its child clusters and
+// binds the network path of the description if the cluster needs it, reserved tells the network whether the
+// description preoccupies its resources.
 func ApplyResourceBinding(ctx context.Context, localdynamicClient dynamic.Interface, discoveryRESTMapper meta.RESTMapper,
-	rb *appsv1alpha1.ResourceBinding, clusterName, descriptionName, networkBindUrl string, nwr *appsv1alpha1.NetworkRequirement) error {
+	rb *appsv1alpha1.ResourceBinding, clusterName, descriptionName, networkBindUrl string, nwr *appsv1alpha1.NetworkRequirement,
+	reserved bool) error {
 	var allErrs []error
 	var err error
 	errCh := make(chan error, len(rb.Spec.RbApps))
@@ -473,7 +491,7 @@ func ApplyResourceBinding(ctx context.Context, localdynamicClient dynamic.Interf
 
 			if len(newRB.Spec.NetworkPath) > 0 && len(networkBindUrl) > 0 && nwr != nil {
 				if NeedBindNetworkInCluster(rb.Spec.RbApps, clusterName, nwr) {
-					postRequest(networkBindUrl, descriptionName, newRB.Spec.NetworkPath[0])
+					postRequest(networkBindUrl, descriptionName, newRB.Spec.NetworkPath[0], reserved)
 				}
 			}
 		}
@@ -502,9 +520,9 @@ type NetworkScheme struct {
 	BuleprintID string `json:"buleprintID,omitempty"`
 }
 
-func postRequest(url, descriptionName string, path []byte) {
+func postRequest(url, descriptionName string, path []byte, reserved bool) {
 	networkScheme := NetworkScheme{
-		IsResouceReserved: false,
+		IsResouceReserved: reserved,
 		Path:              path,
 		BuleprintID:       descriptionName,
 	}